@@ -3,7 +3,13 @@ package handlers
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/ribgsilva/note-api/app/api/handlers/v1/healthcheck"
+	"github.com/ribgsilva/note-api/app/api/handlers/v1/links"
+	"github.com/ribgsilva/note-api/app/api/handlers/v1/notebooks"
 	"github.com/ribgsilva/note-api/app/api/handlers/v1/notes"
+	"github.com/ribgsilva/note-api/app/api/handlers/v1/revisions"
+	"github.com/ribgsilva/note-api/app/api/handlers/v1/shared"
+	"github.com/ribgsilva/note-api/app/api/handlers/v1/tags"
+	"github.com/ribgsilva/note-api/app/api/handlers/v1/trash"
 	"github.com/ribgsilva/note-api/platform/web/handler"
 )
 
@@ -11,6 +17,33 @@ func MapDefaults(r *gin.Engine) {
 	r.GET("/v1/healthcheck", handler.Wrapper(healthcheck.Get))
 }
 
-func MapApi(r *gin.Engine) {
+func MapApi(r gin.IRouter) {
+	r.GET("/v1/notes", handler.Wrapper(notes.List))
+	r.POST("/v1/notes", handler.Wrapper(notes.Create))
+	r.POST("/v1/notes/batch", handler.Wrapper(notes.Batch))
+	r.GET("/v1/notes/search", handler.Wrapper(notes.Search))
+	r.GET("/v1/notes/export", handler.Wrapper(notes.Export))
+	r.POST("/v1/notes/import", handler.Wrapper(notes.Import))
 	r.GET("/v1/notes/:id", handler.Wrapper(notes.Get))
+	r.PUT("/v1/notes/:id", handler.Wrapper(notes.Update))
+	r.PATCH("/v1/notes/:id", handler.Wrapper(notes.Patch))
+	r.DELETE("/v1/notes/:id", handler.Wrapper(notes.Delete))
+	r.POST("/v1/notes/:id/restore", handler.Wrapper(notes.Restore))
+	r.POST("/v1/notes/:id/move", handler.Wrapper(notes.Move))
+	r.GET("/v1/trash", handler.Wrapper(trash.List))
+	r.GET("/v1/notes/:id/revisions", handler.Wrapper(revisions.List))
+	r.GET("/v1/notes/:id/revisions/:rev", handler.Wrapper(revisions.Get))
+	r.GET("/v1/notes/:id/revisions/:rev/diff", handler.Wrapper(revisions.Diff))
+	r.POST("/v1/notes/:id/revisions/:rev/restore", handler.Wrapper(revisions.Restore))
+	r.POST("/v1/notes/:id/links", handler.Wrapper(links.Create))
+	r.GET("/v1/notes/:id/links", handler.Wrapper(links.List))
+	r.DELETE("/v1/notes/:id/links/:link", handler.Wrapper(links.Revoke))
+	r.GET("/v1/shared/:token", handler.Wrapper(shared.Get))
+	r.GET("/v1/tags", handler.Wrapper(tags.List))
+	r.POST("/v1/notebooks", handler.Wrapper(notebooks.Create))
+	r.GET("/v1/notebooks", handler.Wrapper(notebooks.List))
+	r.GET("/v1/notebooks/:id", handler.Wrapper(notebooks.Get))
+	r.DELETE("/v1/notebooks/:id", handler.Wrapper(notebooks.Delete))
+	r.POST("/v1/notebooks/:id/move", handler.Wrapper(notebooks.Move))
+	r.GET("/v1/notebooks/:id/notes", handler.Wrapper(notebooks.Notes))
 }
@@ -0,0 +1,76 @@
+package links
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/ribgsilva/note-api/business/v1/link"
+	"github.com/ribgsilva/note-api/platform/web/handler"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// Create godoc
+// @Summary Create a share link
+// @Description Create a signed read-only link to a note, optionally protected by password and limited by views
+// @Tags Link
+// @Accept json
+// @Produce json
+// @Param id path string true "Note id"
+// @Param link body link.NewLink false "Link options"
+// @Success 201 {object} link.Link
+// @Failure 400 {array} handler.Error
+// @Failure 404 {object} handler.Error
+// @Router /v1/notes/{id}/links [post]
+func Create(ctx *gin.Context) handler.Result {
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   []handler.Error{{Field: "id", Message: "invalid id"}},
+		}
+	}
+
+	var newL link.NewLink
+	if err := ctx.ShouldBindJSON(&newL); err != nil && !errors.Is(err, io.EOF) {
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   []handler.Error{{Message: "invalid body"}},
+		}
+	}
+
+	var errs []handler.Error
+	if newL.ExpiresIn < 0 {
+		errs = append(errs, handler.Error{Field: "expiresIn", Message: "must not be negative"})
+	}
+	if newL.MaxViews < 0 {
+		errs = append(errs, handler.Error{Field: "maxViews", Message: "must not be negative"})
+	}
+	if len(errs) > 0 {
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   errs,
+		}
+	}
+
+	created, err := link.Create(ctx, id, newL)
+
+	switch {
+	case errors.Is(err, link.ErrNoteNotFound):
+		return handler.Result{
+			Status: http.StatusNotFound,
+			Body:   handler.Error{Message: "notes not found"},
+		}
+	case err != nil:
+		return handler.Result{
+			Status: http.StatusInternalServerError,
+			Body:   handler.Error{Message: err.Error()},
+		}
+	default:
+		return handler.Result{
+			Status: http.StatusCreated,
+			Body:   created,
+		}
+	}
+}
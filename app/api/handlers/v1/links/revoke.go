@@ -0,0 +1,48 @@
+package links
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/ribgsilva/note-api/business/v1/link"
+	"github.com/ribgsilva/note-api/platform/web/handler"
+	"net/http"
+	"strconv"
+)
+
+// Revoke godoc
+// @Summary Revoke a share link
+// @Description Revoke a share link, so its token stops working immediately
+// @Tags Link
+// @Param id path string true "Note id"
+// @Param link path string true "Link id"
+// @Success 204
+// @Failure 400 {array} handler.Error
+// @Failure 404 {object} handler.Error
+// @Router /v1/notes/{id}/links/{link} [delete]
+func Revoke(ctx *gin.Context) handler.Result {
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   []handler.Error{{Field: "id", Message: "invalid id"}},
+		}
+	}
+
+	err = link.Revoke(ctx, id, ctx.Param("link"))
+
+	switch {
+	case errors.Is(err, link.ErrNotFound):
+		return handler.Result{
+			Status: http.StatusNotFound,
+			Body:   handler.Error{Message: "link not found"},
+		}
+	case err != nil:
+		return handler.Result{
+			Status: http.StatusInternalServerError,
+			Body:   handler.Error{Message: err.Error()},
+		}
+	default:
+		return handler.Result{Status: http.StatusNoContent}
+	}
+}
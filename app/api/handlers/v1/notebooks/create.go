@@ -0,0 +1,56 @@
+package notebooks
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/ribgsilva/note-api/business/v1/notebook"
+	"github.com/ribgsilva/note-api/platform/web/handler"
+	"net/http"
+)
+
+// Create godoc
+// @Summary Create a notebook
+// @Description Create a notebook in the root or inside a parent notebook
+// @Tags Notebook
+// @Accept json
+// @Produce json
+// @Param notebook body notebook.NewNotebook true "Notebook"
+// @Success 201 {object} notebook.Notebook
+// @Failure 400 {array} handler.Error
+// @Router /v1/notebooks [post]
+func Create(ctx *gin.Context) handler.Result {
+
+	var newN notebook.NewNotebook
+	if err := ctx.ShouldBindJSON(&newN); err != nil {
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   []handler.Error{{Message: "invalid body"}},
+		}
+	}
+	if errs := validate(newN); len(errs) > 0 {
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   errs,
+		}
+	}
+
+	created, err := notebook.Create(ctx, newN)
+
+	switch {
+	case errors.Is(err, notebook.ErrParentNotFound):
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   []handler.Error{{Field: "parentId", Message: err.Error()}},
+		}
+	case err != nil:
+		return handler.Result{
+			Status: http.StatusInternalServerError,
+			Body:   handler.Error{Message: err.Error()},
+		}
+	default:
+		return handler.Result{
+			Status: http.StatusCreated,
+			Body:   created,
+		}
+	}
+}
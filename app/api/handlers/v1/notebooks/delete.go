@@ -0,0 +1,65 @@
+package notebooks
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/ribgsilva/note-api/business/v1/notebook"
+	"github.com/ribgsilva/note-api/platform/web/handler"
+	"github.com/ribgsilva/note-api/platform/web/identity"
+	"net/http"
+	"strconv"
+)
+
+// Delete godoc
+// @Summary Delete a notebook
+// @Description Delete a notebook and its descendants. A notebook with notebooks or notes is only deleted with cascade=trash, which moves its notes to the trash
+// @Tags Notebook
+// @Param id path string true "Notebook id"
+// @Param cascade query string false "What to do with the contents" Enums(reject, trash) default(reject)
+// @Success 204
+// @Failure 400 {array} handler.Error
+// @Failure 404 {object} handler.Error
+// @Failure 409 {object} handler.Error
+// @Router /v1/notebooks/{id} [delete]
+func Delete(ctx *gin.Context) handler.Result {
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   []handler.Error{{Field: "id", Message: "invalid id"}},
+		}
+	}
+
+	cascade := ctx.DefaultQuery("cascade", "reject")
+	if cascade != "reject" && cascade != "trash" {
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   []handler.Error{{Field: "cascade", Message: "must be reject or trash"}},
+		}
+	}
+
+	deleted, err := notebook.Delete(ctx, id, cascade == "trash", identity.User(ctx))
+
+	switch {
+	case errors.Is(err, notebook.ErrNotEmpty):
+		return handler.Result{
+			Status: http.StatusConflict,
+			Body:   handler.Error{Message: err.Error()},
+		}
+	case err != nil:
+		return handler.Result{
+			Status: http.StatusInternalServerError,
+			Body:   handler.Error{Message: err.Error()},
+		}
+	case !deleted:
+		return handler.Result{
+			Status: http.StatusNotFound,
+			Body:   handler.Error{Message: "notebook not found"},
+		}
+	default:
+		return handler.Result{
+			Status: http.StatusNoContent,
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package notebooks
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ribgsilva/note-api/business/v1/notebook"
+	"github.com/ribgsilva/note-api/platform/web/handler"
+	"net/http"
+	"strconv"
+)
+
+// Get godoc
+// @Summary Get a notebook
+// @Tags Notebook
+// @Produce json
+// @Param id path string true "Notebook id"
+// @Success 200 {object} notebook.Notebook
+// @Failure 400 {array} handler.Error
+// @Failure 404 {object} handler.Error
+// @Router /v1/notebooks/{id} [get]
+func Get(ctx *gin.Context) handler.Result {
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   []handler.Error{{Field: "id", Message: "invalid id"}},
+		}
+	}
+
+	found, err := notebook.Find(ctx, id)
+
+	switch {
+	case err != nil:
+		return handler.Result{
+			Status: http.StatusInternalServerError,
+			Body:   handler.Error{Message: err.Error()},
+		}
+	case found.Id == 0:
+		return handler.Result{
+			Status: http.StatusNotFound,
+			Body:   handler.Error{Message: "notebook not found"},
+		}
+	default:
+		return handler.Result{
+			Status: http.StatusOK,
+			Body:   found,
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package notebooks
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ribgsilva/note-api/business/v1/notebook"
+	"github.com/ribgsilva/note-api/platform/web/handler"
+	"net/http"
+)
+
+// List godoc
+// @Summary List notebooks
+// @Description List all the notebooks ordered by path, so parents come before their children
+// @Tags Notebook
+// @Produce json
+// @Success 200 {array} notebook.Notebook
+// @Router /v1/notebooks [get]
+func List(ctx *gin.Context) handler.Result {
+
+	found, err := notebook.List(ctx)
+	if err != nil {
+		return handler.Result{
+			Status: http.StatusInternalServerError,
+			Body:   handler.Error{Message: err.Error()},
+		}
+	}
+
+	return handler.Result{
+		Status: http.StatusOK,
+		Body:   found,
+	}
+}
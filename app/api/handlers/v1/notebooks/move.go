@@ -0,0 +1,72 @@
+package notebooks
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/ribgsilva/note-api/business/v1/notebook"
+	"github.com/ribgsilva/note-api/platform/web/handler"
+	"net/http"
+	"strconv"
+)
+
+// Move godoc
+// @Summary Move a notebook
+// @Description Move a notebook with all its contents to another parent, or to the root when parentId is 0
+// @Tags Notebook
+// @Accept json
+// @Produce json
+// @Param id path string true "Notebook id"
+// @Param move body notebook.MoveNotebook true "Destination parent"
+// @Success 200 {object} notebook.Notebook
+// @Failure 400 {array} handler.Error
+// @Failure 404 {object} handler.Error
+// @Failure 409 {object} handler.Error
+// @Router /v1/notebooks/{id}/move [post]
+func Move(ctx *gin.Context) handler.Result {
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   []handler.Error{{Field: "id", Message: "invalid id"}},
+		}
+	}
+
+	var m notebook.MoveNotebook
+	if err := ctx.ShouldBindJSON(&m); err != nil {
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   []handler.Error{{Message: "invalid body"}},
+		}
+	}
+
+	moved, err := notebook.Move(ctx, id, m)
+
+	switch {
+	case errors.Is(err, notebook.ErrParentNotFound):
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   []handler.Error{{Field: "parentId", Message: err.Error()}},
+		}
+	case errors.Is(err, notebook.ErrCycle):
+		return handler.Result{
+			Status: http.StatusConflict,
+			Body:   handler.Error{Message: err.Error()},
+		}
+	case err != nil:
+		return handler.Result{
+			Status: http.StatusInternalServerError,
+			Body:   handler.Error{Message: err.Error()},
+		}
+	case moved.Id == 0:
+		return handler.Result{
+			Status: http.StatusNotFound,
+			Body:   handler.Error{Message: "notebook not found"},
+		}
+	default:
+		return handler.Result{
+			Status: http.StatusOK,
+			Body:   moved,
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package notebooks
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/business/v1/notebook"
+	"github.com/ribgsilva/note-api/platform/web/handler"
+	"github.com/ribgsilva/note-api/platform/web/paging"
+	"net/http"
+	"strconv"
+)
+
+// Notes godoc
+// @Summary List the notes of a notebook
+// @Description List the notes of a notebook, the most recently changed first, optionally including the ones in its descendants
+// @Tags Notebook
+// @Produce json
+// @Param id path string true "Notebook id"
+// @Param recursive query bool false "Include notes of descendant notebooks" default(false)
+// @Param limit query int false "Max notes returned" default(20)
+// @Param offset query int false "Notes skipped" default(0)
+// @Success 200 {array} note.Note
+// @Failure 400 {array} handler.Error
+// @Failure 404 {object} handler.Error
+// @Router /v1/notebooks/{id}/notes [get]
+func Notes(ctx *gin.Context) handler.Result {
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   []handler.Error{{Field: "id", Message: "invalid id"}},
+		}
+	}
+
+	page, errs := paging.Parse(ctx, 20, 100)
+	recursive, err := strconv.ParseBool(ctx.DefaultQuery("recursive", "false"))
+	if err != nil {
+		errs = append(errs, handler.Error{Field: "recursive", Message: "must be a boolean"})
+	}
+	if len(errs) > 0 {
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   errs,
+		}
+	}
+
+	found, err := notebook.Find(ctx, id)
+	if err != nil {
+		return handler.Result{
+			Status: http.StatusInternalServerError,
+			Body:   handler.Error{Message: err.Error()},
+		}
+	}
+	if found.Id == 0 {
+		return handler.Result{
+			Status: http.StatusNotFound,
+			Body:   handler.Error{Message: "notebook not found"},
+		}
+	}
+
+	notes, err := note.List(ctx, note.Filter{
+		NotebookPath: found.Path,
+		Recursive:    recursive,
+		Limit:        page.Limit,
+		Offset:       page.Offset,
+	})
+	if err != nil {
+		return handler.Result{
+			Status: http.StatusInternalServerError,
+			Body:   handler.Error{Message: err.Error()},
+		}
+	}
+
+	return handler.Result{
+		Status: http.StatusOK,
+		Body:   notes,
+	}
+}
@@ -0,0 +1,20 @@
+package notebooks
+
+import (
+	"github.com/ribgsilva/note-api/business/v1/notebook"
+	"github.com/ribgsilva/note-api/platform/web/handler"
+	"unicode/utf8"
+)
+
+const maxName = 100
+
+func validate(newN notebook.NewNotebook) []handler.Error {
+	var errs []handler.Error
+	switch {
+	case newN.Name == "":
+		errs = append(errs, handler.Error{Field: "name", Message: "required"})
+	case utf8.RuneCountInString(newN.Name) > maxName:
+		errs = append(errs, handler.Error{Field: "name", Message: "must have at most 100 characters"})
+	}
+	return errs
+}
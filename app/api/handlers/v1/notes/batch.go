@@ -0,0 +1,359 @@
+package notes
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/platform/web/conn"
+	"github.com/ribgsilva/note-api/platform/web/handler"
+	"github.com/ribgsilva/note-api/platform/web/identity"
+	"github.com/ribgsilva/note-api/sys"
+	"io"
+	"net/http"
+	"time"
+)
+
+// ndjson is the content type of batches sent and answered with one operation per line
+const ndjson = "application/x-ndjson"
+
+// OperationResult is the outcome of an operation of a batch, with the status it would have as a request of its own.
+// Operations not applied because another one of a transactional batch failed have the status 424
+type OperationResult struct {
+	Index  int             `json:"index" example:"0"`
+	Status int             `json:"status" example:"201"`
+	Note   *note.Note      `json:"note,omitempty"`
+	Errors []handler.Error `json:"errors,omitempty"`
+}
+
+// BatchResponse has the results of the operations of a batch, in the order they were sent
+type BatchResponse struct {
+	Results []OperationResult `json:"results"`
+	Applied int               `json:"applied" example:"2"`
+	Failed  int               `json:"failed" example:"0"`
+}
+
+// Batch godoc
+// @Summary Apply a batch of operations
+// @Description Create, update and delete notes in order, answering the result of every operation. Transactional batches apply every operation or none.
+// @Description Bodies of the application/x-ndjson content type have one operation per line, transactional with ?transactional=true, and are answered with one result per line as the operations are applied
+// @Tags Note
+// @Accept json
+// @Produce json
+// @Param batch body note.Batch true "Operations"
+// @Param transactional query bool false "Apply every operation or none"
+// @Param X-User-Id header string false "User changing the notes"
+// @Success 200 {object} BatchResponse
+// @Failure 400 {object} BatchResponse
+// @Failure 404 {object} BatchResponse
+// @Failure 412 {object} BatchResponse
+// @Failure 413 {array} handler.Error
+// @Router /v1/notes/batch [post]
+func Batch(ctx *gin.Context) handler.Result {
+	transactional := ctx.Query("transactional") == "true"
+	if ctx.ContentType() == ndjson {
+		return stream(ctx, transactional)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(ctx.Request.Body, int64(sys.Configs.Batch.MaxBytes)+1))
+	if err != nil {
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   []handler.Error{{Message: "invalid body"}},
+		}
+	}
+	if len(body) > sys.Configs.Batch.MaxBytes {
+		return handler.Result{
+			Status: http.StatusRequestEntityTooLarge,
+			Body:   []handler.Error{{Message: fmt.Sprintf("must have at most %d bytes", sys.Configs.Batch.MaxBytes)}},
+		}
+	}
+	var b note.Batch
+	if err := json.Unmarshal(body, &b); err != nil {
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   []handler.Error{{Message: "invalid body"}},
+		}
+	}
+	switch {
+	case len(b.Operations) == 0:
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   []handler.Error{{Field: "operations", Message: "required"}},
+		}
+	case len(b.Operations) > sys.Configs.Batch.MaxOperations:
+		return handler.Result{
+			Status: http.StatusRequestEntityTooLarge,
+			Body:   []handler.Error{{Field: "operations", Message: fmt.Sprintf("must have at most %d operations", sys.Configs.Batch.MaxOperations)}},
+		}
+	}
+
+	author := identity.User(ctx)
+	if b.Transactional || transactional {
+		status, results := applyAll(ctx, b.Operations, map[int][]handler.Error{}, author)
+		return handler.Result{Status: status, Body: summary(results)}
+	}
+
+	results := make([]OperationResult, len(b.Operations))
+	for i, op := range b.Operations {
+		results[i] = apply(ctx, i, op, author)
+	}
+	return handler.Result{Status: http.StatusOK, Body: summary(results)}
+}
+
+// stream applies a batch with one operation per line, answering one result per line. Transactional batches are read
+// whole before being applied, so they have the same limit as the JSON ones, while the others are applied as they are read
+func stream(ctx *gin.Context, transactional bool) handler.Result {
+	author := identity.User(ctx)
+	lines := bufio.NewScanner(ctx.Request.Body)
+	lines.Buffer(make([]byte, 0, 64*1024), sys.Configs.Batch.MaxBytes)
+	headers := map[string]string{"Content-Type": ndjson}
+
+	if !transactional {
+		return handler.Result{
+			Status:  http.StatusOK,
+			Headers: headers,
+			Stream: func(w io.Writer) {
+				// the lines are read while the results are written, each one with the server timeouts to be read and answered
+				if err := conn.FullDuplex(ctx); err != nil {
+					sys.R.Log.Errorw("failed to make the batch stream full duplex", "ERROR", err)
+				}
+				extend(ctx, sys.Configs.Http.WriteTimeout)
+				encoder := json.NewEncoder(w)
+				i := 0
+				for ; lines.Scan(); i++ {
+					if len(lines.Bytes()) == 0 {
+						i--
+						continue
+					}
+					if i == sys.Configs.Batch.MaxStreamOperations {
+						_ = encoder.Encode(OperationResult{Index: i, Status: http.StatusRequestEntityTooLarge,
+							Errors: []handler.Error{{Message: fmt.Sprintf("must have at most %d operations", sys.Configs.Batch.MaxStreamOperations)}}})
+						return
+					}
+
+					var op note.Operation
+					result := OperationResult{Index: i, Status: http.StatusBadRequest, Errors: []handler.Error{{Message: "invalid operation"}}}
+					if err := json.Unmarshal(lines.Bytes(), &op); err == nil {
+						result = apply(ctx, i, op, author)
+					}
+					_ = encoder.Encode(result)
+					if f, ok := w.(http.Flusher); ok {
+						f.Flush()
+					}
+					extend(ctx, sys.Configs.Http.WriteTimeout)
+				}
+				if err := lines.Err(); err != nil {
+					status, errs := unreadable(err)
+					_ = encoder.Encode(OperationResult{Index: i, Status: status, Errors: errs})
+				}
+			},
+		}
+	}
+
+	var ops []note.Operation
+	invalid := map[int][]handler.Error{}
+	for lines.Scan() {
+		extend(ctx, sys.Configs.Http.WriteTimeout)
+		if len(lines.Bytes()) == 0 {
+			continue
+		}
+		if len(ops) == sys.Configs.Batch.MaxOperations {
+			return handler.Result{
+				Status: http.StatusRequestEntityTooLarge,
+				Body:   []handler.Error{{Field: "operations", Message: fmt.Sprintf("must have at most %d operations", sys.Configs.Batch.MaxOperations)}},
+			}
+		}
+		var op note.Operation
+		if err := json.Unmarshal(lines.Bytes(), &op); err != nil {
+			invalid[len(ops)] = []handler.Error{{Message: "invalid operation"}}
+		}
+		ops = append(ops, op)
+	}
+	if err := lines.Err(); err != nil {
+		status, errs := unreadable(err)
+		return handler.Result{Status: status, Body: errs}
+	}
+	if len(ops) == 0 {
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   []handler.Error{{Field: "operations", Message: "required"}},
+		}
+	}
+
+	status, results := applyAll(ctx, ops, invalid, author)
+	return handler.Result{
+		Status:  status,
+		Headers: headers,
+		Stream: func(w io.Writer) {
+			encoder := json.NewEncoder(w)
+			for _, r := range results {
+				_ = encoder.Encode(r)
+			}
+		},
+	}
+}
+
+// extend gives the request the server read timeout and write from now to go on
+func extend(ctx *gin.Context, write time.Duration) {
+	if err := conn.Extend(ctx, sys.Configs.Http.ReadTimeout, write); err != nil {
+		sys.R.Log.Errorw("failed to extend the request deadlines", "ERROR", err)
+	}
+}
+
+// unreadable is the status and errors of a batch whose lines could not be read
+func unreadable(err error) (int, []handler.Error) {
+	if errors.Is(err, bufio.ErrTooLong) {
+		return http.StatusRequestEntityTooLarge, []handler.Error{{Message: fmt.Sprintf("lines must have at most %d bytes", sys.Configs.Batch.MaxBytes)}}
+	}
+	return http.StatusBadRequest, []handler.Error{{Message: "invalid body"}}
+}
+
+// apply validates and applies an operation on its own
+func apply(ctx *gin.Context, index int, op note.Operation, author string) OperationResult {
+	if errs := validateOperation(op); len(errs) > 0 {
+		return OperationResult{Index: index, Status: http.StatusBadRequest, Errors: errs}
+	}
+
+	switch op.Op {
+	case note.OpCreate:
+		created, err := note.Create(ctx, *op.Note, author)
+		return outcome(index, op, created, err)
+	case note.OpUpdate:
+		updated, err := note.Update(ctx, note.UpdateNote{
+			Id:              op.Id,
+			Title:           op.Note.Title,
+			Text:            op.Note.Text,
+			Tags:            op.Note.Tags,
+			ExpectedVersion: op.ExpectedVersion,
+		}, author)
+		if err == nil && updated.Id == 0 {
+			err = note.ErrNotFound
+		}
+		return outcome(index, op, updated, err)
+	default:
+		deleted, err := note.Delete(ctx, op.Id, op.ExpectedVersion, author)
+		if err == nil && !deleted {
+			err = note.ErrNotFound
+		}
+		return outcome(index, op, note.Note{}, err)
+	}
+}
+
+// applyAll validates every operation and applies them in a single transaction. When any of them is invalid or fails,
+// none is applied and the batch gets its status
+func applyAll(ctx *gin.Context, ops []note.Operation, invalid map[int][]handler.Error, author string) (int, []OperationResult) {
+	for i, op := range ops {
+		if _, ok := invalid[i]; !ok {
+			if errs := validateOperation(op); len(errs) > 0 {
+				invalid[i] = errs
+			}
+		}
+	}
+
+	results := make([]OperationResult, len(ops))
+	if len(invalid) > 0 {
+		for i := range results {
+			results[i] = OperationResult{Index: i, Status: http.StatusBadRequest, Errors: invalid[i]}
+			if invalid[i] == nil {
+				results[i] = notApplied(i)
+			}
+		}
+		return http.StatusBadRequest, results
+	}
+
+	// the transaction of the batch gets longer with its operations, and so must the time to answer it
+	extend(ctx, sys.Configs.Database.OperationTimeout*time.Duration(len(ops))+sys.Configs.Http.WriteTimeout)
+	notes, err := note.Apply(ctx, ops, author)
+	var opErr *note.OperationError
+	if errors.As(err, &opErr) {
+		for i := range results {
+			results[i] = notApplied(i)
+		}
+		results[opErr.Index] = outcome(opErr.Index, ops[opErr.Index], note.Note{}, opErr.Err)
+		return results[opErr.Index].Status, results
+	}
+	if err != nil {
+		// nothing tells which operation failed, so every one of them is reported as not applied
+		sys.R.Log.Errorw("batch failed", "operations", len(ops), "ERROR", err)
+		for i := range results {
+			results[i] = notApplied(i)
+		}
+		return http.StatusInternalServerError, results
+	}
+	for i, op := range ops {
+		results[i] = outcome(i, op, notes[i], nil)
+	}
+	return http.StatusOK, results
+}
+
+// outcome is the result of an applied operation, with the status of the error when it failed
+func outcome(index int, op note.Operation, n note.Note, err error) OperationResult {
+	switch {
+	case errors.Is(err, note.ErrNotebookNotFound):
+		return OperationResult{Index: index, Status: http.StatusBadRequest, Errors: []handler.Error{{Field: "notebookId", Message: err.Error()}}}
+	case errors.Is(err, note.ErrNotFound):
+		return OperationResult{Index: index, Status: http.StatusNotFound, Errors: []handler.Error{{Message: "notes not found"}}}
+	case errors.Is(err, note.ErrVersionMismatch):
+		return OperationResult{Index: index, Status: http.StatusPreconditionFailed, Errors: []handler.Error{{Message: err.Error()}}}
+	case err != nil:
+		sys.R.Log.Errorw("batch operation failed", "index", index, "op", op.Op, "ERROR", err)
+		return OperationResult{Index: index, Status: http.StatusInternalServerError, Errors: []handler.Error{{Message: "failed to apply the operation"}}}
+	}
+
+	switch op.Op {
+	case note.OpCreate:
+		return OperationResult{Index: index, Status: http.StatusCreated, Note: &n}
+	case note.OpUpdate:
+		return OperationResult{Index: index, Status: http.StatusOK, Note: &n}
+	default:
+		return OperationResult{Index: index, Status: http.StatusNoContent}
+	}
+}
+
+// notApplied is the result of an operation of a transactional batch rolled back by another one
+func notApplied(index int) OperationResult {
+	return OperationResult{Index: index, Status: http.StatusFailedDependency, Errors: []handler.Error{{Message: "not applied"}}}
+}
+
+// validateOperation checks an operation has what it needs to be applied
+func validateOperation(op note.Operation) []handler.Error {
+	switch op.Op {
+	case note.OpCreate:
+		if op.Note == nil {
+			return []handler.Error{{Field: "note", Message: "required"}}
+		}
+		return validate(*op.Note)
+	case note.OpUpdate:
+		var errs []handler.Error
+		if op.Id == 0 {
+			errs = append(errs, handler.Error{Field: "id", Message: "required"})
+		}
+		if op.Note == nil {
+			return append(errs, handler.Error{Field: "note", Message: "required"})
+		}
+		return append(errs, validate(*op.Note)...)
+	case note.OpDelete:
+		if op.Id == 0 {
+			return []handler.Error{{Field: "id", Message: "required"}}
+		}
+		return nil
+	default:
+		return []handler.Error{{Field: "op", Message: "must be create, update or delete"}}
+	}
+}
+
+// summary counts the operations applied and failed of a batch
+func summary(results []OperationResult) BatchResponse {
+	response := BatchResponse{Results: results}
+	for _, r := range results {
+		if r.Status < http.StatusBadRequest {
+			response.Applied++
+		} else {
+			response.Failed++
+		}
+	}
+	return response
+}
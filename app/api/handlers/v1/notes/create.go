@@ -0,0 +1,65 @@
+package notes
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/platform/web/etag"
+	"github.com/ribgsilva/note-api/platform/web/handler"
+	"github.com/ribgsilva/note-api/platform/web/identity"
+	"net/http"
+)
+
+// Create godoc
+// @Summary Create a note
+// @Description Create a note with its tags, in the root or in a notebook
+// @Tags Note
+// @Accept json
+// @Produce json
+// @Param note body note.NewNote true "Note content"
+// @Param X-User-Id header string false "User creating the note"
+// @Success 201 {object} note.Note
+// @Header 201 {string} ETag "Version of the note"
+// @Header 201 {string} Location "Url of the note"
+// @Failure 400 {array} handler.Error
+// @Router /v1/notes [post]
+func Create(ctx *gin.Context) handler.Result {
+
+	var newN note.NewNote
+	if err := ctx.ShouldBindJSON(&newN); err != nil {
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   []handler.Error{{Message: "invalid body"}},
+		}
+	}
+	if errs := validate(newN); len(errs) > 0 {
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   errs,
+		}
+	}
+
+	created, err := note.Create(ctx, newN, identity.User(ctx))
+	if errors.Is(err, note.ErrNotebookNotFound) {
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   []handler.Error{{Field: "notebookId", Message: err.Error()}},
+		}
+	}
+	if err != nil {
+		return handler.Result{
+			Status: http.StatusInternalServerError,
+			Body:   handler.Error{Message: err.Error()},
+		}
+	}
+
+	return handler.Result{
+		Status: http.StatusCreated,
+		Body:   created,
+		Headers: map[string]string{
+			"ETag":     etag.Format(created.Version),
+			"Location": fmt.Sprintf("/v1/notes/%d", created.Id),
+		},
+	}
+}
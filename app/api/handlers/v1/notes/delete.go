@@ -0,0 +1,61 @@
+package notes
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/platform/web/handler"
+	"github.com/ribgsilva/note-api/platform/web/identity"
+	"net/http"
+	"strconv"
+)
+
+// Delete godoc
+// @Summary Delete a note
+// @Description Move a note to the trash, from where it can be restored until it is purged
+// @Tags Note
+// @Param id path string true "Note id"
+// @Param If-Match header string false "ETag of the version being deleted"
+// @Success 204
+// @Failure 400 {array} handler.Error
+// @Failure 404 {object} handler.Error
+// @Failure 412 {object} handler.Error
+// @Failure 428 {object} handler.Error
+// @Router /v1/notes/{id} [delete]
+func Delete(ctx *gin.Context) handler.Result {
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   []handler.Error{{Field: "id", Message: "invalid id"}},
+		}
+	}
+
+	version, res := expectedVersion(ctx)
+	if res != nil {
+		return *res
+	}
+
+	deleted, err := note.Delete(ctx, id, version, identity.User(ctx))
+
+	switch {
+	case errors.Is(err, note.ErrVersionMismatch):
+		return handler.Result{
+			Status: http.StatusPreconditionFailed,
+			Body:   handler.Error{Message: err.Error()},
+		}
+	case err != nil:
+		return handler.Result{
+			Status: http.StatusInternalServerError,
+			Body:   handler.Error{Message: err.Error()},
+		}
+	case !deleted:
+		return handler.Result{
+			Status: http.StatusNotFound,
+			Body:   handler.Error{Message: "notes not found"},
+		}
+	default:
+		return handler.Result{Status: http.StatusNoContent}
+	}
+}
@@ -0,0 +1,59 @@
+package notes
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/platform/web/handler"
+	"github.com/ribgsilva/note-api/platform/web/identity"
+	"github.com/ribgsilva/note-api/sys"
+	"io"
+	"net/http"
+)
+
+// Export godoc
+// @Summary Export notes
+// @Description Download the notes out of the trash created by the caller, in the order they were created.
+// @Description Markdown exports are a zip with a file per note, with its title, tags and timestamps in a YAML front matter. The notes are written as they are read
+// @Tags Note
+// @Produce json,application/x-ndjson,text/csv,application/zip
+// @Param format query string false "Export format" Enums(json, ndjson, csv, markdown) default(json)
+// @Param X-User-Id header string true "User whose notes are exported"
+// @Success 200 {array} note.Note
+// @Header 200 {string} Content-Disposition "File name of the export"
+// @Failure 400 {array} handler.Error
+// @Failure 401 {array} handler.Error
+// @Router /v1/notes/export [get]
+func Export(ctx *gin.Context) handler.Result {
+
+	author := identity.User(ctx)
+	if author == "" {
+		return handler.Result{
+			Status: http.StatusUnauthorized,
+			Body:   []handler.Error{{Field: identity.UserHeader, Message: "required"}},
+		}
+	}
+
+	format := ctx.DefaultQuery("format", note.ExportJSON)
+	f, ok := note.LookupExport(format)
+	if !ok {
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   []handler.Error{{Field: "format", Message: "must be json, ndjson, csv or markdown"}},
+		}
+	}
+
+	return handler.Result{
+		Status: http.StatusOK,
+		Headers: map[string]string{
+			"Content-Type":        f.ContentType,
+			"Content-Disposition": fmt.Sprintf(`attachment; filename="notes.%s"`, f.Extension),
+		},
+		Stream: func(w io.Writer) {
+			// the status is already sent, so a failure can only cut the export short
+			if err := note.Export(ctx, w, format, author); err != nil {
+				sys.R.Log.Errorw("export failed", "format", format, "ERROR", err)
+			}
+		},
+	}
+}
@@ -3,6 +3,7 @@ package notes
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/platform/web/etag"
 	"github.com/ribgsilva/note-api/platform/web/handler"
 	"net/http"
 	"strconv"
@@ -14,7 +15,10 @@ import (
 // @Tags Note
 // @Produce json
 // @Param id path string true "Note id"
+// @Param If-None-Match header string false "ETag of the version the caller already has"
 // @Success 200 {object} note.Note
+// @Header 200 {string} ETag "Version of the note"
+// @Success 304
 // @Failure 400 {array} handler.Error
 // @Failure 404 {object} handler.Error
 // @Router /v1/notes/{id} [get]
@@ -41,10 +45,16 @@ func Get(ctx *gin.Context) handler.Result {
 			Status: http.StatusNotFound,
 			Body:   handler.Error{Message: "notes not found"},
 		}
+	case etag.Match(ctx.GetHeader("If-None-Match"), get.Version):
+		return handler.Result{
+			Status:  http.StatusNotModified,
+			Headers: map[string]string{"ETag": etag.Format(get.Version)},
+		}
 	default:
 		return handler.Result{
-			Status: http.StatusOK,
-			Body:   get,
+			Status:  http.StatusOK,
+			Body:    get,
+			Headers: map[string]string{"ETag": etag.Format(get.Version)},
 		}
 	}
 }
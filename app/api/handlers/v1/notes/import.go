@@ -0,0 +1,174 @@
+package notes
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/platform/web/handler"
+	"github.com/ribgsilva/note-api/platform/web/identity"
+	"github.com/ribgsilva/note-api/sys"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// ImportResponse has the result of every file of an import, in the order they were read
+type ImportResponse struct {
+	Results    []note.ImportResult `json:"results"`
+	Created    int                 `json:"created" example:"2"`
+	Duplicates int                 `json:"duplicates" example:"0"`
+	Queued     int                 `json:"queued" example:"0"`
+	Failed     int                 `json:"failed" example:"0"`
+}
+
+// Import godoc
+// @Summary Import notes
+// @Description Create notes from a zip of Markdown files, with their title, tags and notebook in a YAML front matter, or from an Evernote export.
+// @Description The file is sent as the body or as the file field of a multipart form. Notes with the same title and text of a note the caller imported before, out of the trash, are not created again.
+// @Description Imports with more notes than the queue threshold are created by the messaging app when the imports topic is set, answering 202
+// @Tags Note
+// @Accept application/zip,application/xml,multipart/form-data
+// @Produce json
+// @Param format query string false "Import format, by the file extension when not set" Enums(markdown, enex)
+// @Param file formData file false "Zip of Markdown files or Evernote export"
+// @Param X-User-Id header string false "User creating the notes"
+// @Success 200 {object} ImportResponse
+// @Success 202 {object} ImportResponse
+// @Failure 400 {array} handler.Error
+// @Failure 413 {array} handler.Error
+// @Router /v1/notes/import [post]
+func Import(ctx *gin.Context) handler.Result {
+
+	name, content, err := importFile(ctx.Request)
+	switch {
+	case errors.Is(err, errTooLarge):
+		return handler.Result{
+			Status: http.StatusRequestEntityTooLarge,
+			Body:   []handler.Error{{Message: fmt.Sprintf("must have at most %d bytes", sys.Configs.Import.MaxBytes)}},
+		}
+	case err != nil:
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   []handler.Error{{Field: "file", Message: err.Error()}},
+		}
+	}
+
+	format := ctx.Query("format")
+	if format == "" {
+		format = importFormat(name, ctx.ContentType())
+	}
+	if format != note.ImportMarkdown && format != note.ImportENEX {
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   []handler.Error{{Field: "format", Message: "must be markdown or enex"}},
+		}
+	}
+
+	files, err := note.ReadImport(format, content)
+	switch {
+	case errors.Is(err, note.ErrImportFormat):
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   []handler.Error{{Field: "file", Message: err.Error()}},
+		}
+	case errors.Is(err, note.ErrImportTooLarge):
+		return handler.Result{
+			Status: http.StatusRequestEntityTooLarge,
+			Body:   []handler.Error{{Field: "file", Message: err.Error()}},
+		}
+	case err != nil:
+		return handler.Result{
+			Status: http.StatusInternalServerError,
+			Body:   handler.Error{Message: err.Error()},
+		}
+	case len(files) == 0:
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   []handler.Error{{Field: "file", Message: "has no notes"}},
+		}
+	}
+
+	author := identity.User(ctx)
+	status := http.StatusOK
+	var results []note.ImportResult
+	if sys.R.Imports != nil && len(files) > sys.Configs.Import.QueueThreshold {
+		status = http.StatusAccepted
+		results = note.QueueImport(ctx, sys.R.Imports, files, author)
+	} else {
+		results = note.ImportFiles(ctx, files, author)
+	}
+
+	res := ImportResponse{Results: results}
+	for _, r := range results {
+		switch r.Status {
+		case note.ImportCreated:
+			res.Created++
+		case note.ImportDuplicate:
+			res.Duplicates++
+		case note.ImportQueued:
+			res.Queued++
+		default:
+			res.Failed++
+		}
+	}
+	return handler.Result{
+		Status: status,
+		Body:   res,
+	}
+}
+
+// errTooLarge is returned when an import has more than IMPORT_MAX_BYTES
+var errTooLarge = errors.New("import too large")
+
+// importFile reads the file of an import, from the file field of a multipart form or the whole body,
+// returning its name when the form has it
+func importFile(r *http.Request) (string, []byte, error) {
+	body := r.Body
+	name := ""
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "multipart/form-data" {
+		form, err := r.MultipartReader()
+		if err != nil {
+			return "", nil, errors.New("invalid multipart form")
+		}
+		for {
+			part, err := form.NextPart()
+			if errors.Is(err, io.EOF) {
+				return "", nil, errors.New("required")
+			}
+			if err != nil {
+				return "", nil, errors.New("invalid multipart form")
+			}
+			if part.FormName() == "file" {
+				body, name = part, part.FileName()
+				break
+			}
+		}
+	}
+
+	content, err := io.ReadAll(io.LimitReader(body, int64(sys.Configs.Import.MaxBytes)+1))
+	switch {
+	case err != nil:
+		return "", nil, errors.New("could not be read")
+	case len(content) > sys.Configs.Import.MaxBytes:
+		return "", nil, errTooLarge
+	case len(content) == 0:
+		return "", nil, errors.New("required")
+	}
+	return name, content, nil
+}
+
+// importFormat is the format of an import by its file name, or its content type when sent as the body
+func importFormat(name, contentType string) string {
+	if format := note.ImportFormatOf(name); format != "" {
+		return format
+	}
+	switch {
+	case contentType == "application/zip":
+		return note.ImportMarkdown
+	case strings.HasSuffix(contentType, "xml"):
+		return note.ImportENEX
+	}
+	return ""
+}
@@ -0,0 +1,58 @@
+package notes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/platform/web/handler"
+	"github.com/ribgsilva/note-api/platform/web/paging"
+	"net/http"
+)
+
+// List godoc
+// @Summary List notes
+// @Description List the notes, the most recently changed first, optionally only the ones with any or all of the tags. Notes in the trash are not listed
+// @Tags Note
+// @Produce json
+// @Param limit query int false "Max notes returned" default(20)
+// @Param offset query int false "Notes skipped" default(0)
+// @Param tag query []string false "Only notes with these tags" collectionFormat(multi)
+// @Param match query string false "Whether notes must have any or all of the tags" Enums(any, all) default(any)
+// @Success 200 {array} note.Note
+// @Failure 400 {array} handler.Error
+// @Router /v1/notes [get]
+func List(ctx *gin.Context) handler.Result {
+
+	page, errs := paging.Parse(ctx, 20, 100)
+	if len(errs) > 0 {
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   errs,
+		}
+	}
+
+	match := ctx.DefaultQuery("match", "any")
+	if match != "any" && match != "all" {
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   []handler.Error{{Field: "match", Message: "must be any or all"}},
+		}
+	}
+
+	found, err := note.List(ctx, note.Filter{
+		Tags:     ctx.QueryArray("tag"),
+		MatchAll: match == "all",
+		Limit:    page.Limit,
+		Offset:   page.Offset,
+	})
+	if err != nil {
+		return handler.Result{
+			Status: http.StatusInternalServerError,
+			Body:   handler.Error{Message: err.Error()},
+		}
+	}
+
+	return handler.Result{
+		Status: http.StatusOK,
+		Body:   found,
+	}
+}
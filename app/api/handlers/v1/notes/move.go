@@ -0,0 +1,83 @@
+package notes
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/platform/web/etag"
+	"github.com/ribgsilva/note-api/platform/web/handler"
+	"github.com/ribgsilva/note-api/platform/web/identity"
+	"net/http"
+	"strconv"
+)
+
+// Move godoc
+// @Summary Move a note
+// @Description Put a note in a notebook, or in the root when notebookId is 0
+// @Tags Notebook
+// @Accept json
+// @Produce json
+// @Param id path string true "Note id"
+// @Param move body note.MoveNote true "Destination notebook"
+// @Param If-Match header string false "ETag of the version being moved"
+// @Success 200 {object} note.Note
+// @Header 200 {string} ETag "Version of the note"
+// @Failure 400 {array} handler.Error
+// @Failure 404 {object} handler.Error
+// @Failure 412 {object} handler.Error
+// @Failure 428 {object} handler.Error
+// @Router /v1/notes/{id}/move [post]
+func Move(ctx *gin.Context) handler.Result {
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   []handler.Error{{Field: "id", Message: "invalid id"}},
+		}
+	}
+
+	version, res := expectedVersion(ctx)
+	if res != nil {
+		return *res
+	}
+
+	var m note.MoveNote
+	if err := ctx.ShouldBindJSON(&m); err != nil {
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   []handler.Error{{Message: "invalid body"}},
+		}
+	}
+
+	moved, err := note.Move(ctx, id, m, version, identity.User(ctx))
+
+	switch {
+	case errors.Is(err, note.ErrVersionMismatch):
+		return handler.Result{
+			Status: http.StatusPreconditionFailed,
+			Body:   handler.Error{Message: err.Error()},
+		}
+	case errors.Is(err, note.ErrNotebookNotFound):
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   []handler.Error{{Field: "notebookId", Message: err.Error()}},
+		}
+	case err != nil:
+		return handler.Result{
+			Status: http.StatusInternalServerError,
+			Body:   handler.Error{Message: err.Error()},
+		}
+	case moved.Id == 0:
+		return handler.Result{
+			Status: http.StatusNotFound,
+			Body:   handler.Error{Message: "notes not found"},
+		}
+	default:
+		return handler.Result{
+			Status:  http.StatusOK,
+			Body:    moved,
+			Headers: map[string]string{"ETag": etag.Format(moved.Version)},
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package notes
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/platform/web/etag"
+	"github.com/ribgsilva/note-api/platform/web/handler"
+	"github.com/ribgsilva/note-api/platform/web/identity"
+	"net/http"
+	"strconv"
+)
+
+// Patch godoc
+// @Summary Patch a note
+// @Description Change only the fields sent, keeping the previous content as a revision. Tags sent replace all the tags of the note
+// @Tags Note
+// @Accept json
+// @Produce json
+// @Param id path string true "Note id"
+// @Param note body note.PatchNote true "Fields to change"
+// @Param X-User-Id header string false "User changing the note"
+// @Param If-Match header string false "ETag of the version being changed"
+// @Success 200 {object} note.Note
+// @Header 200 {string} ETag "Version of the note"
+// @Failure 400 {array} handler.Error
+// @Failure 404 {object} handler.Error
+// @Failure 412 {object} handler.Error
+// @Failure 428 {object} handler.Error
+// @Router /v1/notes/{id} [patch]
+func Patch(ctx *gin.Context) handler.Result {
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   []handler.Error{{Field: "id", Message: "invalid id"}},
+		}
+	}
+
+	version, res := expectedVersion(ctx)
+	if res != nil {
+		return *res
+	}
+
+	var p note.PatchNote
+	if err := ctx.ShouldBindJSON(&p); err != nil {
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   []handler.Error{{Message: "invalid body"}},
+		}
+	}
+	errs := validateTags(p.Tags)
+	if p.Title != nil {
+		errs = append(validate(note.NewNote{Title: *p.Title}), errs...)
+	}
+	if len(errs) > 0 {
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   errs,
+		}
+	}
+
+	patched, err := note.Patch(ctx, id, p, version, identity.User(ctx))
+
+	switch {
+	case errors.Is(err, note.ErrVersionMismatch):
+		return handler.Result{
+			Status: http.StatusPreconditionFailed,
+			Body:   handler.Error{Message: err.Error()},
+		}
+	case err != nil:
+		return handler.Result{
+			Status: http.StatusInternalServerError,
+			Body:   handler.Error{Message: err.Error()},
+		}
+	case patched.Id == 0:
+		return handler.Result{
+			Status: http.StatusNotFound,
+			Body:   handler.Error{Message: "notes not found"},
+		}
+	default:
+		return handler.Result{
+			Status:  http.StatusOK,
+			Body:    patched,
+			Headers: map[string]string{"ETag": etag.Format(patched.Version)},
+		}
+	}
+}
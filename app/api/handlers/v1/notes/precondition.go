@@ -0,0 +1,33 @@
+package notes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ribgsilva/note-api/platform/web/etag"
+	"github.com/ribgsilva/note-api/platform/web/handler"
+	"github.com/ribgsilva/note-api/sys"
+	"net/http"
+)
+
+// expectedVersion reads the If-Match header, returning the version the caller expects the note to be in.
+// When the header is missing the note can be changed in any version, unless If-Match is required
+func expectedVersion(ctx *gin.Context) (uint64, *handler.Result) {
+	header := ctx.GetHeader("If-Match")
+	if header == "" {
+		if sys.Configs.Http.RequireIfMatch {
+			return 0, &handler.Result{
+				Status: http.StatusPreconditionRequired,
+				Body:   handler.Error{Field: "If-Match", Message: "required"},
+			}
+		}
+		return 0, nil
+	}
+
+	version, err := etag.Version(header)
+	if err != nil {
+		return 0, &handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   []handler.Error{{Field: "If-Match", Message: err.Error()}},
+		}
+	}
+	return version, nil
+}
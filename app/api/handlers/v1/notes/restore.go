@@ -0,0 +1,54 @@
+package notes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/platform/web/etag"
+	"github.com/ribgsilva/note-api/platform/web/handler"
+	"github.com/ribgsilva/note-api/platform/web/identity"
+	"net/http"
+	"strconv"
+)
+
+// Restore godoc
+// @Summary Restore a note
+// @Description Take a note out of the trash
+// @Tags Trash
+// @Produce json
+// @Param id path string true "Note id"
+// @Success 200 {object} note.Note
+// @Header 200 {string} ETag "Version of the note"
+// @Failure 400 {array} handler.Error
+// @Failure 404 {object} handler.Error
+// @Router /v1/notes/{id}/restore [post]
+func Restore(ctx *gin.Context) handler.Result {
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   []handler.Error{{Field: "id", Message: "invalid id"}},
+		}
+	}
+
+	restored, err := note.Restore(ctx, id, identity.User(ctx))
+
+	switch {
+	case err != nil:
+		return handler.Result{
+			Status: http.StatusInternalServerError,
+			Body:   handler.Error{Message: err.Error()},
+		}
+	case restored.Id == 0:
+		return handler.Result{
+			Status: http.StatusNotFound,
+			Body:   handler.Error{Message: "notes not found in trash"},
+		}
+	default:
+		return handler.Result{
+			Status:  http.StatusOK,
+			Body:    restored,
+			Headers: map[string]string{"ETag": etag.Format(restored.Version)},
+		}
+	}
+}
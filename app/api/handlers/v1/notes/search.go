@@ -0,0 +1,59 @@
+package notes
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/platform/web/handler"
+	"github.com/ribgsilva/note-api/platform/web/paging"
+	"net/http"
+)
+
+// Search godoc
+// @Summary Search notes
+// @Description Search the title and text of the notes out of the trash, the most relevant first. All the words must match;
+// @Description use double quotes for phrases and a trailing * for prefixes. Matched words are highlighted with <mark>
+// @Tags Note
+// @Produce json
+// @Param q query string true "Search query" example("meeting notes" proj*)
+// @Param limit query int false "Max notes returned" default(20)
+// @Param offset query int false "Notes skipped" default(0)
+// @Success 200 {array} note.SearchResult
+// @Failure 400 {array} handler.Error
+// @Failure 501 {object} handler.Error
+// @Router /v1/notes/search [get]
+func Search(ctx *gin.Context) handler.Result {
+
+	page, errs := paging.Parse(ctx, 20, 100)
+	if len(errs) > 0 {
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   errs,
+		}
+	}
+
+	found, err := note.Search(ctx, ctx.Query("q"), page.Limit, page.Offset)
+
+	switch {
+	case errors.Is(err, note.ErrEmptyQuery):
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   []handler.Error{{Field: "q", Message: err.Error()}},
+		}
+	case errors.Is(err, note.ErrSearchUnsupported):
+		return handler.Result{
+			Status: http.StatusNotImplemented,
+			Body:   handler.Error{Message: err.Error()},
+		}
+	case err != nil:
+		return handler.Result{
+			Status: http.StatusInternalServerError,
+			Body:   handler.Error{Message: err.Error()},
+		}
+	default:
+		return handler.Result{
+			Status: http.StatusOK,
+			Body:   found,
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package notes
+
+import (
+	"fmt"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/platform/web/handler"
+	"unicode/utf8"
+)
+
+const maxTitle = 100
+
+func validate(newN note.NewNote) []handler.Error {
+	var errs []handler.Error
+	switch {
+	case newN.Title == "":
+		errs = append(errs, handler.Error{Field: "title", Message: "required"})
+	case utf8.RuneCountInString(newN.Title) > maxTitle:
+		errs = append(errs, handler.Error{Field: "title", Message: "must have at most 100 characters"})
+	}
+	return append(errs, validateTags(newN.Tags)...)
+}
+
+func validateTags(tags []string) []handler.Error {
+	var errs []handler.Error
+	if len(tags) > note.MaxTags {
+		errs = append(errs, handler.Error{Field: "tags", Message: "must have at most 20 tags"})
+	}
+	for i, t := range tags {
+		if utf8.RuneCountInString(t) > note.MaxTagLength {
+			errs = append(errs, handler.Error{Field: fmt.Sprintf("tags[%d]", i), Message: "must have at most 50 characters"})
+		}
+	}
+	return errs
+}
@@ -0,0 +1,68 @@
+package revisions
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/ribgsilva/note-api/business/v1/revision"
+	"github.com/ribgsilva/note-api/platform/web/handler"
+	"net/http"
+	"strconv"
+)
+
+// Diff godoc
+// @Summary Compare revisions
+// @Description Line diff of the title and text between two revisions of a note. By default compares with the previous revision. Revisions with more than 2000 changed lines are not compared
+// @Tags Revision
+// @Produce json
+// @Param id path string true "Note id"
+// @Param rev path string true "Revision"
+// @Param from query string false "Revision to compare with"
+// @Success 200 {object} revision.Diff
+// @Failure 400 {array} handler.Error
+// @Failure 404 {object} handler.Error
+// @Failure 422 {object} handler.Error
+// @Router /v1/notes/{id}/revisions/{rev}/diff [get]
+func Diff(ctx *gin.Context) handler.Result {
+
+	id, rev, errs := params(ctx)
+
+	from := rev - 1
+	if f := ctx.Query("from"); f != "" {
+		parsed, err := strconv.ParseUint(f, 10, 64)
+		if err != nil || parsed == 0 {
+			errs = append(errs, handler.Error{Field: "from", Message: "invalid revision"})
+		}
+		from = parsed
+	}
+	if len(errs) > 0 {
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   errs,
+		}
+	}
+
+	get, err := revision.Compare(ctx, id, from, rev)
+
+	switch {
+	case errors.Is(err, revision.ErrDiffTooLarge):
+		return handler.Result{
+			Status: http.StatusUnprocessableEntity,
+			Body:   handler.Error{Message: err.Error()},
+		}
+	case err != nil:
+		return handler.Result{
+			Status: http.StatusInternalServerError,
+			Body:   handler.Error{Message: err.Error()},
+		}
+	case get.NoteId == 0:
+		return handler.Result{
+			Status: http.StatusNotFound,
+			Body:   handler.Error{Message: "revision not found"},
+		}
+	default:
+		return handler.Result{
+			Status: http.StatusOK,
+			Body:   get,
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package revisions
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ribgsilva/note-api/business/v1/revision"
+	"github.com/ribgsilva/note-api/platform/web/handler"
+	"net/http"
+)
+
+// Get godoc
+// @Summary Find a revision
+// @Description Find a version of a note
+// @Tags Revision
+// @Produce json
+// @Param id path string true "Note id"
+// @Param rev path string true "Revision"
+// @Success 200 {object} revision.Revision
+// @Failure 400 {array} handler.Error
+// @Failure 404 {object} handler.Error
+// @Router /v1/notes/{id}/revisions/{rev} [get]
+func Get(ctx *gin.Context) handler.Result {
+
+	id, rev, errs := params(ctx)
+	if len(errs) > 0 {
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   errs,
+		}
+	}
+
+	get, err := revision.Find(ctx, id, rev)
+
+	switch {
+	case err != nil:
+		return handler.Result{
+			Status: http.StatusInternalServerError,
+			Body:   handler.Error{Message: err.Error()},
+		}
+	case get.Revision == 0:
+		return handler.Result{
+			Status: http.StatusNotFound,
+			Body:   handler.Error{Message: "revision not found"},
+		}
+	default:
+		return handler.Result{
+			Status: http.StatusOK,
+			Body:   get,
+		}
+	}
+}
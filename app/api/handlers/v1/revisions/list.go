@@ -0,0 +1,42 @@
+package revisions
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ribgsilva/note-api/business/v1/revision"
+	"github.com/ribgsilva/note-api/platform/web/handler"
+	"net/http"
+	"strconv"
+)
+
+// List godoc
+// @Summary List the revisions of a note
+// @Description List every version of a note, the oldest first
+// @Tags Revision
+// @Produce json
+// @Param id path string true "Note id"
+// @Success 200 {array} revision.Revision
+// @Failure 400 {array} handler.Error
+// @Router /v1/notes/{id}/revisions [get]
+func List(ctx *gin.Context) handler.Result {
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   []handler.Error{{Field: "id", Message: "invalid id"}},
+		}
+	}
+
+	found, err := revision.FindByNote(ctx, id)
+	if err != nil {
+		return handler.Result{
+			Status: http.StatusInternalServerError,
+			Body:   handler.Error{Message: err.Error()},
+		}
+	}
+
+	return handler.Result{
+		Status: http.StatusOK,
+		Body:   found,
+	}
+}
@@ -0,0 +1,20 @@
+package revisions
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ribgsilva/note-api/platform/web/handler"
+	"strconv"
+)
+
+// params reads the note id and the revision from the path, returning the errors of the ones that are invalid
+func params(ctx *gin.Context) (id, rev uint64, errs []handler.Error) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		errs = append(errs, handler.Error{Field: "id", Message: "invalid id"})
+	}
+	rev, err = strconv.ParseUint(ctx.Param("rev"), 10, 64)
+	if err != nil || rev == 0 {
+		errs = append(errs, handler.Error{Field: "rev", Message: "invalid revision"})
+	}
+	return id, rev, errs
+}
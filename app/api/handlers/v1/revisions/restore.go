@@ -0,0 +1,52 @@
+package revisions
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ribgsilva/note-api/business/v1/revision"
+	"github.com/ribgsilva/note-api/platform/web/handler"
+	"github.com/ribgsilva/note-api/platform/web/identity"
+	"net/http"
+)
+
+// Restore godoc
+// @Summary Restore a revision
+// @Description Bring back the content of a revision, storing it as the newest revision of the note
+// @Tags Revision
+// @Produce json
+// @Param id path string true "Note id"
+// @Param rev path string true "Revision"
+// @Param X-User-Id header string false "User restoring the note"
+// @Success 200 {object} note.Note
+// @Failure 400 {array} handler.Error
+// @Failure 404 {object} handler.Error
+// @Router /v1/notes/{id}/revisions/{rev}/restore [post]
+func Restore(ctx *gin.Context) handler.Result {
+
+	id, rev, errs := params(ctx)
+	if len(errs) > 0 {
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   errs,
+		}
+	}
+
+	restored, err := revision.Restore(ctx, id, rev, identity.User(ctx))
+
+	switch {
+	case err != nil:
+		return handler.Result{
+			Status: http.StatusInternalServerError,
+			Body:   handler.Error{Message: err.Error()},
+		}
+	case restored.Id == 0:
+		return handler.Result{
+			Status: http.StatusNotFound,
+			Body:   handler.Error{Message: "revision not found"},
+		}
+	default:
+		return handler.Result{
+			Status: http.StatusOK,
+			Body:   restored,
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package shared
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/ribgsilva/note-api/business/v1/link"
+	"github.com/ribgsilva/note-api/platform/web/handler"
+	"net/http"
+)
+
+// Get godoc
+// @Summary Open a shared note
+// @Description Read a note through a share link, no account required. Each successful read counts as a view
+// @Tags Link
+// @Produce json
+// @Param token path string true "Link token"
+// @Param X-Link-Password header string false "Link password, when the link is protected"
+// @Success 200 {object} link.Shared
+// @Failure 401 {object} handler.Error
+// @Failure 404 {object} handler.Error
+// @Failure 410 {object} handler.Error
+// @Router /v1/shared/{token} [get]
+func Get(ctx *gin.Context) handler.Result {
+
+	get, err := link.Open(ctx, ctx.Param("token"), ctx.GetHeader("X-Link-Password"))
+
+	switch {
+	case errors.Is(err, link.ErrNotFound):
+		return handler.Result{
+			Status: http.StatusNotFound,
+			Body:   handler.Error{Message: "link not found"},
+		}
+	case errors.Is(err, link.ErrExpired), errors.Is(err, link.ErrExhausted):
+		return handler.Result{
+			Status: http.StatusGone,
+			Body:   handler.Error{Message: err.Error()},
+		}
+	case errors.Is(err, link.ErrPasswordRequired), errors.Is(err, link.ErrInvalidPassword):
+		return handler.Result{
+			Status: http.StatusUnauthorized,
+			Body:   handler.Error{Field: "X-Link-Password", Message: err.Error()},
+		}
+	case err != nil:
+		return handler.Result{
+			Status: http.StatusInternalServerError,
+			Body:   handler.Error{Message: err.Error()},
+		}
+	default:
+		return handler.Result{
+			Status: http.StatusOK,
+			Body:   get,
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package tags
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ribgsilva/note-api/business/v1/tag"
+	"github.com/ribgsilva/note-api/platform/web/handler"
+	"net/http"
+)
+
+// List godoc
+// @Summary List tags
+// @Description List the tags in use with how many notes have each of them, the most used first. Notes in the trash are not counted
+// @Tags Tag
+// @Produce json
+// @Success 200 {array} tag.Tag
+// @Router /v1/tags [get]
+func List(ctx *gin.Context) handler.Result {
+
+	found, err := tag.List(ctx)
+	if err != nil {
+		return handler.Result{
+			Status: http.StatusInternalServerError,
+			Body:   handler.Error{Message: err.Error()},
+		}
+	}
+
+	return handler.Result{
+		Status: http.StatusOK,
+		Body:   found,
+	}
+}
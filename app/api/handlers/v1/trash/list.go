@@ -0,0 +1,47 @@
+package trash
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/platform/web/handler"
+	"github.com/ribgsilva/note-api/platform/web/paging"
+	"net/http"
+)
+
+// List godoc
+// @Summary List the trash
+// @Description List the notes in the trash, the most recently deleted first. They are purged after the retention period
+// @Tags Trash
+// @Produce json
+// @Param limit query int false "Max notes returned" default(20)
+// @Param offset query int false "Notes skipped" default(0)
+// @Success 200 {array} note.Note
+// @Failure 400 {array} handler.Error
+// @Router /v1/trash [get]
+func List(ctx *gin.Context) handler.Result {
+
+	page, errs := paging.Parse(ctx, 20, 100)
+	if len(errs) > 0 {
+		return handler.Result{
+			Status: http.StatusBadRequest,
+			Body:   errs,
+		}
+	}
+
+	found, err := note.List(ctx, note.Filter{
+		Trashed: true,
+		Limit:   page.Limit,
+		Offset:  page.Offset,
+	})
+	if err != nil {
+		return handler.Result{
+			Status: http.StatusInternalServerError,
+			Body:   handler.Error{Message: err.Error()},
+		}
+	}
+
+	return handler.Result{
+		Status: http.StatusOK,
+		Body:   found,
+	}
+}
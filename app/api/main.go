@@ -7,17 +7,26 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/go-redis/redis/v8"
 	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/mattn/go-sqlite3"
 	"github.com/newrelic/go-agent/v3/integrations/nrgin"
 	"github.com/newrelic/go-agent/v3/newrelic"
 	"github.com/ribgsilva/note-api/app/api/docs"
 	"github.com/ribgsilva/note-api/app/api/handlers"
+	indexer "github.com/ribgsilva/note-api/app/api/workers/v1/search"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/persistence/v1/schema"
 	"github.com/ribgsilva/note-api/platform/env"
 	"github.com/ribgsilva/note-api/platform/logger"
+	"github.com/ribgsilva/note-api/platform/search"
+	"github.com/ribgsilva/note-api/platform/web/conn"
+	"github.com/ribgsilva/note-api/platform/web/ratelimit"
 	"github.com/ribgsilva/note-api/sys"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"github.com/swaggo/gin-swagger/swaggerFiles"
 	"go.uber.org/automaxprocs/maxprocs"
 	"go.uber.org/zap"
+	"gocloud.dev/pubsub"
+	_ "gocloud.dev/pubsub/awssnssqs"
 	"net/http"
 	"os"
 	"os/signal"
@@ -62,17 +71,43 @@ func run(log *zap.SugaredLogger) error {
 	sys.Configs.Http.IdleTimeout = env.DurationDefault(log, "HTTP_IDLE_TIMEOUT", "120s")
 	sys.Configs.Http.WriteTimeout = env.DurationDefault(log, "HTTP_WRITE_TIMEOUT", "10s")
 	sys.Configs.Http.ShutdownTimeout = env.DurationDefault(log, "HTTP_SHUTDOWN_TIMEOUT", "60s")
+	sys.Configs.Http.RequireIfMatch = env.BoolDefault(log, "HTTP_REQUIRE_IF_MATCH", "f")
 	sys.Configs.Swagger.Protocol = env.OrDefault(log, "SWAGGER_PROTOCOL", "http")
 	sys.Configs.Swagger.Host = env.OrDefault(log, "SWAGGER_HOST", "localhost:"+sys.Configs.Http.Port)
 	sys.Configs.Database.ConnectionURL = env.OrDefault(log, "DATABASE_CONNECTION_URL", "root:admin@localhost:3306/note")
+	sys.Configs.Database.Dialect = env.OrDefault(log, "DATABASE_DIALECT", "mysql")
 	sys.Configs.Database.PingTimeout = env.DurationDefault(log, "DATABASE_PING_TIMEOUT", "2s")
 	sys.Configs.Database.OperationTimeout = env.DurationDefault(log, "DATABASE_OPERATION_TIMEOUT", "5s")
+	sys.Configs.Database.Migrate = env.BoolDefault(log, "DATABASE_MIGRATE", "t")
 	sys.Configs.Cache.ConnectionURL = env.OrDefault(log, "CACHE_CONNECTION_URL", "localhost:6379")
 	sys.Configs.Cache.User = env.OrDefault(log, "CACHE_USER", "")
 	sys.Configs.Cache.Pass = env.OrDefault(log, "CACHE_PASS", "")
 	sys.Configs.Cache.PingTimeout = env.DurationDefault(log, "CACHE_PING_TIMEOUT", "2s")
 	sys.Configs.Cache.OperationTimeout = env.DurationDefault(log, "CACHE_PING_TIMEOUT", "10s")
 	sys.Configs.Cache.CacheTTL = env.DurationDefault(log, "CACHE_CACHE_TTL", "24h")
+	sys.Configs.RateLimit.Enabled = env.BoolDefault(log, "RATE_LIMIT_ENABLED", "t")
+	sys.Configs.RateLimit.Limit = env.IntDefault(log, "RATE_LIMIT_LIMIT", "100")
+	sys.Configs.RateLimit.Period = env.DurationDefault(log, "RATE_LIMIT_PERIOD", "1m")
+	sys.Configs.RateLimit.Burst = env.IntDefault(log, "RATE_LIMIT_BURST", "0")
+	sys.Configs.RateLimit.Keys = env.OrDefault(log, "RATE_LIMIT_KEYS", "ip")
+	sys.Configs.Links.Secret = env.Must(log, "LINKS_SECRET")
+	sys.Configs.Links.DefaultTTL = env.DurationDefault(log, "LINKS_DEFAULT_TTL", "24h")
+	sys.Configs.Links.MaxTTL = env.DurationDefault(log, "LINKS_MAX_TTL", "720h")
+	sys.Configs.Batch.MaxOperations = env.IntDefault(log, "BATCH_MAX_OPERATIONS", "1000")
+	sys.Configs.Batch.MaxBytes = env.IntDefault(log, "BATCH_MAX_BYTES", "10485760")
+	sys.Configs.Batch.MaxStreamOperations = env.IntDefault(log, "BATCH_MAX_STREAM_OPERATIONS", "100000")
+	sys.Configs.Import.MaxBytes = env.IntDefault(log, "IMPORT_MAX_BYTES", "52428800")
+	sys.Configs.Import.QueueThreshold = env.IntDefault(log, "IMPORT_QUEUE_THRESHOLD", "100")
+	sys.Configs.Import.TopicURL = env.OrDefault(log, "IMPORT_TOPIC_URL", "")
+	sys.Configs.Search.Engine = env.OrDefault(log, "SEARCH_ENGINE", "database")
+	sys.Configs.Search.IndexPath = env.OrDefault(log, "SEARCH_INDEX_PATH", "notes.index")
+	sys.Configs.Search.Language = env.OrDefault(log, "SEARCH_LANGUAGE", "en")
+	sys.Configs.Search.ReindexInterval = env.DurationDefault(log, "SEARCH_REINDEX_INTERVAL", "5m")
+	sys.Configs.Events.TopicURL = env.OrDefault(log, "EVENTS_TOPIC_URL", "")
+	sys.Configs.Events.Format = env.OrDefault(log, "EVENTS_FORMAT", note.LegacyFormat)
+	sys.Configs.Events.Source = env.OrDefault(log, "EVENTS_SOURCE", "/note-api")
+	sys.Configs.Events.ShutdownTimeout = env.DurationDefault(log, "EVENTS_SHUTDOWN_TIMEOUT", "10s")
+	sys.Configs.Events.Outbox = env.BoolDefault(log, "EVENTS_OUTBOX", "f")
 	sys.Configs.NewRelic.AppName = env.OrDefault(log, "NEW_RELIC_APP_NAME", "person-api")
 	sys.Configs.NewRelic.Licence = env.OrDefault(log, "NEW_RELIC_LICENCE", "")
 	sys.Configs.NewRelic.Enabled = env.BoolDefault(log, "NEW_RELIC_ENABLED", "f")
@@ -85,10 +120,10 @@ func run(log *zap.SugaredLogger) error {
 	// logger
 	sys.R.Log = log
 
-	// mysql
+	// database of the configured dialect
 	var db *sql.DB
 	if err := func() error {
-		mysqlDb, err := sql.Open("mysql", sys.Configs.Database.ConnectionURL)
+		mysqlDb, err := sql.Open(sys.Configs.Database.Dialect, sys.Configs.Database.ConnectionURL)
 		if err != nil {
 			return fmt.Errorf("error to connecto to database: %w", err)
 		}
@@ -107,6 +142,15 @@ func run(log *zap.SugaredLogger) error {
 	}()
 	sys.R.Database = db
 
+	// migrations
+	if sys.Configs.Database.Migrate {
+		applied, err := schema.Migrate(context.Background())
+		if err != nil {
+			return fmt.Errorf("could not migrate the database: %w", err)
+		}
+		log.Infof("applied %d migrations", applied)
+	}
+
 	// redis
 	// doing in a func, so I can use defer to cancel the contexts
 	var rdb *redis.Client
@@ -131,6 +175,80 @@ func run(log *zap.SugaredLogger) error {
 
 	sys.R.Cache = rdb
 
+	// events
+	if !note.ValidFormat(sys.Configs.Events.Format) {
+		return fmt.Errorf("invalid events format: %s", sys.Configs.Events.Format)
+	}
+	if sys.Configs.Events.TopicURL != "" {
+		topic, err := pubsub.OpenTopic(context.Background(), sys.Configs.Events.TopicURL)
+		if err != nil {
+			return fmt.Errorf("could not open events topic: %w", err)
+		}
+		defer func() {
+			stdCtx, stdCancel := context.WithTimeout(context.Background(), sys.Configs.Events.ShutdownTimeout)
+			defer stdCancel()
+
+			if err := topic.Shutdown(stdCtx); err != nil {
+				log.Errorf("could not stop events topic gracefully: %s", err)
+			}
+		}()
+		sys.R.Events = topic
+	}
+
+	// imports
+	if sys.Configs.Import.TopicURL != "" {
+		topic, err := pubsub.OpenTopic(context.Background(), sys.Configs.Import.TopicURL)
+		if err != nil {
+			return fmt.Errorf("could not open imports topic: %w", err)
+		}
+		defer func() {
+			stdCtx, stdCancel := context.WithTimeout(context.Background(), sys.Configs.Events.ShutdownTimeout)
+			defer stdCancel()
+
+			if err := topic.Shutdown(stdCtx); err != nil {
+				log.Errorf("could not stop imports topic gracefully: %s", err)
+			}
+		}()
+		sys.R.Imports = topic
+	}
+
+	// =======================================================================================================
+	// Search
+
+	switch sys.Configs.Search.Engine {
+	case "database":
+	case "embedded":
+		lang, ok := search.ParseLanguage(sys.Configs.Search.Language)
+		if !ok {
+			return fmt.Errorf("invalid search language: %s", sys.Configs.Search.Language)
+		}
+		index, err := search.Load(sys.Configs.Search.IndexPath, lang)
+		if err != nil {
+			log.Infow("search", "status", "rebuilding index", "reason", err)
+			sys.R.Search = search.New(lang)
+			indexed, err := note.Reindex(context.Background())
+			if err != nil {
+				return fmt.Errorf("could not build search index: %w", err)
+			}
+			log.Infof("indexed %d notes", indexed)
+		} else {
+			sys.R.Search = index
+		}
+		defer func() {
+			if err := sys.R.Search.Save(sys.Configs.Search.IndexPath); err != nil {
+				log.Errorf("could not save search index: %s", err)
+			}
+		}()
+
+		if sys.Configs.Search.ReindexInterval > 0 {
+			withCancel, cancelFunc := context.WithCancel(context.Background())
+			defer cancelFunc()
+			go indexer.Reindex(withCancel, sys.Configs.Search.ReindexInterval, sys.Configs.Search.IndexPath)
+		}
+	default:
+		return fmt.Errorf("invalid search engine: %s", sys.Configs.Search.Engine)
+	}
+
 	// =======================================================================================================
 	// NR
 
@@ -156,7 +274,28 @@ func run(log *zap.SugaredLogger) error {
 	}), gin.Recovery(), nrgin.Middleware(nrApp))
 
 	handlers.MapDefaults(router)
-	handlers.MapApi(router)
+
+	api := router.Group("")
+	if sys.Configs.RateLimit.Enabled {
+		key, err := ratelimit.ParseKeys(sys.Configs.RateLimit.Keys)
+		if err != nil {
+			return err
+		}
+		limiter, err := ratelimit.New(ratelimit.Config{
+			Log:     log,
+			Cache:   rdb,
+			Timeout: sys.Configs.Cache.OperationTimeout,
+			Limit:   sys.Configs.RateLimit.Limit,
+			Period:  sys.Configs.RateLimit.Period,
+			Burst:   sys.Configs.RateLimit.Burst,
+			Key:     key,
+		})
+		if err != nil {
+			return err
+		}
+		api.Use(limiter)
+	}
+	handlers.MapApi(api)
 
 	docs.SwaggerInfo.Host = sys.Configs.Swagger.Host
 	url := ginSwagger.URL(fmt.Sprintf("%s://%s/swagger/doc.json", sys.Configs.Swagger.Protocol, sys.Configs.Swagger.Host))
@@ -167,7 +306,7 @@ func run(log *zap.SugaredLogger) error {
 
 	svr := &http.Server{
 		Addr:         fmt.Sprintf(":%s", sys.Configs.Http.Port),
-		Handler:      router,
+		Handler:      conn.Handler(router),
 		ReadTimeout:  sys.Configs.Http.ReadTimeout,
 		WriteTimeout: sys.Configs.Http.WriteTimeout,
 		IdleTimeout:  sys.Configs.Http.IdleTimeout,
@@ -0,0 +1,262 @@
+package tests
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ribgsilva/note-api/app/api/handlers/v1/notes"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/platform/web/conn"
+	"github.com/ribgsilva/note-api/sys"
+	"gocloud.dev/pubsub/mempubsub"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func (nt *NoteTests) batch(t *testing.T) {
+	// operations are applied on their own, each one with its result
+	res := nt.postBatch(t, `{"operations":[
+		{"op":"create","note":{"title":"batch","text":"batch text","tags":["Batch"]}},
+		{"op":"create","note":{"text":"no title"}},
+		{"op":"delete","id":999999},
+		{"op":"move","id":1}
+	]}`, http.StatusOK)
+	batchStatuses(t, res, http.StatusCreated, http.StatusBadRequest, http.StatusNotFound, http.StatusBadRequest)
+	if res.Applied != 1 || res.Failed != 3 {
+		t.Fatalf("Test batch: Should have counted the operations applied and failed: %+v", res)
+	}
+	created := res.Results[0].Note
+	if created == nil || created.Id == 0 || created.Version != 1 || !reflect.DeepEqual(created.Tags, []string{"batch"}) {
+		t.Fatalf("Test batch: Should have received the created note: %+v", created)
+	}
+	path := fmt.Sprintf("/v1/notes/%d", created.Id)
+
+	res = nt.postBatch(t, fmt.Sprintf(`{"operations":[
+		{"op":"update","id":%d,"expectedVersion":9,"note":{"title":"batch","text":"stale"}},
+		{"op":"update","id":%d,"expectedVersion":1,"note":{"title":"batch","text":"updated"}}
+	]}`, created.Id, created.Id), http.StatusOK)
+	batchStatuses(t, res, http.StatusPreconditionFailed, http.StatusOK)
+	if n := res.Results[1].Note; n == nil || n.Text != "updated" || n.Version != 2 {
+		t.Fatalf("Test batch: Should have received the updated note: %+v", n)
+	}
+
+	// transactional batches apply every operation or none
+	res = nt.postBatch(t, fmt.Sprintf(`{"transactional":true,"operations":[
+		{"op":"update","id":%d,"expectedVersion":2,"note":{"title":"batch","text":"rolled back"}},
+		{"op":"delete","id":999999}
+	]}`, created.Id), http.StatusNotFound)
+	batchStatuses(t, res, http.StatusFailedDependency, http.StatusNotFound)
+	res = nt.postBatch(t, fmt.Sprintf(`{"transactional":true,"operations":[
+		{"op":"update","id":%d,"note":{"title":"batch","text":"invalid"}},
+		{"op":"update","note":{"title":"batch"}}
+	]}`, created.Id), http.StatusBadRequest)
+	batchStatuses(t, res, http.StatusFailedDependency, http.StatusBadRequest)
+	if n := nt.findNote(t, path); n.Text != "updated" || n.Version != 2 {
+		t.Fatalf("Test batch: Should not have applied the operations of failed transactional batches: %+v", n)
+	}
+
+	topic := mempubsub.NewTopic()
+	subscription := mempubsub.NewSubscription(topic, time.Second)
+	sys.R.Events = topic
+	defer func() {
+		sys.R.Events = nil
+		_ = subscription.Shutdown(context.Background())
+		_ = topic.Shutdown(context.Background())
+	}()
+
+	res = nt.postBatch(t, fmt.Sprintf(`{"transactional":true,"operations":[
+		{"op":"create","note":{"title":"transactional","text":"transactional text"}},
+		{"op":"update","id":%d,"expectedVersion":2,"note":{"title":"batch","text":"transactional"}},
+		{"op":"delete","id":%d,"expectedVersion":3}
+	]}`, created.Id, created.Id), http.StatusOK)
+	batchStatuses(t, res, http.StatusCreated, http.StatusOK, http.StatusNoContent)
+	nt.getETag(t, path, "", http.StatusNotFound)
+
+	// events are published once the batch is committed, delivered in any order by the memory topic
+	events := map[string]changeEvent{}
+	for i := 0; i < 3; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		m, err := subscription.Receive(ctx)
+		cancel()
+		if err != nil {
+			t.Fatalf("Test batch: Should have received the events of the batch: %v", err)
+		}
+		m.Ack()
+		var e changeEvent
+		if err := json.Unmarshal(m.Body, &e); err != nil {
+			t.Fatalf("Test batch: Should be able to unmarshal the event : %v", err)
+		}
+		events[e.Type] = e
+	}
+	if e := events[note.Created]; e.Data.After == nil || e.Data.After.Title != "transactional" {
+		t.Fatalf("Test batch: Should have published the created note: %+v", e.Data)
+	}
+	if e := events[note.Updated]; e.Data.Before == nil || e.Data.Before.Text != "updated" || e.Data.After.Text != "transactional" {
+		t.Fatalf("Test batch: Should have published the updated note: %+v", e.Data)
+	}
+	if e := events[note.Deleted]; e.Data.Before == nil || e.Data.Before.Version != 3 || e.Data.After != nil {
+		t.Fatalf("Test batch: Should have published the deleted note: %+v", e.Data)
+	}
+
+	// limits
+	ops := strings.Repeat(`{"op":"delete","id":999999},`, sys.Configs.Batch.MaxOperations)
+	nt.sendBatch(t, "application/json", "", `{"operations":[`+ops+`{"op":"delete","id":999999}]}`, http.StatusRequestEntityTooLarge)
+	nt.sendBatch(t, "application/json", "", `{"operations":[]}`, http.StatusBadRequest)
+	nt.sendBatch(t, "application/json", "", `{"operations":[{"op":"create","note":{"title":"big","text":"`+strings.Repeat("a", sys.Configs.Batch.MaxBytes)+`"}}]}`, http.StatusRequestEntityTooLarge)
+
+	nt.batchStream(t)
+
+	r := httptest.NewRequest(http.MethodPost, "/v1/notesX", bytes.NewBufferString(`{"operations":[]}`))
+	w := httptest.NewRecorder()
+	nt.app.ServeHTTP(w, r)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("Test batch: Should receive a status code of 404 for other actions : %v", w.Code)
+	}
+}
+
+// batchStream sends batches with one operation per line
+func (nt *NoteTests) batchStream(t *testing.T) {
+	w := nt.sendBatch(t, "application/x-ndjson", "", `{"op":"create","note":{"title":"streamed","text":"streamed text"}}
+not an operation
+
+{"op":"delete","id":999999}
+`, http.StatusOK)
+	results := streamResults(t, w)
+	streamStatuses(t, results, http.StatusCreated, http.StatusBadRequest, http.StatusNotFound)
+	if results[0].Note == nil || results[0].Note.Title != "streamed" {
+		t.Fatalf("Test batchStream: Should have received the created note: %+v", results[0])
+	}
+
+	// operations past the limit are not applied
+	lines := strings.Repeat(`{"op":"delete","id":999999}`+"\n", sys.Configs.Batch.MaxStreamOperations+5)
+	results = streamResults(t, nt.sendBatch(t, "application/x-ndjson", "", lines, http.StatusOK))
+	if len(results) != sys.Configs.Batch.MaxStreamOperations+1 || results[len(results)-1].Status != http.StatusRequestEntityTooLarge {
+		t.Fatalf("Test batchStream: Should have stopped at the max operations: %v", len(results))
+	}
+	results = streamResults(t, nt.sendBatch(t, "application/x-ndjson", "", `{"op":"create","note":{"title":"long","text":"`+strings.Repeat("a", sys.Configs.Batch.MaxBytes)+`"}}`, http.StatusOK))
+	streamStatuses(t, results, http.StatusRequestEntityTooLarge)
+
+	w = nt.sendBatch(t, "application/x-ndjson", "?transactional=true", `{"op":"create","note":{"title":"streamed one","text":"text"}}
+{"op":"create","note":{"title":"streamed two","text":"text"}}`, http.StatusOK)
+	streamStatuses(t, streamResults(t, w), http.StatusCreated, http.StatusCreated)
+	w = nt.sendBatch(t, "application/x-ndjson", "?transactional=true", `{"op":"create","note":{"title":"streamed three","text":"text"}}
+{"op":"update","id":999999,"note":{"title":"missing"}}`, http.StatusNotFound)
+	streamStatuses(t, streamResults(t, w), http.StatusFailedDependency, http.StatusNotFound)
+
+	nt.batchStreamServer(t)
+}
+
+// batchStreamServer streams a batch to a server for longer than its timeouts, the results being written as the lines are read
+func (nt *NoteTests) batchStreamServer(t *testing.T) {
+	readTimeout, writeTimeout := sys.Configs.Http.ReadTimeout, sys.Configs.Http.WriteTimeout
+	defer func() {
+		sys.Configs.Http.ReadTimeout, sys.Configs.Http.WriteTimeout = readTimeout, writeTimeout
+	}()
+	sys.Configs.Http.ReadTimeout, sys.Configs.Http.WriteTimeout = 300*time.Millisecond, 300*time.Millisecond
+
+	svr := httptest.NewUnstartedServer(conn.Handler(nt.app))
+	svr.Config.ReadTimeout, svr.Config.WriteTimeout = sys.Configs.Http.ReadTimeout, sys.Configs.Http.WriteTimeout
+	svr.Start()
+	defer svr.Close()
+
+	body, lines := io.Pipe()
+	go func() {
+		for i := 0; i < 5; i++ {
+			_, _ = fmt.Fprintf(lines, `{"op":"delete","id":999999}`+"\n")
+			time.Sleep(100 * time.Millisecond)
+		}
+		_ = lines.Close()
+	}()
+	res, err := http.Post(svr.URL+"/v1/notes/batch", "application/x-ndjson", body)
+	if err != nil {
+		t.Fatalf("Test batchStreamServer: Should be able to send the batch : %v", err)
+	}
+	defer res.Body.Close()
+
+	var statuses []int
+	results := bufio.NewScanner(res.Body)
+	for results.Scan() {
+		var r notes.OperationResult
+		if err := json.Unmarshal(results.Bytes(), &r); err != nil {
+			t.Fatalf("Test batchStreamServer: Should be able to unmarshal the result : %v", err)
+		}
+		statuses = append(statuses, r.Status)
+	}
+	if err := results.Err(); err != nil || !reflect.DeepEqual(statuses, []int{404, 404, 404, 404, 404}) {
+		t.Fatalf("Test batchStreamServer: Should have received the result of every line: %v %v", statuses, err)
+	}
+}
+
+func (nt *NoteTests) postBatch(t *testing.T, body string, status int) notes.BatchResponse {
+	w := nt.sendBatch(t, "application/json", "", body, status)
+
+	var res notes.BatchResponse
+	if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+		t.Fatalf("Test postBatch: Should be able to unmarshal the response : %v", err)
+	}
+	return res
+}
+
+func (nt *NoteTests) sendBatch(t *testing.T, contentType, query, body string, status int) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, "/v1/notes/batch"+query, bytes.NewBufferString(body))
+	r.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+
+	nt.app.ServeHTTP(w, r)
+
+	if w.Code != status {
+		t.Fatalf("Test sendBatch: Should receive a status code of %d for the response : %v %s", status, w.Code, w.Body.String())
+	}
+	return w
+}
+
+func (nt *NoteTests) findNote(t *testing.T, path string) note.Note {
+	r := httptest.NewRequest(http.MethodGet, path, nil)
+	w := httptest.NewRecorder()
+
+	nt.app.ServeHTTP(w, r)
+
+	var n note.Note
+	if err := json.NewDecoder(w.Body).Decode(&n); err != nil {
+		t.Fatalf("Test findNote: Should be able to unmarshal the response : %v", err)
+	}
+	return n
+}
+
+func streamResults(t *testing.T, w *httptest.ResponseRecorder) []notes.OperationResult {
+	if w.Header().Get("Content-Type") != "application/x-ndjson" {
+		t.Fatalf("Test streamResults: Should have received one result per line: %v", w.Header())
+	}
+	var results []notes.OperationResult
+	lines := bufio.NewScanner(w.Body)
+	for lines.Scan() {
+		var r notes.OperationResult
+		if err := json.Unmarshal(lines.Bytes(), &r); err != nil {
+			t.Fatalf("Test streamResults: Should be able to unmarshal the result : %v", err)
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+func batchStatuses(t *testing.T, res notes.BatchResponse, statuses ...int) {
+	streamStatuses(t, res.Results, statuses...)
+}
+
+func streamStatuses(t *testing.T, results []notes.OperationResult, statuses ...int) {
+	if len(results) != len(statuses) {
+		t.Fatalf("Test batch: Should have received %d results: %+v", len(statuses), results)
+	}
+	for i, r := range results {
+		if r.Index != i || r.Status != statuses[i] {
+			t.Fatalf("Test batch: Should have received the status %d for the operation %d: %+v", statuses[i], i, r)
+		}
+	}
+}
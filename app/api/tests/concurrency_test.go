@@ -0,0 +1,59 @@
+package tests
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func (nt *NoteTests) concurrency(t *testing.T) {
+	tag := nt.getETag(t, "/v1/notes/1", "", http.StatusOK)
+	nt.getETag(t, "/v1/notes/1", tag, http.StatusNotModified)
+	nt.getETag(t, "/v1/notes/1", `W/"999"`, http.StatusOK)
+
+	nt.changeNote(t, http.MethodPatch, "/v1/notes/1", `{"text":"patched"}`, tag, http.StatusOK)
+	nt.changeNote(t, http.MethodPatch, "/v1/notes/1", `{"text":"stale"}`, tag, http.StatusPreconditionFailed)
+	nt.changeNote(t, http.MethodPut, "/v1/notes/1", `{"title":"my notes","text":"stale"}`, tag, http.StatusPreconditionFailed)
+	nt.changeNote(t, http.MethodPut, "/v1/notes/1", `{"title":"my notes","text":"stale"}`, "not-a-tag", http.StatusBadRequest)
+
+	newTag := nt.getETag(t, "/v1/notes/1", "", http.StatusOK)
+	if newTag == tag {
+		t.Fatalf("Test concurrency: Should have received a new ETag after the patch: %v", newTag)
+	}
+
+	deleteTag := nt.getETag(t, "/v1/notes/2", "", http.StatusOK)
+	nt.changeNote(t, http.MethodDelete, "/v1/notes/2", "", `"99"`, http.StatusPreconditionFailed)
+	nt.changeNote(t, http.MethodDelete, "/v1/notes/2", "", deleteTag, http.StatusNoContent)
+	nt.getETag(t, "/v1/notes/2", "", http.StatusNotFound)
+}
+
+func (nt *NoteTests) getETag(t *testing.T, path, ifNoneMatch string, status int) string {
+	r := httptest.NewRequest(http.MethodGet, path, nil)
+	if ifNoneMatch != "" {
+		r.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	w := httptest.NewRecorder()
+
+	nt.app.ServeHTTP(w, r)
+
+	if w.Code != status {
+		t.Fatalf("Test getETag: Should receive a status code of %d for the response : %v", status, w.Code)
+	}
+	if status != http.StatusNotFound && w.Header().Get("ETag") == "" {
+		t.Fatalf("Test getETag: Should have received an ETag header: %v", w.Header())
+	}
+	return w.Header().Get("ETag")
+}
+
+func (nt *NoteTests) changeNote(t *testing.T, method, path, body, ifMatch string, status int) {
+	r := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	r.Header.Set("If-Match", ifMatch)
+	w := httptest.NewRecorder()
+
+	nt.app.ServeHTTP(w, r)
+
+	if w.Code != status {
+		t.Fatalf("Test changeNote: Should receive a status code of %d for %s %s : %v", status, method, path, w.Code)
+	}
+}
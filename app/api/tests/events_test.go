@@ -0,0 +1,73 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/sys"
+	"gocloud.dev/pubsub"
+	"gocloud.dev/pubsub/mempubsub"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// changeEvent is a note.Event holding the note.Change published on note changes
+type changeEvent struct {
+	Type string      `json:"type"`
+	Data note.Change `json:"data"`
+}
+
+func (nt *NoteTests) events(t *testing.T) {
+	topic := mempubsub.NewTopic()
+	subscription := mempubsub.NewSubscription(topic, time.Second)
+	sys.R.Events = topic
+	defer func() {
+		sys.R.Events = nil
+		_ = subscription.Shutdown(context.Background())
+		_ = topic.Shutdown(context.Background())
+	}()
+
+	nt.createNote(t, `{"title":"published","text":"published text"}`, http.StatusCreated)
+	created := receiveEvent(t, subscription, note.Created, "")
+	if created.Data.Before != nil || created.Data.After == nil || created.Data.After.Title != "published" {
+		t.Fatalf("Test events: Should have published the created note: %+v", created.Data)
+	}
+	path := fmt.Sprintf("/v1/notes/%d", created.Data.After.Id)
+
+	nt.changeNote(t, http.MethodPut, path, `{"title":"published","text":"changed text"}`, "", http.StatusOK)
+	updated := receiveEvent(t, subscription, note.Updated, "")
+	if updated.Data.Before == nil || updated.Data.Before.Text != "published text" || updated.Data.After == nil || updated.Data.After.Text != "changed text" {
+		t.Fatalf("Test events: Should have published the note before and after the update: %+v", updated.Data)
+	}
+
+	nt.changeNote(t, http.MethodDelete, path, "", "", http.StatusNoContent)
+	deleted := receiveEvent(t, subscription, note.Deleted, "")
+	if deleted.Data.Before == nil || deleted.Data.Before.Version != 2 || deleted.Data.After != nil {
+		t.Fatalf("Test events: Should have published the deleted note: %+v", deleted.Data)
+	}
+}
+
+func receiveEvent(t *testing.T, subscription *pubsub.Subscription, eventType, author string) changeEvent {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	m, err := subscription.Receive(ctx)
+	if err != nil {
+		t.Fatalf("Test receiveEvent: Should have received a %s event: %v", eventType, err)
+	}
+	m.Ack()
+
+	var e changeEvent
+	if err := json.Unmarshal(m.Body, &e); err != nil {
+		t.Fatalf("Test receiveEvent: Should be able to unmarshal the event : %v", err)
+	}
+	if e.Type != eventType || m.Metadata["type"] != eventType {
+		t.Fatalf("Test receiveEvent: Should have received a %s event: %s", eventType, e.Type)
+	}
+	if m.Metadata["user"] != author {
+		t.Fatalf("Test receiveEvent: Should have received %q as user: %q", author, m.Metadata["user"])
+	}
+	return e
+}
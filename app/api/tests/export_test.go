@@ -0,0 +1,130 @@
+package tests
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func (nt *NoteTests) export(t *testing.T) {
+	first := nt.createNoteAs(t, "exporter", `{"title":"exported: first","text":"first text","tags":["export","work"]}`)
+	second := nt.createNoteAs(t, "exporter", `{"title":"exported second","text":"second\ntext"}`)
+	trashed := nt.createNoteAs(t, "exporter", `{"title":"exported trashed","text":"trashed text"}`)
+	nt.changeNote(t, http.MethodDelete, fmt.Sprintf("/v1/notes/%d", trashed.Id), "", "", http.StatusNoContent)
+
+	// only the notes of the caller out of the trash, in the order they were created
+	w := nt.getExport(t, "", "exporter", http.StatusOK)
+	var exported []note.Note
+	if err := json.NewDecoder(w.Body).Decode(&exported); err != nil {
+		t.Fatalf("Test export: Should be able to unmarshal the json export : %v", err)
+	}
+	if len(exported) != 2 || !reflect.DeepEqual(exported[0], first) || !reflect.DeepEqual(exported[1], second) {
+		t.Fatalf("Test export: Should have exported the notes of the caller: %+v", exported)
+	}
+	if w.Header().Get("Content-Disposition") != `attachment; filename="notes.json"` {
+		t.Fatalf("Test export: Should have received the file name of the export: %v", w.Header())
+	}
+
+	w = nt.getExport(t, "?format=ndjson", "exporter", http.StatusOK)
+	lines := bufio.NewScanner(w.Body)
+	var count int
+	for ; lines.Scan(); count++ {
+		var n note.Note
+		if err := json.Unmarshal(lines.Bytes(), &n); err != nil || n.Title != exported[count].Title {
+			t.Fatalf("Test export: Should have exported a note per line: %s", lines.Text())
+		}
+	}
+	if count != 2 {
+		t.Fatalf("Test export: Should have exported the notes of the caller as ndjson: %v", count)
+	}
+
+	w = nt.getExport(t, "?format=csv", "exporter", http.StatusOK)
+	rows, err := csv.NewReader(w.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("Test export: Should be able to read the csv export : %v", err)
+	}
+	if len(rows) != 3 || rows[0][1] != "title" || rows[1][1] != "exported: first" || rows[1][3] != "export,work" || rows[2][2] != "second\ntext" {
+		t.Fatalf("Test export: Should have exported a row per note after the header: %v", rows)
+	}
+
+	w = nt.getExport(t, "?format=markdown", "exporter", http.StatusOK)
+	archive, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	if err != nil {
+		t.Fatalf("Test export: Should be able to open the markdown export : %v", err)
+	}
+	if len(archive.File) != 2 || archive.File[0].Name != fmt.Sprintf("%d-exported-first.md", first.Id) {
+		t.Fatalf("Test export: Should have exported a file per note: %v", archive.File)
+	}
+	f, err := archive.File[0].Open()
+	if err != nil {
+		t.Fatalf("Test export: Should be able to open the exported note : %v", err)
+	}
+	content, _ := io.ReadAll(f)
+	_ = f.Close()
+	if !strings.HasPrefix(string(content), "---\ntitle: \"exported: first\"\ntags: [\"export\",\"work\"]\ncreatedAt: ") ||
+		!strings.HasSuffix(string(content), "---\n\nfirst text\n") {
+		t.Fatalf("Test export: Should have exported the note with its front matter: %s", content)
+	}
+
+	// anonymous callers can not export
+	nt.getExport(t, "", "", http.StatusUnauthorized)
+	w = nt.getExport(t, "", "nobody", http.StatusOK)
+	if strings.TrimSpace(w.Body.String()) != "[]" {
+		t.Fatalf("Test export: Should have exported no note: %s", w.Body.String())
+	}
+
+	nt.getExport(t, "?format=pdf", "exporter", http.StatusBadRequest)
+
+	// the notes created without an author are exported on their own
+	anonymous := nt.createNoteAs(t, "", `{"title":"exported anonymous","text":"anonymous text"}`)
+	var b bytes.Buffer
+	if err := note.ExportAnonymous(context.Background(), &b, note.ExportNDJSON); err != nil {
+		t.Fatalf("Test export: Should be able to export the anonymous notes : %v", err)
+	}
+	if !strings.Contains(b.String(), `"id":`+fmt.Sprint(anonymous.Id)+`,`) || strings.Contains(b.String(), "exported second") {
+		t.Fatalf("Test export: Should have exported only the notes without an author: %s", b.String())
+	}
+}
+
+func (nt *NoteTests) createNoteAs(t *testing.T, user, body string) note.Note {
+	r := httptest.NewRequest(http.MethodPost, "/v1/notes", bytes.NewBufferString(body))
+	r.Header.Set("X-User-Id", user)
+	w := httptest.NewRecorder()
+
+	nt.app.ServeHTTP(w, r)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Test createNoteAs: Should receive a status code of 201 for the response : %v", w.Code)
+	}
+	var created note.Note
+	if err := json.NewDecoder(w.Body).Decode(&created); err != nil {
+		t.Fatalf("Test createNoteAs: Should be able to unmarshal the response : %v", err)
+	}
+	return created
+}
+
+func (nt *NoteTests) getExport(t *testing.T, query, user string, status int) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, "/v1/notes/export"+query, nil)
+	if user != "" {
+		r.Header.Set("X-User-Id", user)
+	}
+	w := httptest.NewRecorder()
+
+	nt.app.ServeHTTP(w, r)
+
+	if w.Code != status {
+		t.Fatalf("Test getExport: Should receive a status code of %d for the response : %v", status, w.Code)
+	}
+	return w
+}
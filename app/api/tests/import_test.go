@@ -0,0 +1,234 @@
+package tests
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ribgsilva/note-api/app/api/handlers/v1/notes"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/sys"
+	"gocloud.dev/pubsub/mempubsub"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const enex = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE en-export SYSTEM "http://xml.evernote.com/pub/evernote-export3.dtd">
+<en-export export-date="20220101T000000Z" application="Evernote">
+  <note>
+    <title>enex note</title>
+    <content><![CDATA[<?xml version="1.0" encoding="UTF-8"?><!DOCTYPE en-note SYSTEM "http://xml.evernote.com/pub/enml2.dtd">
+<en-note><div>first line</div><div>caf&eacute; &amp; more<br/></div><ul><li>one</li><li>two</li></ul></en-note>]]></content>
+    <created>20220101T000000Z</created>
+    <tag>Evernote</tag>
+    <tag>work</tag>
+    <resource><data encoding="base64">aGVsbG8=</data></resource>
+  </note>
+  <note>
+    <title></title>
+    <content><![CDATA[<en-note>untitled</en-note>]]></content>
+  </note>
+</en-export>`
+
+func (nt *NoteTests) imports(t *testing.T) {
+	// front matter as the markdown exports, headings and file names as titles
+	exported := nt.createNoteAs(t, "importer", `{"title":"to export and import","text":"round trip","tags":["trip"]}`)
+	archive := importZip(t, map[string]string{
+		"folder/first.md":      "---\ntitle: \"imported: first\"\ntags: [\"Import\", \"work\"]\ncreatedAt: 2022-01-01T00:00:00Z\n---\n\nfirst text\n",
+		"folder/heading.md":    "# From heading\n\nheading text\n",
+		"block tags.md":        "---\ntitle: 'block tags'\ntags:\n  - one\n  - two\n---\nblock text",
+		"file name.md":         "just text",
+		"exported.md":          string(note.Markdown(exported)),
+		"invalid notebook.md":  "---\ntitle: notebook\nnotebookId: 999999\n---\ntext",
+		"too many tags.md":     "---\ntitle: tags\ntags: [" + strings.Repeat("t,", 20) + "t]\n---\n",
+		"ignored.txt":          "not markdown",
+		"__MACOSX/._first.md":  "resource fork",
+		"folder/.hidden.md":    "hidden",
+		"folder/long title.md": "# " + strings.Repeat("a", 150),
+	})
+
+	res := nt.postImport(t, "?format=markdown", "", archive, "importer", http.StatusOK)
+	results := map[string]note.ImportResult{}
+	for _, r := range res.Results {
+		results[r.File] = r
+	}
+	if len(res.Results) != 8 || res.Created != 6 || res.Duplicates != 0 || res.Failed != 2 {
+		t.Fatalf("Test imports: Should have imported the markdown files: %+v", res)
+	}
+	for file, want := range map[string]note.NewNote{
+		"folder/first.md":      {Title: "imported: first", Text: "first text", Tags: []string{"import", "work"}},
+		"folder/heading.md":    {Title: "From heading", Text: "# From heading\n\nheading text"},
+		"block tags.md":        {Title: "block tags", Text: "block text", Tags: []string{"one", "two"}},
+		"file name.md":         {Title: "file name", Text: "just text"},
+		"exported.md":          {Title: exported.Title, Text: exported.Text, Tags: exported.Tags},
+		"folder/long title.md": {Title: strings.Repeat("a", 100), Text: "# " + strings.Repeat("a", 150)},
+	} {
+		r := results[file]
+		if r.Status != note.ImportCreated || r.Id == 0 {
+			t.Fatalf("Test imports: Should have created the note of %s: %+v", file, r)
+		}
+		n := nt.findNote(t, fmt.Sprintf("/v1/notes/%d", r.Id))
+		if n.Title != want.Title || n.Text != want.Text || !reflect.DeepEqual(n.Tags, want.Tags) {
+			t.Fatalf("Test imports: Should have imported %s: %+v", file, n)
+		}
+	}
+	if r := results["invalid notebook.md"]; r.Status != note.ImportFailed || r.Error != note.ErrNotebookNotFound.Error() {
+		t.Fatalf("Test imports: Should have failed the note of a missing notebook: %+v", r)
+	}
+	if r := results["too many tags.md"]; r.Status != note.ImportFailed || r.Error == "" {
+		t.Fatalf("Test imports: Should have failed the note with too many tags: %+v", r)
+	}
+
+	// the same content is only imported once
+	again := nt.postImport(t, "", "notes.zip", archive, "importer", http.StatusOK)
+	if again.Created != 0 || again.Duplicates != 6 || again.Failed != 2 {
+		t.Fatalf("Test imports: Should not have imported the notes again: %+v", again)
+	}
+	for _, r := range again.Results {
+		if r.Status == note.ImportDuplicate && r.Id != results[r.File].Id {
+			t.Fatalf("Test imports: Should have received the note imported before: %+v", r)
+		}
+	}
+
+	// other users import the same content, and notes in the trash are imported again
+	other := nt.postImport(t, "", "notes.zip", archive, "other importer", http.StatusOK)
+	if other.Created != 6 || other.Duplicates != 0 {
+		t.Fatalf("Test imports: Should have imported the notes of another user: %+v", other)
+	}
+	nt.changeNote(t, http.MethodDelete, fmt.Sprintf("/v1/notes/%d", results["file name.md"].Id), "", "", http.StatusNoContent)
+	trashed := nt.postImport(t, "?format=markdown", "", importZip(t, map[string]string{"file name.md": "just text"}), "importer", http.StatusOK)
+	if trashed.Created != 1 || trashed.Results[0].Id == results["file name.md"].Id {
+		t.Fatalf("Test imports: Should have imported the note in the trash again: %+v", trashed)
+	}
+
+	res = nt.postImport(t, "", "export.enex", []byte(enex), "importer", http.StatusOK)
+	if len(res.Results) != 2 || res.Created != 1 || res.Failed != 1 || res.Results[0].File != "enex note" {
+		t.Fatalf("Test imports: Should have imported the enex notes: %+v", res)
+	}
+	n := nt.findNote(t, fmt.Sprintf("/v1/notes/%d", res.Results[0].Id))
+	if n.Text != "first line\ncafé & more\n\n- one\n- two" || !reflect.DeepEqual(n.Tags, []string{"evernote", "work"}) {
+		t.Fatalf("Test imports: Should have converted the enex note to text: %q %v", n.Text, n.Tags)
+	}
+
+	// invalid imports
+	nt.postImport(t, "", "", []byte(enex), "", http.StatusBadRequest)
+	nt.postImport(t, "?format=pdf", "", archive, "", http.StatusBadRequest)
+	nt.postImport(t, "?format=markdown", "", []byte("not a zip"), "", http.StatusBadRequest)
+	nt.postImport(t, "?format=enex", "", archive, "", http.StatusBadRequest)
+	nt.postImport(t, "?format=markdown", "", importZip(t, map[string]string{"a.txt": "a"}), "", http.StatusBadRequest)
+	nt.postImport(t, "?format=enex", "", bytes.Repeat([]byte("a"), sys.Configs.Import.MaxBytes+1), "", http.StatusRequestEntityTooLarge)
+	many := map[string]string{}
+	for i := 0; i <= 5000; i++ {
+		many[fmt.Sprintf("%d.md", i)] = ""
+	}
+	if _, err := note.ReadImport(note.ImportMarkdown, importZip(t, many)); !errors.Is(err, note.ErrImportTooLarge) {
+		t.Fatalf("Test imports: Should have rejected the zip with too many files: %v", err)
+	}
+
+	nt.queuedImport(t)
+}
+
+// queuedImport sends imports with more notes than the threshold to the imports topic
+func (nt *NoteTests) queuedImport(t *testing.T) {
+	topic := mempubsub.NewTopic()
+	subscription := mempubsub.NewSubscription(topic, time.Second)
+	sys.R.Imports = topic
+	defer func() {
+		sys.R.Imports = nil
+		_ = subscription.Shutdown(context.Background())
+		_ = topic.Shutdown(context.Background())
+	}()
+
+	files := map[string]string{"invalid.md": "---\ntitle: x\nnotebookId: abc\n---\n"}
+	for i := 0; i <= sys.Configs.Import.QueueThreshold; i++ {
+		files[fmt.Sprintf("queued %d.md", i)] = fmt.Sprintf("queued text %d", i)
+	}
+	res := nt.postImport(t, "?format=markdown", "", importZip(t, files), "queuer", http.StatusAccepted)
+	if res.Queued != sys.Configs.Import.QueueThreshold+1 || res.Failed != 1 {
+		t.Fatalf("Test queuedImport: Should have queued the notes: %+v", res)
+	}
+
+	for i := 0; i < res.Queued; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		m, err := subscription.Receive(ctx)
+		cancel()
+		if err != nil {
+			t.Fatalf("Test queuedImport: Should have received the queued notes: %v", err)
+		}
+		m.Ack()
+		var e struct {
+			Type string          `json:"type"`
+			Data note.ImportNote `json:"data"`
+		}
+		if err := json.Unmarshal(m.Body, &e); err != nil {
+			t.Fatalf("Test queuedImport: Should be able to unmarshal the message : %v", err)
+		}
+		if e.Type != note.ImportType || !strings.HasPrefix(e.Data.File, "queued ") || e.Data.Title != strings.TrimSuffix(e.Data.File, ".md") ||
+			m.Metadata["user"] != "queuer" || m.Metadata["orderingKey"] != note.ContentHash(e.Data.NewNote, "queuer") {
+			t.Fatalf("Test queuedImport: Should have queued the note with its author and content hash: %+v %v", e, m.Metadata)
+		}
+	}
+
+	// small imports are still done on the request
+	res = nt.postImport(t, "?format=markdown", "", importZip(t, map[string]string{"small.md": "small"}), "queuer", http.StatusOK)
+	if res.Created != 1 {
+		t.Fatalf("Test queuedImport: Should have imported the small import: %+v", res)
+	}
+}
+
+// postImport sends an import as the file of a multipart form when it has a name, or else as the body
+func (nt *NoteTests) postImport(t *testing.T, query, name string, content []byte, user string, status int) notes.ImportResponse {
+	body := bytes.NewBuffer(content)
+	contentType := "application/octet-stream"
+	if name != "" {
+		body = &bytes.Buffer{}
+		form := multipart.NewWriter(body)
+		f, _ := form.CreateFormFile("file", name)
+		_, _ = f.Write(content)
+		_ = form.Close()
+		contentType = form.FormDataContentType()
+	}
+	r := httptest.NewRequest(http.MethodPost, "/v1/notes/import"+query, body)
+	r.Header.Set("Content-Type", contentType)
+	if user != "" {
+		r.Header.Set("X-User-Id", user)
+	}
+	w := httptest.NewRecorder()
+
+	nt.app.ServeHTTP(w, r)
+
+	if w.Code != status {
+		t.Fatalf("Test postImport: Should receive a status code of %d for the response : %v %s", status, w.Code, w.Body.String())
+	}
+	var res notes.ImportResponse
+	if status == http.StatusOK || status == http.StatusAccepted {
+		if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+			t.Fatalf("Test postImport: Should be able to unmarshal the response : %v", err)
+		}
+	}
+	return res
+}
+
+func importZip(t *testing.T, files map[string]string) []byte {
+	var b bytes.Buffer
+	archive := zip.NewWriter(&b)
+	for name, content := range files {
+		f, err := archive.Create(name)
+		if err != nil {
+			t.Fatalf("Test importZip: Should be able to create the file : %v", err)
+		}
+		_, _ = f.Write([]byte(content))
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatalf("Test importZip: Should be able to close the zip : %v", err)
+	}
+	return b.Bytes()
+}
@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/ribgsilva/note-api/business/v1/link"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func (nt *NoteTests) sharedLink(t *testing.T) {
+	created := nt.createLink201(t, link.NewLink{MaxViews: 1, Password: "secret"})
+
+	nt.getShared(t, created.Token, "", http.StatusUnauthorized)
+	nt.getShared(t, created.Token, "wrong", http.StatusUnauthorized)
+	nt.getShared(t, created.Token, "secret", http.StatusOK)
+	nt.getShared(t, created.Token, "secret", http.StatusGone)
+	nt.getShared(t, created.Token+"x", "secret", http.StatusNotFound)
+
+	other := nt.createLink201(t, link.NewLink{})
+	nt.getShared(t, other.Token, "", http.StatusOK)
+	nt.revokeLink(t, other.Id, http.StatusNoContent)
+	nt.revokeLink(t, other.Id, http.StatusNotFound)
+	nt.getShared(t, other.Token, "", http.StatusNotFound)
+}
+
+func (nt *NoteTests) createLink201(t *testing.T, newL link.NewLink) link.Link {
+	body, err := json.Marshal(newL)
+	if err != nil {
+		t.Fatalf("Test createLink201: failed to parse request body: %v", err)
+	}
+	r := httptest.NewRequest(http.MethodPost, "/v1/notes/1/links", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	nt.app.ServeHTTP(w, r)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Test createLink201: Should receive a status code of 201 for the response : %v", w.Code)
+	}
+
+	var resp link.Link
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Test createLink201: Should be able to unmarshal the response : %v", err)
+	}
+	if resp.Token == "" {
+		t.Fatalf("Test createLink201: Should have received a token in the response: %v", resp)
+	}
+	if resp.Protected != (newL.Password != "") {
+		t.Fatalf("Test createLink201: Should have received protected as %t in the response: %v", newL.Password != "", resp)
+	}
+	return resp
+}
+
+func (nt *NoteTests) getShared(t *testing.T, token, password string, status int) {
+	r := httptest.NewRequest(http.MethodGet, "/v1/shared/"+token, nil)
+	if password != "" {
+		r.Header.Set("X-Link-Password", password)
+	}
+	w := httptest.NewRecorder()
+
+	nt.app.ServeHTTP(w, r)
+
+	if w.Code != status {
+		t.Fatalf("Test getShared: Should receive a status code of %d for the response : %v", status, w.Code)
+	}
+	if status != http.StatusOK {
+		return
+	}
+
+	var resp link.Shared
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Test getShared: Should be able to unmarshal the response : %v", err)
+	}
+	if resp.Title != "my notes" {
+		t.Fatalf("Test getShared: Should have received \"my notes\" as title in the response: %v", resp)
+	}
+}
+
+func (nt *NoteTests) revokeLink(t *testing.T, id string, status int) {
+	r := httptest.NewRequest(http.MethodDelete, "/v1/notes/1/links/"+id, nil)
+	w := httptest.NewRecorder()
+
+	nt.app.ServeHTTP(w, r)
+
+	if w.Code != status {
+		t.Fatalf("Test revokeLink: Should receive a status code of %d for the response : %v", status, w.Code)
+	}
+}
@@ -50,6 +50,14 @@ func TestNote(t *testing.T) {
 	sys.Configs.Cache.PingTimeout = env.DurationDefault(log, "CACHE_PING_TIMEOUT", "2s")
 	sys.Configs.Cache.OperationTimeout = env.DurationDefault(log, "CACHE_PING_TIMEOUT", "10s")
 	sys.Configs.Cache.CacheTTL = env.DurationDefault(log, "CACHE_CACHE_TTL", "24h")
+	sys.Configs.Links.Secret = env.OrDefault(log, "LINKS_SECRET", "tests")
+	sys.Configs.Links.DefaultTTL = env.DurationDefault(log, "LINKS_DEFAULT_TTL", "24h")
+	sys.Configs.Links.MaxTTL = env.DurationDefault(log, "LINKS_MAX_TTL", "720h")
+	sys.Configs.Batch.MaxOperations = env.IntDefault(log, "BATCH_MAX_OPERATIONS", "10")
+	sys.Configs.Batch.MaxBytes = env.IntDefault(log, "BATCH_MAX_BYTES", "65536")
+	sys.Configs.Batch.MaxStreamOperations = env.IntDefault(log, "BATCH_MAX_STREAM_OPERATIONS", "50")
+	sys.Configs.Import.MaxBytes = env.IntDefault(log, "IMPORT_MAX_BYTES", "65536")
+	sys.Configs.Import.QueueThreshold = env.IntDefault(log, "IMPORT_QUEUE_THRESHOLD", "3")
 
 	// =======================================================================================================
 	// Setup resources
@@ -111,10 +119,50 @@ func TestNote(t *testing.T) {
 			id INTEGER PRIMARY KEY,
 			title VARCHAR(100),
 			notes TEXT,
+			notebookId BIGINT NULL,
+			version BIGINT NOT NULL DEFAULT 1,
 			updatedAt DATETIME,
+			createdAt DATETIME,
+			deletedAt DATETIME NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS revisions(
+			id INTEGER PRIMARY KEY,
+			noteId BIGINT NOT NULL,
+			revision BIGINT NOT NULL,
+			title VARCHAR(100),
+			notes TEXT,
+			author VARCHAR(100),
+			createdAt DATETIME,
+			CONSTRAINT revisions_note_revision UNIQUE (noteId, revision)
+		)`,
+		`CREATE TABLE IF NOT EXISTS tags(
+			id INTEGER PRIMARY KEY,
+			name VARCHAR(50) NOT NULL,
+			CONSTRAINT tags_name UNIQUE (name)
+		)`,
+		`CREATE TABLE IF NOT EXISTS note_tags(
+			noteId BIGINT NOT NULL,
+			tagId BIGINT NOT NULL,
+			PRIMARY KEY (noteId, tagId)
+		)`,
+		`CREATE TABLE IF NOT EXISTS notebooks(
+			id INTEGER PRIMARY KEY,
+			parentId BIGINT NULL,
+			name VARCHAR(100) NOT NULL,
+			path VARCHAR(1000) NOT NULL,
+			updatedAt DATETIME,
+			createdAt DATETIME
+		)`,
+		`CREATE TABLE IF NOT EXISTS note_imports(
+			hash CHAR(64) PRIMARY KEY,
+			noteId BIGINT NOT NULL,
 			createdAt DATETIME
 		)`,
+		// the notes table above is created at its latest version, so its column migrations are already applied
+		`CREATE TABLE IF NOT EXISTS schema_migrations(version VARCHAR(100) PRIMARY KEY, appliedAt DATETIME)`,
+		`INSERT INTO schema_migrations (version, appliedAt) VALUES ('0002_notes_version', CURRENT_TIMESTAMP), ('0003_notes_deleted_at', CURRENT_TIMESTAMP), ('0004_notes_notebook', CURRENT_TIMESTAMP), ('0005_revisions_backfill', CURRENT_TIMESTAMP)`,
 		`INSERT INTO notes (title, notes, updatedAt, createdAt) VALUES ('my notes', 'my notes text', ?, ?)`,
+		`INSERT INTO notes (title, notes, updatedAt, createdAt) VALUES ('to delete', 'to delete text', ?, ?)`,
 	}
 
 	for _, b := range batch {
@@ -143,6 +191,30 @@ func TestNote(t *testing.T) {
 		t.Fatalf("notes 1 not in cache")
 	}
 	tests.getNote200(t)
+
+	tests.sharedLink(t)
+
+	tests.revisions(t)
+
+	tests.concurrency(t)
+
+	tests.trash(t)
+
+	tests.tags(t)
+
+	tests.notebooks(t)
+
+	tests.search(t)
+
+	tests.events(t)
+
+	tests.batch(t)
+
+	tests.export(t)
+
+	tests.imports(t)
+
+	tests.diffTooLarge(t)
 }
 
 func (nt *NoteTests) getNote200(t *testing.T) {
@@ -0,0 +1,99 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/ribgsilva/note-api/business/v1/notebook"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func (nt *NoteTests) notebooks(t *testing.T) {
+	nt.createNotebook(t, `{"name":"work"}`, http.StatusCreated, "/1/")
+	nt.createNotebook(t, `{"name":"projects","parentId":1}`, http.StatusCreated, "/1/2/")
+	nt.createNotebook(t, `{"name":"api","parentId":2}`, http.StatusCreated, "/1/2/3/")
+	nt.createNotebook(t, `{"name":"orphan","parentId":99}`, http.StatusBadRequest, "")
+	nt.createNotebook(t, `{"parentId":1}`, http.StatusBadRequest, "")
+
+	nt.changeNote(t, http.MethodPost, "/v1/notes/1/move", `{"notebookId":1}`, "", http.StatusOK)
+	nt.changeNote(t, http.MethodPost, "/v1/notes/3/move", `{"notebookId":3}`, "", http.StatusOK)
+	nt.changeNote(t, http.MethodPost, "/v1/notes/3/move", `{"notebookId":99}`, "", http.StatusBadRequest)
+
+	if ids := nt.listNotes(t, "/v1/notebooks/1/notes"); len(ids) != 1 || ids[0] != 1 {
+		t.Fatalf("Test notebooks: Should list only note 1 in notebook 1: %v", ids)
+	}
+	if ids := nt.listNotes(t, "/v1/notebooks/1/notes?recursive=true"); len(ids) != 2 {
+		t.Fatalf("Test notebooks: Should list notes 1 and 3 under notebook 1: %v", ids)
+	}
+
+	nt.moveNotebook(t, 1, `{"parentId":3}`, http.StatusConflict, "")
+	nt.moveNotebook(t, 1, `{"parentId":1}`, http.StatusConflict, "")
+	nt.moveNotebook(t, 3, `{"parentId":0}`, http.StatusOK, "/3/")
+	nt.moveNotebook(t, 2, `{"parentId":3}`, http.StatusOK, "/3/2/")
+	if ids := nt.listNotes(t, "/v1/notebooks/1/notes?recursive=true"); len(ids) != 1 || ids[0] != 1 {
+		t.Fatalf("Test notebooks: Should list only note 1 under notebook 1 after the move: %v", ids)
+	}
+
+	nt.deleteNotebook(t, "/v1/notebooks/3", http.StatusConflict)
+	nt.deleteNotebook(t, "/v1/notebooks/2", http.StatusNoContent)
+	nt.deleteNotebook(t, "/v1/notebooks/1?cascade=trash", http.StatusNoContent)
+	nt.deleteNotebook(t, "/v1/notebooks/1", http.StatusNotFound)
+	nt.getETag(t, "/v1/notes/1", "", http.StatusNotFound)
+}
+
+func (nt *NoteTests) createNotebook(t *testing.T, body string, status int, path string) {
+	r := httptest.NewRequest(http.MethodPost, "/v1/notebooks", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	nt.app.ServeHTTP(w, r)
+
+	if w.Code != status {
+		t.Fatalf("Test createNotebook: Should receive a status code of %d for the response : %v", status, w.Code)
+	}
+	if status != http.StatusCreated {
+		return
+	}
+
+	var resp notebook.Notebook
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Test createNotebook: Should be able to unmarshal the response : %v", err)
+	}
+	if resp.Path != path {
+		t.Fatalf("Test createNotebook: Should have received %q as path in the response: %v", path, resp)
+	}
+}
+
+func (nt *NoteTests) moveNotebook(t *testing.T, id uint64, body string, status int, path string) {
+	r := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/v1/notebooks/%d/move", id), bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	nt.app.ServeHTTP(w, r)
+
+	if w.Code != status {
+		t.Fatalf("Test moveNotebook: Should receive a status code of %d for the response : %v", status, w.Code)
+	}
+	if status != http.StatusOK {
+		return
+	}
+
+	var resp notebook.Notebook
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Test moveNotebook: Should be able to unmarshal the response : %v", err)
+	}
+	if resp.Path != path {
+		t.Fatalf("Test moveNotebook: Should have received %q as path in the response: %v", path, resp)
+	}
+}
+
+func (nt *NoteTests) deleteNotebook(t *testing.T, path string, status int) {
+	r := httptest.NewRequest(http.MethodDelete, path, nil)
+	w := httptest.NewRecorder()
+
+	nt.app.ServeHTTP(w, r)
+
+	if w.Code != status {
+		t.Fatalf("Test deleteNotebook: Should receive a status code of %d for the response : %v", status, w.Code)
+	}
+}
@@ -0,0 +1,91 @@
+package tests
+
+import (
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"github.com/ribgsilva/note-api/platform/web/identity"
+	"github.com/ribgsilva/note-api/platform/web/ratelimit"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	s := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	defer func() {
+		_ = rdb.Close()
+	}()
+
+	now := time.Now()
+	engine := gin.New()
+	limiter, err := ratelimit.New(ratelimit.Config{
+		Log:     zap.NewNop().Sugar(),
+		Cache:   rdb,
+		Timeout: time.Second,
+		Limit:   2,
+		Period:  time.Minute,
+		Key:     ratelimit.First(ratelimit.ByAPIKey, ratelimit.ByIP),
+		Now:     func() time.Time { return now },
+	})
+	if err != nil {
+		t.Fatalf("Test rateLimit: Should have created the limiter: %v", err)
+	}
+	engine.Use(limiter)
+	for _, invalid := range []ratelimit.Config{{Limit: 0, Period: time.Minute}, {Limit: 2}} {
+		if _, err := ratelimit.New(invalid); err == nil {
+			t.Fatalf("Test rateLimit: Should have rejected the limit %d every %s", invalid.Limit, invalid.Period)
+		}
+	}
+	engine.GET("/limited", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	call := func(apiKey string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/limited", nil)
+		if apiKey != "" {
+			r.Header.Set(identity.APIKeyHeader, apiKey)
+		}
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, r)
+		return w
+	}
+
+	for i := 0; i < 2; i++ {
+		if w := call("key"); w.Code != http.StatusOK {
+			t.Fatalf("Test rateLimit: Should receive a status code of 200 for request %d : %v", i, w.Code)
+		}
+	}
+	w := call("key")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("Test rateLimit: Should receive a status code of 429 after the limit : %v", w.Code)
+	}
+	if w.Header().Get("Retry-After") != "30" {
+		t.Fatalf("Test rateLimit: Should have received \"30\" as Retry-After: %v", w.Header())
+	}
+	if w.Header().Get("RateLimit-Remaining") != "0" {
+		t.Fatalf("Test rateLimit: Should have received \"0\" as RateLimit-Remaining: %v", w.Header())
+	}
+	if w := call("other"); w.Code != http.StatusOK {
+		t.Fatalf("Test rateLimit: Should not limit other api keys : %v", w.Code)
+	}
+
+	now = now.Add(30 * time.Second)
+	if w := call("key"); w.Code != http.StatusOK {
+		t.Fatalf("Test rateLimit: Should receive a status code of 200 after the refill : %v", w.Code)
+	}
+
+	// redis down, falls back to memory
+	s.Close()
+	for i := 0; i < 2; i++ {
+		if w := call(""); w.Code != http.StatusOK {
+			t.Fatalf("Test rateLimit: Should receive a status code of 200 in memory for request %d : %v", i, w.Code)
+		}
+	}
+	if w := call(""); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("Test rateLimit: Should receive a status code of 429 in memory after the limit : %v", w.Code)
+	}
+}
@@ -0,0 +1,153 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/business/v1/revision"
+	"github.com/ribgsilva/note-api/platform/diff"
+	"github.com/ribgsilva/note-api/platform/web/identity"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func (nt *NoteTests) revisions(t *testing.T) {
+	nt.updateNote(t, note.NewNote{Title: "my notes", Text: "my notes text\nsecond line"}, http.StatusOK)
+	nt.updateNote(t, note.NewNote{Title: "my notes", Text: "my notes text\nother line"}, http.StatusOK)
+	nt.updateNote(t, note.NewNote{Text: "no title"}, http.StatusBadRequest)
+
+	nt.listRevisions(t, 2)
+	nt.getRevision404(t)
+	nt.diffRevisions(t)
+	nt.restoreRevision(t)
+	nt.listRevisions(t, 3)
+}
+
+func (nt *NoteTests) updateNote(t *testing.T, newN note.NewNote, status int) {
+	body, err := json.Marshal(newN)
+	if err != nil {
+		t.Fatalf("Test updateNote: failed to parse request body: %v", err)
+	}
+	r := httptest.NewRequest(http.MethodPut, "/v1/notes/1", bytes.NewReader(body))
+	r.Header.Set(identity.UserHeader, "john")
+	w := httptest.NewRecorder()
+
+	nt.app.ServeHTTP(w, r)
+
+	if w.Code != status {
+		t.Fatalf("Test updateNote: Should receive a status code of %d for the response : %v", status, w.Code)
+	}
+	if status != http.StatusOK {
+		return
+	}
+
+	var resp note.Note
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Test updateNote: Should be able to unmarshal the response : %v", err)
+	}
+	if resp.Text != newN.Text {
+		t.Fatalf("Test updateNote: Should have received %q as text in the response: %v", newN.Text, resp)
+	}
+}
+
+func (nt *NoteTests) listRevisions(t *testing.T, size int) {
+	r := httptest.NewRequest(http.MethodGet, "/v1/notes/1/revisions", nil)
+	w := httptest.NewRecorder()
+
+	nt.app.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Test listRevisions: Should receive a status code of 200 for the response : %v", w.Code)
+	}
+
+	var resp []revision.Revision
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Test listRevisions: Should be able to unmarshal the response : %v", err)
+	}
+	if len(resp) != size {
+		t.Fatalf("Test listRevisions: Should have received %d revisions in the response: %v", size, resp)
+	}
+	for i, rev := range resp {
+		if rev.Revision != uint64(i+1) || rev.Author != "john" {
+			t.Fatalf("Test listRevisions: Should have received revision %d by \"john\" in the response: %v", i+1, rev)
+		}
+	}
+}
+
+func (nt *NoteTests) getRevision404(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/v1/notes/1/revisions/99", nil)
+	w := httptest.NewRecorder()
+
+	nt.app.ServeHTTP(w, r)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("Test getRevision404: Should receive a status code of 404 for the response : %v", w.Code)
+	}
+}
+
+func (nt *NoteTests) diffRevisions(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/v1/notes/1/revisions/2/diff", nil)
+	w := httptest.NewRecorder()
+
+	nt.app.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Test diffRevisions: Should receive a status code of 200 for the response : %v", w.Code)
+	}
+
+	var resp revision.Diff
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Test diffRevisions: Should be able to unmarshal the response : %v", err)
+	}
+	expected := []diff.Line{
+		{Op: diff.Equal, Text: "my notes text"},
+		{Op: diff.Delete, Text: "second line"},
+		{Op: diff.Insert, Text: "other line"},
+	}
+	if fmt.Sprint(resp.Text) != fmt.Sprint(expected) {
+		t.Fatalf("Test diffRevisions: Should have received %v as text diff in the response: %v", expected, resp.Text)
+	}
+}
+
+func (nt *NoteTests) restoreRevision(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/v1/notes/1/revisions/1/restore", nil)
+	r.Header.Set(identity.UserHeader, "john")
+	w := httptest.NewRecorder()
+
+	nt.app.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Test restoreRevision: Should receive a status code of 200 for the response : %v", w.Code)
+	}
+
+	var resp note.Note
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Test restoreRevision: Should be able to unmarshal the response : %v", err)
+	}
+	if resp.Text != "my notes text\nsecond line" {
+		t.Fatalf("Test restoreRevision: Should have received the first revision text in the response: %v", resp)
+	}
+}
+
+// diffTooLarge compares revisions with more changed lines than the diff compares
+func (nt *NoteTests) diffTooLarge(t *testing.T) {
+	created := nt.createNoteAs(t, "john", `{"title":"large","text":"large"}`)
+	var lines []string
+	for i := 0; i <= diff.MaxLines; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	body, _ := json.Marshal(note.NewNote{Title: "large", Text: strings.Join(lines, "\n")})
+	nt.changeNote(t, http.MethodPut, fmt.Sprintf("/v1/notes/%d", created.Id), string(body), "", http.StatusOK)
+
+	r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/v1/notes/%d/revisions/2/diff", created.Id), nil)
+	w := httptest.NewRecorder()
+
+	nt.app.ServeHTTP(w, r)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("Test diffTooLarge: Should receive a status code of 422 for the response : %v", w.Code)
+	}
+}
@@ -0,0 +1,62 @@
+//go:build sqlite_fts5
+
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"github.com/ribgsilva/note-api/persistence/v1/schema"
+	"github.com/ribgsilva/note-api/sys"
+	"testing"
+	"time"
+)
+
+// TestSchema creates the SQLite schema and migrates it to the version the application runs on
+func TestSchema(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Test schema: failed to open the database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	database, dialect := sys.R.Database, sys.Configs.Database.Dialect
+	sys.R.Database, sys.Configs.Database.Dialect = db, "sqlite3"
+	defer func() {
+		sys.R.Database, sys.Configs.Database.Dialect = database, dialect
+		_ = db.Close()
+	}()
+
+	if err := schema.Create(context.Background()); err != nil {
+		t.Fatalf("Test schema: failed to create the schema: %v", err)
+	}
+	// a note from before the migrations, without revisions
+	now := time.Now().UTC()
+	if _, err := db.Exec("INSERT INTO notes (title, notes, updatedAt, createdAt) VALUES ('schema', 'schema text', ?, ?)", now, now); err != nil {
+		t.Fatalf("Test schema: failed to insert a note: %v", err)
+	}
+	applied, err := schema.Migrate(context.Background())
+	if err != nil || applied != 5 {
+		t.Fatalf("Test schema: Should have applied every migration: %d %v", applied, err)
+	}
+
+	var id, version uint64
+	var deletedAt, notebookId sql.NullString
+	if err := db.QueryRow("SELECT id, version, deletedAt, notebookId FROM notes").Scan(&id, &version, &deletedAt, &notebookId); err != nil {
+		t.Fatalf("Test schema: failed to read the migrated columns: %v", err)
+	}
+	if id != 1 || version != 1 || deletedAt.Valid || notebookId.Valid {
+		t.Fatalf("Test schema: Should have numbered the note and defaulted its columns: %v %v %v %v", id, version, deletedAt, notebookId)
+	}
+	var revision uint64
+	var author, title string
+	if err := db.QueryRow("SELECT revision, author, title FROM revisions WHERE noteId = 1").Scan(&revision, &author, &title); err != nil || revision != 1 || author != "" || title != "schema" {
+		t.Fatalf("Test schema: Should have backfilled the first revision without an author: %v %q %v %v", revision, author, title, err)
+	}
+	var matched int
+	if err := db.QueryRow("SELECT COUNT(*) FROM notes_fts WHERE notes_fts MATCH 'schema'").Scan(&matched); err != nil || matched != 1 {
+		t.Fatalf("Test schema: Should have indexed the note: %v %v", matched, err)
+	}
+
+	if err := schema.Drop(context.Background()); err != nil {
+		t.Fatalf("Test schema: failed to drop the schema: %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+//go:build sqlite_fts5
+
+package tests
+
+import (
+	"context"
+	"github.com/ribgsilva/note-api/persistence/v1/schema"
+	"github.com/ribgsilva/note-api/sys"
+	"net/http"
+	"testing"
+)
+
+// search needs SQLite built with FTS5, run with go test -tags sqlite_fts5
+func (nt *NoteTests) search(t *testing.T) {
+	sys.Configs.Database.Dialect = "sqlite3"
+	defer func() {
+		sys.Configs.Database.Dialect = ""
+	}()
+
+	for _, expected := range []int{1, 0} {
+		applied, err := schema.Migrate(context.Background())
+		if err != nil {
+			t.Fatalf("Test search: failed to migrate: %v", err)
+		}
+		if applied != expected {
+			t.Fatalf("Test search: Should have applied %d migrations: %d", expected, applied)
+		}
+	}
+
+	nt.createNote(t, `{"title":"meeting notes","text":"we discussed the project roadmap and the next meeting"}`, http.StatusCreated)
+
+	results := nt.searchNotes(t, "meeting", http.StatusOK)
+	if len(results) != 1 || results[0].Note.Title != "meeting notes" {
+		t.Fatalf("Test search: Should have found the meeting notes: %v", results)
+	}
+	if results[0].Highlight.Title != "<mark>meeting</mark> notes" {
+		t.Fatalf("Test search: Should have highlighted the title: %v", results[0].Highlight)
+	}
+	if results[0].Highlight.Text != "we discussed the project roadmap and the next <mark>meeting</mark>" {
+		t.Fatalf("Test search: Should have highlighted the text: %v", results[0].Highlight)
+	}
+
+	if results := nt.searchNotes(t, `"project roadmap"`, http.StatusOK); len(results) != 1 {
+		t.Fatalf("Test search: Should have found the phrase: %v", results)
+	}
+	if results := nt.searchNotes(t, `"roadmap project"`, http.StatusOK); len(results) != 0 {
+		t.Fatalf("Test search: Should not have found the phrase out of order: %v", results)
+	}
+	if results := nt.searchNotes(t, "proj*", http.StatusOK); len(results) != 1 || results[0].Highlight.Text == "" {
+		t.Fatalf("Test search: Should have found the prefix: %v", results)
+	}
+
+	if results := nt.searchNotes(t, "tagged", http.StatusOK); len(results) != 1 {
+		t.Fatalf("Test search: Should have found the tagged note: %v", results)
+	}
+	nt.changeNote(t, http.MethodPut, "/v1/notes/3", `{"title":"renamed","text":"renamed text"}`, "", http.StatusOK)
+	if results := nt.searchNotes(t, "tagged", http.StatusOK); len(results) != 0 {
+		t.Fatalf("Test search: Should not have found the renamed note: %v", results)
+	}
+
+	nt.searchNotes(t, `"" *`, http.StatusBadRequest)
+}
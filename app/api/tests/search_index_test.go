@@ -0,0 +1,92 @@
+//go:build !sqlite_fts5
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/platform/search"
+	"github.com/ribgsilva/note-api/sys"
+	"net/http"
+	"path/filepath"
+	"testing"
+)
+
+// search without FTS5 uses the embedded search index, run with go test -tags sqlite_fts5 to search with SQLite
+func (nt *NoteTests) search(t *testing.T) {
+	nt.searchNotes(t, "meeting", http.StatusNotImplemented)
+
+	sys.R.Search = search.New(search.English)
+	defer func() {
+		sys.R.Search = nil
+	}()
+
+	indexed, err := note.Reindex(context.Background())
+	if err != nil {
+		t.Fatalf("Test search: failed to reindex: %v", err)
+	}
+	if indexed != 2 {
+		t.Fatalf("Test search: Should have indexed the 2 notes out of the trash: %d", indexed)
+	}
+
+	nt.createNote(t, `{"title":"meeting notes","text":"we discussed the project roadmap and the next meeting"}`, http.StatusCreated)
+
+	results := nt.searchNotes(t, "meeting", http.StatusOK)
+	if len(results) != 1 || results[0].Note.Title != "meeting notes" {
+		t.Fatalf("Test search: Should have found the meeting notes: %v", results)
+	}
+	if results[0].Highlight.Title != "<mark>meeting</mark> notes" {
+		t.Fatalf("Test search: Should have highlighted the title: %v", results[0].Highlight)
+	}
+	id := results[0].Note.Id
+
+	if results := nt.searchNotes(t, "meetings discussing", http.StatusOK); len(results) != 1 {
+		t.Fatalf("Test search: Should have found the stemmed words: %v", results)
+	}
+	if results := nt.searchNotes(t, "proj*", http.StatusOK); len(results) != 1 {
+		t.Fatalf("Test search: Should have found the prefix: %v", results)
+	}
+	if results := nt.searchNotes(t, "meeting unknown", http.StatusOK); len(results) != 0 {
+		t.Fatalf("Test search: Should require all the words: %v", results)
+	}
+
+	if results := nt.searchNotes(t, "tagged", http.StatusOK); len(results) != 1 {
+		t.Fatalf("Test search: Should have found the tagged note: %v", results)
+	}
+	nt.changeNote(t, http.MethodPut, "/v1/notes/3", `{"title":"renamed","text":"renamed text"}`, "", http.StatusOK)
+	if results := nt.searchNotes(t, "tagged", http.StatusOK); len(results) != 0 {
+		t.Fatalf("Test search: Should not have found the renamed note: %v", results)
+	}
+
+	nt.changeNote(t, http.MethodDelete, fmt.Sprintf("/v1/notes/%d", id), "", "", http.StatusNoContent)
+	if results := nt.searchNotes(t, "meeting", http.StatusOK); len(results) != 0 {
+		t.Fatalf("Test search: Should not have found the deleted note: %v", results)
+	}
+	nt.restoreNote(t, id, http.StatusOK)
+	if results := nt.searchNotes(t, "meeting", http.StatusOK); len(results) != 1 {
+		t.Fatalf("Test search: Should have found the restored note: %v", results)
+	}
+
+	path := filepath.Join(t.TempDir(), "notes.index")
+	if err := sys.R.Search.Save(path); err != nil {
+		t.Fatalf("Test search: failed to save the index: %v", err)
+	}
+	loaded, err := search.Load(path, search.English)
+	if err != nil {
+		t.Fatalf("Test search: failed to load the index: %v", err)
+	}
+	if loaded.Len() != 3 || len(loaded.Search([]string{"roadmap"}, nil)) != 1 {
+		t.Fatalf("Test search: Should have loaded the 3 notes indexed: %d", loaded.Len())
+	}
+	if _, err := search.Load(path, search.Portuguese); !errors.Is(err, search.ErrIncompatible) {
+		t.Fatalf("Test search: Should not load an index of another language: %v", err)
+	}
+
+	pt := search.New(search.Portuguese)
+	pt.Add(1, search.Field{Text: "As reuniões do projeto foram canceladas", Weight: 1})
+	if hits := pt.Search([]string{"reunião", "projetos", "cancelada"}, nil); len(hits) != 1 {
+		t.Fatalf("Test search: Should have found the portuguese stemmed words: %v", hits)
+	}
+}
@@ -0,0 +1,30 @@
+package tests
+
+import (
+	"encoding/json"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func (nt *NoteTests) searchNotes(t *testing.T, q string, status int) []note.SearchResult {
+	r := httptest.NewRequest(http.MethodGet, "/v1/notes/search?q="+url.QueryEscape(q), nil)
+	w := httptest.NewRecorder()
+
+	nt.app.ServeHTTP(w, r)
+
+	if w.Code != status {
+		t.Fatalf("Test searchNotes: Should receive a status code of %d for the response : %v", status, w.Code)
+	}
+	if status != http.StatusOK {
+		return nil
+	}
+
+	var resp []note.SearchResult
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Test searchNotes: Should be able to unmarshal the response : %v", err)
+	}
+	return resp
+}
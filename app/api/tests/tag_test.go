@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/business/v1/tag"
+	"github.com/ribgsilva/note-api/platform/web/etag"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func (nt *NoteTests) tags(t *testing.T) {
+	if created := nt.createNote(t, `{"title":"tagged","text":"tagged text","tags":[" Work ","ideas","work"]}`, http.StatusCreated); !reflect.DeepEqual(created.Tags, []string{"ideas", "work"}) {
+		t.Fatalf("Test tags: Should have received the normalized tags of the created note: %v", created.Tags)
+	}
+	nt.createNote(t, `{"text":"no title","tags":["work"]}`, http.StatusBadRequest)
+	nt.changeNote(t, http.MethodPatch, "/v1/notes/1", `{"tags":["work"]}`, "", http.StatusOK)
+
+	if ids := nt.listNotes(t, "/v1/notes?tag=work&tag=ideas"); len(ids) != 2 {
+		t.Fatalf("Test tags: Should list the 2 notes with any of the tags: %v", ids)
+	}
+	if ids := nt.listNotes(t, "/v1/notes?tag=work&tag=ideas&match=all"); len(ids) != 1 || ids[0] != 3 {
+		t.Fatalf("Test tags: Should list only note 3 with all the tags: %v", ids)
+	}
+	if ids := nt.listNotes(t, "/v1/notes?tag=unknown"); len(ids) != 0 {
+		t.Fatalf("Test tags: Should list no notes with an unknown tag: %v", ids)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/v1/notes?tag=work&match=some", nil)
+	w := httptest.NewRecorder()
+	nt.app.ServeHTTP(w, r)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Test tags: Should receive a status code of 400 for an invalid match : %v", w.Code)
+	}
+
+	nt.listTags(t, []tag.Tag{{Name: "work", Count: 2}, {Name: "ideas", Count: 1}})
+}
+
+// createNote posts a note, checking the created one is returned as persisted
+func (nt *NoteTests) createNote(t *testing.T, body string, status int) note.Note {
+	r := httptest.NewRequest(http.MethodPost, "/v1/notes", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	nt.app.ServeHTTP(w, r)
+
+	if w.Code != status {
+		t.Fatalf("Test createNote: Should receive a status code of %d for the response : %v", status, w.Code)
+	}
+	if status != http.StatusCreated {
+		return note.Note{}
+	}
+
+	var created note.Note
+	if err := json.NewDecoder(w.Body).Decode(&created); err != nil {
+		t.Fatalf("Test createNote: Should be able to unmarshal the response : %v", err)
+	}
+	if created.Id == 0 || created.Version != 1 || created.CreatedAt.IsZero() {
+		t.Fatalf("Test createNote: Should have received the created note: %v", created)
+	}
+	location := fmt.Sprintf("/v1/notes/%d", created.Id)
+	if w.Header().Get("Location") != location || w.Header().Get("ETag") != etag.Format(created.Version) {
+		t.Fatalf("Test createNote: Should have received the location and version of the note: %v", w.Header())
+	}
+
+	r = httptest.NewRequest(http.MethodGet, location, nil)
+	w = httptest.NewRecorder()
+	nt.app.ServeHTTP(w, r)
+	var found note.Note
+	if err := json.NewDecoder(w.Body).Decode(&found); err != nil {
+		t.Fatalf("Test createNote: Should be able to unmarshal the found note : %v", err)
+	}
+	if !reflect.DeepEqual(created, found) {
+		t.Fatalf("Test createNote: Should have received the note as persisted: %v %v", created, found)
+	}
+	return created
+}
+
+func (nt *NoteTests) listTags(t *testing.T, expected []tag.Tag) {
+	r := httptest.NewRequest(http.MethodGet, "/v1/tags", nil)
+	w := httptest.NewRecorder()
+
+	nt.app.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Test listTags: Should receive a status code of 200 for the response : %v", w.Code)
+	}
+
+	var resp []tag.Tag
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Test listTags: Should be able to unmarshal the response : %v", err)
+	}
+	if len(resp) != len(expected) {
+		t.Fatalf("Test listTags: Should have received %v in the response: %v", expected, resp)
+	}
+	for i := range expected {
+		if resp[i] != expected[i] {
+			t.Fatalf("Test listTags: Should have received %v in the response: %v", expected, resp)
+		}
+	}
+}
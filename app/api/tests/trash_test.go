@@ -0,0 +1,61 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func (nt *NoteTests) trash(t *testing.T) {
+	if ids := nt.listNotes(t, "/v1/trash"); len(ids) != 1 || ids[0] != 2 {
+		t.Fatalf("Test trash: Should have only note 2 in the trash: %v", ids)
+	}
+	if ids := nt.listNotes(t, "/v1/notes"); len(ids) != 1 || ids[0] != 1 {
+		t.Fatalf("Test trash: Should list only note 1: %v", ids)
+	}
+
+	nt.restoreNote(t, 2, http.StatusOK)
+	nt.restoreNote(t, 2, http.StatusNotFound)
+
+	if ids := nt.listNotes(t, "/v1/trash"); len(ids) != 0 {
+		t.Fatalf("Test trash: Should have an empty trash: %v", ids)
+	}
+	if ids := nt.listNotes(t, "/v1/notes?limit=1&offset=1"); len(ids) != 1 {
+		t.Fatalf("Test trash: Should list one note in the second page: %v", ids)
+	}
+}
+
+func (nt *NoteTests) listNotes(t *testing.T, path string) []uint64 {
+	r := httptest.NewRequest(http.MethodGet, path, nil)
+	w := httptest.NewRecorder()
+
+	nt.app.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Test listNotes: Should receive a status code of 200 for the response : %v", w.Code)
+	}
+
+	var resp []note.Note
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Test listNotes: Should be able to unmarshal the response : %v", err)
+	}
+	ids := make([]uint64, len(resp))
+	for i, n := range resp {
+		ids[i] = n.Id
+	}
+	return ids
+}
+
+func (nt *NoteTests) restoreNote(t *testing.T, id uint64, status int) {
+	r := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/v1/notes/%d/restore", id), nil)
+	w := httptest.NewRecorder()
+
+	nt.app.ServeHTTP(w, r)
+
+	if w.Code != status {
+		t.Fatalf("Test restoreNote: Should receive a status code of %d for the response : %v", status, w.Code)
+	}
+}
@@ -0,0 +1,35 @@
+package search
+
+import (
+	"context"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/sys"
+	"time"
+)
+
+// Reindex rebuilds the embedded search index from the database every interval and saves it to disk, until the context
+// is done. It picks up the notes written by other apps, as the messaging consumer
+func Reindex(ctx context.Context, interval time.Duration, path string) {
+	logger := sys.R.Log
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		indexed, err := note.Reindex(ctx)
+		if err != nil {
+			logger.Error("failed to reindex notes: ", err)
+			continue
+		}
+		logger.Infof("reindexed %d notes", indexed)
+		if err := sys.R.Search.Save(path); err != nil {
+			logger.Error("failed to save search index: ", err)
+		}
+	}
+}
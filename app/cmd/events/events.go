@@ -0,0 +1,104 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/platform/dispatch"
+	"github.com/ribgsilva/note-api/platform/filepubsub"
+	"os"
+	"strings"
+)
+
+func ListCommands() {
+	println("Events Commands")
+	println("\tvalidate <file>\t\t- Validates the messages in a file against the schemas of their type and version. The file has one JSON message, or one per line")
+	println("\thelp\t\t\t- Print the commands available")
+}
+
+func Run(options []string) {
+	if len(options) == 0 {
+		ListCommands()
+		return
+	}
+	switch options[0] {
+	case "validate":
+		if len(options) < 2 {
+			println("missing the file to validate")
+			return
+		}
+		if !validate(options[1]) {
+			os.Exit(1)
+		}
+	case "help":
+		fallthrough
+	default:
+		ListCommands()
+	}
+}
+
+// validate prints the report of each message in the file, returning false if any is not valid
+func validate(path string) bool {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		println("failed to read file:", err.Error())
+		return false
+	}
+
+	messages, err := messages(content)
+	if err != nil {
+		println("failed to read messages:", err.Error())
+		return false
+	}
+
+	valid := 0
+	for i, m := range messages {
+		report, err := check(m)
+		if err != nil {
+			println("failed to validate:", err.Error())
+			return false
+		}
+		if len(report) == 0 {
+			valid++
+			continue
+		}
+		println(fmt.Sprintf("message %d is invalid:", i+1))
+		for _, r := range report {
+			println("\t" + r)
+		}
+	}
+	println(fmt.Sprintf("%d of %d messages are valid", valid, len(messages)))
+	return valid == len(messages)
+}
+
+// messages reads a file with a single JSON message, possibly spanning many lines, or with one message per line
+func messages(content []byte) ([]filepubsub.Message, error) {
+	trimmed := bytes.TrimSpace(content)
+	if len(trimmed) == 0 {
+		return nil, nil
+	}
+	if json.Valid(trimmed) {
+		return []filepubsub.Message{filepubsub.Parse(trimmed)}, nil
+	}
+
+	var found []filepubsub.Message
+	scanner := bufio.NewScanner(bytes.NewReader(trimmed))
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			found = append(found, filepubsub.Parse([]byte(line)))
+		}
+	}
+	return found, scanner.Err()
+}
+
+// check decodes a message as the messaging app does and validates its data, returning the violations found
+func check(m filepubsub.Message) ([]string, error) {
+	e, err := dispatch.Decode(m.Body, m.Metadata)
+	if err != nil {
+		return []string{err.Error()}, nil
+	}
+	return note.ValidateEvent(e.Type, e.Version, e.Data)
+}
@@ -1,7 +1,14 @@
 package main
 
 import (
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/ribgsilva/note-api/app/cmd/events"
+	"github.com/ribgsilva/note-api/app/cmd/messages"
+	"github.com/ribgsilva/note-api/app/cmd/notes"
+	"github.com/ribgsilva/note-api/app/cmd/purge"
 	"github.com/ribgsilva/note-api/app/cmd/schema"
+	"github.com/ribgsilva/note-api/app/cmd/search"
 	"os"
 )
 
@@ -14,6 +21,16 @@ func main() {
 	switch args[1] {
 	case "schema":
 		schema.Run(args[2:])
+	case "purge":
+		purge.Run(args[2:])
+	case "search":
+		search.Run(args[2:])
+	case "events":
+		events.Run(args[2:])
+	case "messages":
+		messages.Run(args[2:])
+	case "notes":
+		notes.Run(args[2:])
 	case "help":
 		fallthrough
 	default:
@@ -24,4 +41,9 @@ func main() {
 func printOpts() {
 	println("Person API Commands")
 	println("\tschema\t\t\t- Schema migrations")
+	println("\tpurge\t\t\t- Trash purge")
+	println("\tsearch\t\t\t- Embedded search index")
+	println("\tevents\t\t\t- Messaging events")
+	println("\tmessages\t\t- Message replay and dead-letter redrive")
+	println("\tnotes\t\t\t- Notes export and import")
 }
@@ -0,0 +1,267 @@
+package messages
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/ribgsilva/note-api/platform/cloudevents"
+	"github.com/ribgsilva/note-api/platform/dispatch"
+	_ "github.com/ribgsilva/note-api/platform/filepubsub"
+	"gocloud.dev/pubsub"
+	_ "gocloud.dev/pubsub/awssnssqs"
+	_ "gocloud.dev/pubsub/kafkapubsub"
+	_ "gocloud.dev/pubsub/natspubsub"
+	_ "gocloud.dev/pubsub/rabbitpubsub"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// errNotDeadLetter is returned when redriving a message that was not sent by the dead-letter of the messaging app
+var errNotDeadLetter = errors.New("not a dead letter")
+
+func ListCommands() {
+	println("Messages Commands")
+	println("\treplay --from <url|file> --to <url>\t- Publishes again the messages of a subscription or file")
+	println("\tredrive --dlq <url|file> --to <url>\t- Moves the messages of a dead-letter subscription or file back to a topic")
+	println("\t\t--type <types>\t\t- Only the messages of these comma separated types")
+	println("\t\t--since, --until <time>\t- Only the messages of this RFC 3339 period: their CloudEvents time on replay, when they failed on redrive")
+	println("\t\t--rate <n>\t\t- Publishes at most n messages per second")
+	println("\t\t--idle <duration>\t- Stops after receiving nothing for this long, 5s by default")
+	println("\t\t--dry-run\t\t- Prints the messages instead of publishing them")
+	println("\thelp\t\t\t- Print the commands available")
+}
+
+func Run(options []string) {
+	if len(options) == 0 {
+		ListCommands()
+		return
+	}
+	var err error
+	switch options[0] {
+	case "replay":
+		err = run(options[0], "from", options[1:], replayed)
+	case "redrive":
+		err = run(options[0], "dlq", options[1:], redriven)
+	case "help":
+		fallthrough
+	default:
+		ListCommands()
+	}
+	if err != nil {
+		println("error:", err.Error())
+		os.Exit(1)
+	}
+}
+
+// options of the replay and redrive commands
+type options struct {
+	from   string
+	to     string
+	types  map[string]bool
+	since  time.Time
+	until  time.Time
+	rate   float64
+	idle   time.Duration
+	dryRun bool
+}
+
+// parse reads the flags of a command, the source being in the flag named source
+func parse(command, source string, args []string) (options, error) {
+	var o options
+	var types, since, until string
+	fs := flag.NewFlagSet(command, flag.ContinueOnError)
+	fs.StringVar(&o.from, source, "", "subscription url or file to read the messages from")
+	fs.StringVar(&o.to, "to", "", "topic url to publish the messages to")
+	fs.StringVar(&types, "type", "", "comma separated types of the messages")
+	fs.StringVar(&since, "since", "", "RFC 3339 time of the oldest message")
+	fs.StringVar(&until, "until", "", "RFC 3339 time of the newest message")
+	fs.Float64Var(&o.rate, "rate", 0, "messages published per second")
+	fs.DurationVar(&o.idle, "idle", 5*time.Second, "time without messages to stop")
+	fs.BoolVar(&o.dryRun, "dry-run", false, "print the messages instead of publishing them")
+	if err := fs.Parse(args); err != nil {
+		return o, err
+	}
+
+	switch {
+	case o.from == "":
+		return o, fmt.Errorf("missing --%s", source)
+	case o.to == "" && !o.dryRun:
+		return o, errors.New("missing --to")
+	}
+	if types != "" {
+		o.types = map[string]bool{}
+		for _, t := range strings.Split(types, ",") {
+			o.types[strings.TrimSpace(t)] = true
+		}
+	}
+	var err error
+	if since != "" {
+		if o.since, err = time.Parse(time.RFC3339, since); err != nil {
+			return o, fmt.Errorf("invalid --since: %w", err)
+		}
+	}
+	if until != "" {
+		if o.until, err = time.Parse(time.RFC3339, until); err != nil {
+			return o, fmt.Errorf("invalid --until: %w", err)
+		}
+	}
+	return o, nil
+}
+
+// candidate is a message as it is published again, with what it is filtered by
+type candidate struct {
+	body      []byte
+	metadata  map[string]string
+	eventType string
+	at        *time.Time
+}
+
+// match tells if a message passes the type and time filters. Messages without a time only pass without time filters
+func (o options) match(c candidate) bool {
+	if o.types != nil && !o.types[c.eventType] {
+		return false
+	}
+	if o.since.IsZero() && o.until.IsZero() {
+		return true
+	}
+	if c.at == nil {
+		return false
+	}
+	return (o.since.IsZero() || !c.at.Before(o.since)) && (o.until.IsZero() || !c.at.After(o.until))
+}
+
+// replayed publishes a message as it was received, filtered by its type and CloudEvents time
+func replayed(m *pubsub.Message) (candidate, error) {
+	c := candidate{body: m.Body, metadata: m.Metadata}
+	if e, err := dispatch.Decode(m.Body, m.Metadata); err == nil {
+		c.eventType = e.Type
+	}
+	if e, err := cloudevents.Decode(m.Body, m.Metadata); err == nil {
+		c.at = e.Time
+	}
+	return c, nil
+}
+
+// redriven publishes the original message of a dead letter, filtered by its type and when it failed
+func redriven(m *pubsub.Message) (candidate, error) {
+	var d dispatch.DeadLetter
+	if err := json.Unmarshal(m.Body, &d); err != nil || d.Body == "" {
+		return candidate{}, errNotDeadLetter
+	}
+
+	c := candidate{body: []byte(d.Body), metadata: d.Metadata, at: &d.FailedAt}
+	if e, err := dispatch.Decode(c.body, c.metadata); err == nil {
+		c.eventType = e.Type
+	}
+	return c, nil
+}
+
+// run publishes the messages of the source that match the filters, acking them once published,
+// until the source ends or nothing is received for the idle time
+func run(command, source string, args []string, read func(m *pubsub.Message) (candidate, error)) error {
+	o, err := parse(command, source, args)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	sub, err := pubsub.OpenSubscription(ctx, sourceURL(o.from))
+	if err != nil {
+		return fmt.Errorf("could not open subscription: %w", err)
+	}
+	defer func() {
+		_ = sub.Shutdown(ctx)
+	}()
+
+	var topic *pubsub.Topic
+	if !o.dryRun {
+		if topic, err = pubsub.OpenTopic(ctx, o.to); err != nil {
+			return fmt.Errorf("could not open topic: %w", err)
+		}
+		defer func() {
+			_ = topic.Shutdown(ctx)
+		}()
+	}
+
+	var tick <-chan time.Time
+	if o.rate > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / o.rate))
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	var published, skipped int
+	for {
+		receiveCtx, receiveCancel := context.WithTimeout(ctx, o.idle)
+		m, err := sub.Receive(receiveCtx)
+		receiveCancel()
+		if errors.Is(err, io.EOF) || errors.Is(err, context.DeadlineExceeded) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to receive: %w", err)
+		}
+
+		c, err := read(m)
+		if err != nil || !o.match(c) {
+			skipped++
+			leave(m)
+			continue
+		}
+		if o.dryRun {
+			published++
+			println(fmt.Sprintf("%s %s %s", c.eventType, metadata(c.metadata), string(c.body)))
+			leave(m)
+			continue
+		}
+
+		if tick != nil {
+			<-tick
+		}
+		if err := topic.Send(ctx, &pubsub.Message{Body: c.body, Metadata: c.metadata}); err != nil {
+			return fmt.Errorf("failed to publish: %w", err)
+		}
+		m.Ack()
+		published++
+	}
+
+	if o.dryRun {
+		println(fmt.Sprintf("%d messages would be published, %d skipped", published, skipped))
+	} else {
+		println(fmt.Sprintf("%d messages published, %d skipped", published, skipped))
+	}
+	return nil
+}
+
+// leave keeps a message in the source, to be received again once its ack deadline expires.
+// Sources that can not nack, as files, are acked, as their messages are not removed anyway
+func leave(m *pubsub.Message) {
+	if !m.Nackable() {
+		m.Ack()
+	}
+}
+
+// sourceURL takes a source without a scheme as a file path
+func sourceURL(source string) string {
+	if strings.Contains(source, "://") {
+		return source
+	}
+	if abs, err := filepath.Abs(source); err == nil {
+		source = abs
+	}
+	return "file://" + source
+}
+
+// metadata prints the metadata of a message as JSON
+func metadata(m map[string]string) string {
+	if len(m) == 0 {
+		return "{}"
+	}
+	encoded, _ := json.Marshal(m)
+	return string(encoded)
+}
@@ -0,0 +1,218 @@
+package notes
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/platform/env"
+	"github.com/ribgsilva/note-api/sys"
+	"go.uber.org/zap"
+	"gocloud.dev/pubsub"
+	"io"
+	"os"
+)
+
+func ListCommands() {
+	println("Notes Commands")
+	println("\texport\t\t\t- Writes the notes out of the trash, in the order they were created")
+	println("\t\t--format <format>\t- json, ndjson, csv or markdown (a zip with a file per note), json by default")
+	println("\t\t--output <file>\t\t- File written, the standard output by default")
+	println("\t\t--author <user>\t\t- User whose notes are exported, required without --anonymous")
+	println("\t\t--anonymous\t\t- Export the notes created without a user instead")
+	println("\timport\t\t\t- Creates the notes of a zip of Markdown files or an Evernote export, skipping the ones imported before")
+	println("\t\t--file <file>\t\t- Zip of Markdown files or .enex export")
+	println("\t\t--format <format>\t- markdown or enex, by the file extension by default")
+	println("\t\t--author <user>\t\t- User creating the notes")
+	println("\t\t--queue <topic url>\t- Send the notes to the topic of the messaging app instead of creating them")
+	println("\thelp\t\t\t- Print the commands available")
+}
+
+func Run(options []string) {
+	if len(options) == 0 {
+		ListCommands()
+		return
+	}
+	var err error
+	switch options[0] {
+	case "export":
+		err = export(options[1:])
+	case "import":
+		err = importNotes(options[1:])
+	case "help":
+		fallthrough
+	default:
+		ListCommands()
+	}
+	if err != nil {
+		println("error:", err.Error())
+		os.Exit(1)
+	}
+}
+
+// export writes the notes to a file or the standard output. Messages go to the standard error,
+// so they do not mix with the notes
+func export(args []string) error {
+	var format, output, author string
+	var anonymous bool
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	fs.StringVar(&format, "format", note.ExportJSON, "json, ndjson, csv or markdown")
+	fs.StringVar(&output, "output", "", "file written, the standard output by default")
+	fs.StringVar(&author, "author", "", "user whose notes are exported, required without --anonymous")
+	fs.BoolVar(&anonymous, "anonymous", false, "export the notes created without a user instead")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if _, ok := note.LookupExport(format); !ok {
+		return errors.New("--format must be json, ndjson, csv or markdown")
+	}
+	switch {
+	case anonymous && author != "":
+		return errors.New("--author and --anonymous can not be used together")
+	case !anonymous && author == "":
+		return errors.New("--author is required")
+	}
+
+	// empty logger
+	log := zap.NewNop().Sugar()
+	if err := initVars(log); err != nil {
+		return err
+	}
+	defer func() {
+		if err := sys.R.Database.Close(); err != nil {
+			log.Errorf("could not close db conn gracefully: %s", err)
+		}
+	}()
+
+	var w io.Writer = os.Stdout
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			return fmt.Errorf("could not create output: %w", err)
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+		w = f
+	}
+
+	var err error
+	if anonymous {
+		err = note.ExportAnonymous(context.Background(), w, format)
+	} else {
+		err = note.Export(context.Background(), w, format, author)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to export: %w", err)
+	}
+	if output != "" {
+		println("notes exported to", output)
+	}
+	return nil
+}
+
+// importNotes creates the notes of an import file, or queues them to the messaging app, printing the result of every file
+func importNotes(args []string) error {
+	var file, format, author, queue string
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	fs.StringVar(&file, "file", "", "zip of markdown files or .enex export")
+	fs.StringVar(&format, "format", "", "markdown or enex, by the file extension by default")
+	fs.StringVar(&author, "author", "", "user creating the notes")
+	fs.StringVar(&queue, "queue", "", "topic url of the messaging app the notes are sent to")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if file == "" {
+		return errors.New("--file is required")
+	}
+	if format == "" {
+		format = note.ImportFormatOf(file)
+	}
+	if format != note.ImportMarkdown && format != note.ImportENEX {
+		return errors.New("--format must be markdown or enex")
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("could not read file: %w", err)
+	}
+	files, err := note.ReadImport(format, content)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+
+	// empty logger
+	log := zap.NewNop().Sugar()
+	var results []note.ImportResult
+	if queue != "" {
+		sys.R.Log = log
+		topic, err := pubsub.OpenTopic(context.Background(), queue)
+		if err != nil {
+			return fmt.Errorf("could not open topic: %w", err)
+		}
+		defer func() {
+			if err := topic.Shutdown(context.Background()); err != nil {
+				println("could not stop topic gracefully:", err.Error())
+			}
+		}()
+		results = note.QueueImport(context.Background(), topic, files, author)
+	} else {
+		if err := initVars(log); err != nil {
+			return err
+		}
+		defer func() {
+			if err := sys.R.Database.Close(); err != nil {
+				log.Errorf("could not close db conn gracefully: %s", err)
+			}
+		}()
+		results = note.ImportFiles(context.Background(), files, author)
+	}
+
+	counts := map[string]int{}
+	for _, r := range results {
+		counts[r.Status]++
+		switch {
+		case r.Error != "":
+			println(r.File, r.Status, r.Error)
+		case r.Id != 0:
+			println(r.File, r.Status, r.Id)
+		default:
+			println(r.File, r.Status)
+		}
+	}
+	println(fmt.Sprintf("%d created, %d duplicates, %d queued, %d failed", counts[note.ImportCreated],
+		counts[note.ImportDuplicate], counts[note.ImportQueued], counts[note.ImportFailed]))
+	return nil
+}
+
+func initVars(log *zap.SugaredLogger) error {
+	sys.Configs.Database.ConnectionURL = env.OrDefault(log, "DATABASE_CONNECTION_URL", "root:admin@localhost:3306/note")
+	sys.Configs.Database.Dialect = env.OrDefault(log, "DATABASE_DIALECT", "mysql")
+	sys.Configs.Database.PingTimeout = env.DurationDefault(log, "DATABASE_PING_TIMEOUT", "2s")
+	sys.Configs.Database.OperationTimeout = env.DurationDefault(log, "DATABASE_OPERATION_TIMEOUT", "5s")
+
+	// logger
+	sys.R.Log = log
+
+	// database of the configured dialect
+	var db *sql.DB
+	if err := func() error {
+		mysqlDb, err := sql.Open(sys.Configs.Database.Dialect, sys.Configs.Database.ConnectionURL)
+		if err != nil {
+			return fmt.Errorf("error to connecto to database: %w", err)
+		}
+		dbCtx, dbCancel := context.WithTimeout(context.Background(), sys.Configs.Database.PingTimeout)
+		defer dbCancel()
+		if err := mysqlDb.PingContext(dbCtx); err != nil {
+			return fmt.Errorf("could not connect to database: %w", err)
+		}
+		db = mysqlDb
+		return nil
+	}(); err != nil {
+		return err
+	}
+	sys.R.Database = db
+	return nil
+}
@@ -0,0 +1,82 @@
+package purge
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/platform/env"
+	"github.com/ribgsilva/note-api/sys"
+	"go.uber.org/zap"
+	"os"
+)
+
+func ListCommands() {
+	println("Purge Commands")
+	println("\trun\t\t\t- Permanently removes the notes in the trash for longer than TRASH_RETENTION")
+	println("\thelp\t\t\t- Print the commands available")
+}
+
+func Run(options []string) {
+	if len(options) == 0 {
+		ListCommands()
+		return
+	}
+	switch options[0] {
+	case "run":
+		// empty logger
+		log := zap.NewNop().Sugar()
+		if err := initVars(log); err != nil {
+			println("error:", err.Error())
+			return
+		}
+		defer func() {
+			if err := sys.R.Database.Close(); err != nil {
+				log.Errorf("could not close db conn gracefully: %s", err)
+			}
+		}()
+
+		println("purging trash older than", sys.Configs.Trash.Retention.String())
+		purged, err := note.Purge(context.Background(), sys.Configs.Trash.Retention)
+		if err != nil {
+			println("failed to purge trash:", err.Error())
+			os.Exit(1)
+		}
+		println("purged", purged, "notes")
+	case "help":
+		fallthrough
+	default:
+		ListCommands()
+	}
+}
+
+func initVars(log *zap.SugaredLogger) error {
+	sys.Configs.Database.ConnectionURL = env.OrDefault(log, "DATABASE_CONNECTION_URL", "root:admin@localhost:3306/note")
+	sys.Configs.Database.Dialect = env.OrDefault(log, "DATABASE_DIALECT", "mysql")
+	sys.Configs.Database.PingTimeout = env.DurationDefault(log, "DATABASE_PING_TIMEOUT", "2s")
+	sys.Configs.Database.OperationTimeout = env.DurationDefault(log, "DATABASE_OPERATION_TIMEOUT", "5s")
+	sys.Configs.Trash.Retention = env.DurationDefault(log, "TRASH_RETENTION", "720h")
+
+	// logger
+	sys.R.Log = log
+
+	// database of the configured dialect
+	var db *sql.DB
+	if err := func() error {
+		mysqlDb, err := sql.Open(sys.Configs.Database.Dialect, sys.Configs.Database.ConnectionURL)
+		if err != nil {
+			return fmt.Errorf("error to connecto to database: %w", err)
+		}
+		dbCtx, dbCancel := context.WithTimeout(context.Background(), sys.Configs.Database.PingTimeout)
+		defer dbCancel()
+		if err := mysqlDb.PingContext(dbCtx); err != nil {
+			return fmt.Errorf("could not connect to database: %w", err)
+		}
+		db = mysqlDb
+		return nil
+	}(); err != nil {
+		return err
+	}
+	sys.R.Database = db
+	return nil
+}
@@ -12,8 +12,9 @@ import (
 
 func ListCommands() {
 	println("Schema Commands")
-	println("\tcreate\t\t\t- Creates the schema")
+	println("\tcreate\t\t\t- Creates the schema and applies its migrations")
 	println("\tdelete\t\t\t- Deletes the schema")
+	println("\tmigrate\t\t\t- Applies the pending migrations of the database dialect")
 	println("\thelp\t\t\t- Print the commands available")
 }
 
@@ -37,8 +38,10 @@ func Run(options []string) {
 		println("creating schema")
 		if err := schema.Create(context.Background()); err != nil {
 			println("failed to create schema:", err.Error())
+		} else if applied, err := schema.Migrate(context.Background()); err != nil {
+			println("failed to migrate schema:", err.Error())
 		} else {
-			println("created schema")
+			println("created schema, applied", applied, "migrations")
 		}
 	case "delete":
 		println("deleting schema")
@@ -47,6 +50,13 @@ func Run(options []string) {
 		} else {
 			println("deleted schema")
 		}
+	case "migrate":
+		println("migrating schema")
+		if applied, err := schema.Migrate(context.Background()); err != nil {
+			println("failed to migrate schema:", err.Error())
+		} else {
+			println("applied", applied, "migrations")
+		}
 	case "help":
 		fallthrough
 	default:
@@ -56,16 +66,17 @@ func Run(options []string) {
 
 func initVars(log *zap.SugaredLogger) error {
 	sys.Configs.Database.ConnectionURL = env.OrDefault(log, "DATABASE_CONNECTION_URL", "root:admin@localhost:3306/note")
+	sys.Configs.Database.Dialect = env.OrDefault(log, "DATABASE_DIALECT", "mysql")
 	sys.Configs.Database.PingTimeout = env.DurationDefault(log, "DATABASE_PING_TIMEOUT", "2s")
 	sys.Configs.Database.OperationTimeout = env.DurationDefault(log, "DATABASE_OPERATION_TIMEOUT", "5s")
 
 	// logger
 	sys.R.Log = log
 
-	// mysql
+	// database of the configured dialect
 	var db *sql.DB
 	if err := func() error {
-		mysqlDb, err := sql.Open("mysql", sys.Configs.Database.ConnectionURL)
+		mysqlDb, err := sql.Open(sys.Configs.Database.Dialect, sys.Configs.Database.ConnectionURL)
 		if err != nil {
 			return fmt.Errorf("error to connecto to database: %w", err)
 		}
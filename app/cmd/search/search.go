@@ -0,0 +1,94 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/platform/env"
+	"github.com/ribgsilva/note-api/platform/search"
+	"github.com/ribgsilva/note-api/sys"
+	"go.uber.org/zap"
+)
+
+func ListCommands() {
+	println("Search Commands")
+	println("\treindex\t\t\t- Rebuilds the embedded search index from the database and saves it to SEARCH_INDEX_PATH")
+	println("\thelp\t\t\t- Print the commands available")
+}
+
+func Run(options []string) {
+	if len(options) == 0 {
+		ListCommands()
+		return
+	}
+	switch options[0] {
+	case "reindex":
+		// empty logger
+		log := zap.NewNop().Sugar()
+		if err := initVars(log); err != nil {
+			println("error:", err.Error())
+			return
+		}
+		defer func() {
+			if err := sys.R.Database.Close(); err != nil {
+				log.Errorf("could not close db conn gracefully: %s", err)
+			}
+		}()
+
+		lang, ok := search.ParseLanguage(sys.Configs.Search.Language)
+		if !ok {
+			println("invalid search language:", sys.Configs.Search.Language)
+			return
+		}
+		sys.R.Search = search.New(lang)
+
+		println("reindexing notes")
+		indexed, err := note.Reindex(context.Background())
+		if err != nil {
+			println("failed to reindex notes:", err.Error())
+			return
+		}
+		if err := sys.R.Search.Save(sys.Configs.Search.IndexPath); err != nil {
+			println("failed to save search index:", err.Error())
+			return
+		}
+		println("indexed", indexed, "notes into", sys.Configs.Search.IndexPath)
+	case "help":
+		fallthrough
+	default:
+		ListCommands()
+	}
+}
+
+func initVars(log *zap.SugaredLogger) error {
+	sys.Configs.Database.ConnectionURL = env.OrDefault(log, "DATABASE_CONNECTION_URL", "root:admin@localhost:3306/note")
+	sys.Configs.Database.Dialect = env.OrDefault(log, "DATABASE_DIALECT", "mysql")
+	sys.Configs.Database.PingTimeout = env.DurationDefault(log, "DATABASE_PING_TIMEOUT", "2s")
+	sys.Configs.Database.OperationTimeout = env.DurationDefault(log, "DATABASE_OPERATION_TIMEOUT", "5s")
+	sys.Configs.Search.IndexPath = env.OrDefault(log, "SEARCH_INDEX_PATH", "notes.index")
+	sys.Configs.Search.Language = env.OrDefault(log, "SEARCH_LANGUAGE", "en")
+
+	// logger
+	sys.R.Log = log
+
+	// database of the configured dialect
+	var db *sql.DB
+	if err := func() error {
+		mysqlDb, err := sql.Open(sys.Configs.Database.Dialect, sys.Configs.Database.ConnectionURL)
+		if err != nil {
+			return fmt.Errorf("error to connecto to database: %w", err)
+		}
+		dbCtx, dbCancel := context.WithTimeout(context.Background(), sys.Configs.Database.PingTimeout)
+		defer dbCancel()
+		if err := mysqlDb.PingContext(dbCtx); err != nil {
+			return fmt.Errorf("could not connect to database: %w", err)
+		}
+		db = mysqlDb
+		return nil
+	}(); err != nil {
+		return err
+	}
+	sys.R.Database = db
+	return nil
+}
@@ -4,56 +4,209 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"expvar"
+	"fmt"
 	"github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/platform/dispatch"
 	"github.com/ribgsilva/note-api/sys"
 	"gocloud.dev/pubsub"
+	"hash/fnv"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
+// orderingKey is the message metadata holding the key of the messages that must be processed in order,
+// for the ones without a note id, as creates
+const orderingKey = "orderingKey"
+
+// shutdown counts, served by the expvar handler
+var (
+	drained   = expvar.NewInt("messages_drained")
+	abandoned = expvar.NewInt("messages_abandoned")
+)
+
+// progress counts the messages the shards of a consumer finished and the ones they abandoned
+type progress struct {
+	handled   int64
+	abandoned int64
+}
+
+// Consume handles the messages of the subscription with maxWorkers shards. Messages with the same key, the
+// orderingKey metadata or the note id, always go to the same shard, so they are processed one at a time in the order
+// they were received, while messages of other notes are processed in parallel. How many of the shards handle a
+// message at once is limited by MESSAGING_MIN_WORKERS and maxWorkers, and receiving pauses while the database is down.
+// Once ctx is done it stops receiving, and the messages already received are handled with their own context for up to
+// MESSAGING_SHUTDOWN_TIMEOUT, so writes in flight are not cancelled midway. The ones left after it are abandoned,
+// nacked to be received again
 func Consume(ctx context.Context, sub *pubsub.Subscription, maxWorkers int) error {
-	logger := sys.R.Log
-	workers := make(chan int, maxWorkers)
+	rep := newReplier()
+	registry := newRegistry()
+	if maxWorkers < 1 {
+		maxWorkers = 1
+	}
+
+	work, abandon := context.WithCancel(context.Background())
+	defer abandon()
+
+	var wg sync.WaitGroup
+	var p progress
+	l := newLimiter(maxWorkers)
+	shards := make([]chan *pubsub.Message, maxWorkers)
+	for i := range shards {
+		shards[i] = make(chan *pubsub.Message, 1)
+		wg.Add(1)
+		go func(messages <-chan *pubsub.Message) {
+			defer wg.Done()
+			newShard(registry, rep, l, &p).run(work, messages)
+		}(shards[i])
+	}
 
 	var err error
+	var pending *pubsub.Message
+	var pendingShard int
+	next := 0
+	h := health{interval: sys.Configs.Messaging.HealthInterval}
 	for {
-		var message, err = sub.Receive(ctx)
-		if err != nil {
+		// the subscription keeps returning the messages it already pulled after ctx is done
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		if err = h.wait(ctx); err != nil {
+			break
+		}
+		var message *pubsub.Message
+		if message, err = sub.Receive(ctx); err != nil {
 			break
 		}
 
-		workers <- 1
-		go func(m *pubsub.Message) {
-			defer func() { <-workers }()
-			defer m.Ack()
+		// messages without a key are spread between the shards
+		shard := next
+		if k := key(message); k != "" {
+			h := fnv.New32a()
+			_, _ = h.Write([]byte(k))
+			shard = int(h.Sum32() % uint32(len(shards)))
+		} else {
+			next = (next + 1) % len(shards)
+		}
 
-			logger.Infof("message received: %s", string(m.Body))
-			var e note.Event
-			if err := json.Unmarshal(m.Body, &e); err != nil {
-				logger.Error("failed to parse body: ", err)
-				return
-			}
+		// a busy shard does not hold receiving from stopping, its message is drained with the others
+		select {
+		case shards[shard] <- message:
+			continue
+		case <-ctx.Done():
+			pending, pendingShard = message, shard
+			err = ctx.Err()
+		}
+		break
+	}
 
-			switch e.Type {
-			case "create":
-				var c note.NewNote
-				marshal, _ := json.Marshal(e.Data)
-				_ = json.Unmarshal(marshal, &c)
+	// the messages received are drained, and the ones left when the timeout expires are abandoned
+	handled := atomic.LoadInt64(&p.handled)
+	finished := make(chan struct{})
+	go func() {
+		if pending != nil {
+			shards[pendingShard] <- pending
+		}
+		for _, s := range shards {
+			close(s)
+		}
+		wg.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+	case <-time.After(sys.Configs.Messaging.ShutdownTimeout):
+		abandon()
+		<-finished
+	}
+
+	d, a := atomic.LoadInt64(&p.handled)-handled, atomic.LoadInt64(&p.abandoned)
+	drained.Add(d)
+	abandoned.Add(a)
+	sys.R.Log.Infow("shutdown", "status", "messages drained", "drained", d, "abandoned", a)
+
+	replyCtx, replyCancel := context.WithTimeout(context.Background(), sys.Configs.Messaging.ShutdownTimeout)
+	defer replyCancel()
+	rep.shutdown(replyCtx)
 
-				if err := note.Create(ctx, c); err != nil {
-					logger.Errorf("failed to create event %+v: err: %s", e.Data, err)
-				}
-			default:
-				logger.Error("unknown event type: ", e.Type)
-			}
-		}(message)
+	// the subscription ends when the context is cancelled, or at the end of a file
+	if !errors.Is(err, context.Canceled) && !errors.Is(err, io.EOF) {
+		return err
 	}
 
-	for w := 0; w < maxWorkers; w++ {
-		workers <- 1
+	return nil
+}
+
+// key returns the ordering key of a message, or the id of the note in its data
+func key(m *pubsub.Message) string {
+	if k := m.Metadata[orderingKey]; k != "" {
+		return k
 	}
 
-	if !errors.Is(err, context.Canceled) {
-		return err
+	e, err := dispatch.Decode(m.Body, m.Metadata)
+	if err != nil {
+		return ""
+	}
+	var data struct {
+		Id json.Number `json:"id"`
+	}
+	if err := json.Unmarshal(e.Data, &data); err != nil {
+		return ""
+	}
+	return data.Id.String()
+}
+
+// decode reads a message and validates it against the schema of its type, sending it to the dead-letter topic when
+// it is not valid, and replying why when the message asks for a reply. ok is false for the dead-lettered messages,
+// and it only fails when the message could not be dead-lettered, so it can be received again
+func decode(ctx context.Context, rep *replier, m *pubsub.Message) (e dispatch.Message, ok bool, err error) {
+	sys.R.Log.Infof("message received: %s", string(m.Body))
+
+	e, err = dispatch.Decode(m.Body, m.Metadata)
+	if err != nil {
+		return e, false, rejected(ctx, rep, m, e.Type, undecodable, []string{err.Error()})
+	}
+
+	report, err := note.ValidateEvent(e.Type, e.Version, e.Data)
+	if err != nil {
+		return e, false, err
 	}
+	if len(report) > 0 {
+		return e, false, rejected(ctx, rep, m, e.Type, invalid, report)
+	}
+	return e, true, nil
+}
 
+// rejected dead-letters a message and replies why
+func rejected(ctx context.Context, rep *replier, m *pubsub.Message, eventType, reason string, report []string) error {
+	if err := deadLetter(ctx, m, reason, report); err != nil {
+		return err
+	}
+	rep.reply(ctx, eventType, m.Metadata, 0, fmt.Errorf("%s: %s", reason, strings.Join(report, "; ")))
 	return nil
 }
+
+// done acks a message, or nacks it when it failed so it is received again
+func (p *progress) done(m *pubsub.Message, err error) {
+	atomic.AddInt64(&p.handled, 1)
+	if err != nil {
+		sys.R.Log.Error(err)
+		if m.Nackable() {
+			m.Nack()
+			return
+		}
+	}
+	m.Ack()
+}
+
+// abandon nacks a message left when the shutdown timeout expired, or whose handling was cancelled by it,
+// so it is received again
+func (p *progress) abandon(m *pubsub.Message) {
+	atomic.AddInt64(&p.abandoned, 1)
+	if m.Nackable() {
+		m.Nack()
+	}
+}
@@ -0,0 +1,65 @@
+package notes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/platform/dispatch"
+	"github.com/ribgsilva/note-api/sys"
+	"gocloud.dev/pubsub"
+	"time"
+)
+
+// Reasons of dead-lettering a message
+const (
+	undecodable = "undecodable"
+	invalid     = "invalid"
+	unhandled   = "unhandled"
+)
+
+// deadLettered counts the messages sent to the dead-letter topic per reason, served by the expvar handler
+var deadLettered = expvar.NewMap("messages_dead_lettered")
+
+// permanentCause returns why a message failed when it failed for what it carries, so receiving it again would fail
+// the same way, and nil otherwise. Other failures, as the database being down or timing out, may pass on a new attempt
+func permanentCause(err error) error {
+	for _, target := range []error{
+		dispatch.ErrDecode, dispatch.ErrUnknownType, dispatch.ErrPanic, errNotFound,
+		note.ErrNotFound, note.ErrVersionMismatch, note.ErrNotebookNotFound, note.ErrInvalidTags,
+	} {
+		if errors.Is(err, target) {
+			return target
+		}
+	}
+	return nil
+}
+
+// deadLetter sends a message that can not be handled to the dead-letter topic, along with the report of why.
+// Without a dead-letter topic the report is only logged
+func deadLetter(ctx context.Context, m *pubsub.Message, reason string, report []string) error {
+	sys.R.Log.Errorw("message dead-lettered", "reason", reason, "report", report, "body", string(m.Body))
+	deadLettered.Add(reason, 1)
+
+	topic := sys.R.DeadLetter
+	if topic == nil {
+		return nil
+	}
+
+	body, err := json.Marshal(dispatch.DeadLetter{
+		Reason:   reason,
+		Report:   report,
+		Body:     string(m.Body),
+		Metadata: m.Metadata,
+		FailedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to parse dead letter: %w", err)
+	}
+	if err := topic.Send(ctx, &pubsub.Message{Body: body, Metadata: map[string]string{"reason": reason}}); err != nil {
+		return fmt.Errorf("failed to send dead letter: %w", err)
+	}
+	return nil
+}
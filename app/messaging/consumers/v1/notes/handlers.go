@@ -0,0 +1,62 @@
+package notes
+
+import (
+	"context"
+	"errors"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/platform/dispatch"
+	"github.com/ribgsilva/note-api/sys"
+)
+
+// authorKey is the message metadata holding the user that made the change
+const authorKey = "user"
+
+var (
+	// errNotFound is returned when updating a note that does not exist
+	errNotFound = errors.New("note not found")
+	// errReplyNotAllowed is returned when replying to a url out of MESSAGING_REPLY_URLS
+	errReplyNotAllowed = errors.New("reply url not allowed")
+)
+
+// newRegistry routes the note messages to their handlers, logging, measuring and recovering every one of them.
+// Replies are sent by the shards, once they know the final outcome of a message
+func newRegistry() *dispatch.Registry {
+	r := dispatch.NewRegistry(sys.Configs.Messaging.StrictDecoding)
+	r.Use(dispatch.Logging(sys.R.Log), dispatch.Metrics(), dispatch.Recover())
+
+	dispatch.Register(r, "create", "1", create)
+	dispatch.Register(r, "update", "1", update)
+	dispatch.Register(r, note.ImportType, "1", importNote)
+	return r
+}
+
+func create(ctx context.Context, c note.NewNote, m dispatch.Message) error {
+	created, err := note.Create(ctx, c, m.Metadata[authorKey])
+	if err != nil {
+		return err
+	}
+	replied(ctx, created.Id)
+	return nil
+}
+
+func update(ctx context.Context, u note.UpdateNote, m dispatch.Message) error {
+	updated, err := note.Update(ctx, u, m.Metadata[authorKey])
+	switch {
+	case err != nil:
+		return err
+	case updated.Id == 0:
+		return errNotFound
+	}
+	replied(ctx, updated.Id)
+	return nil
+}
+
+// importNote creates a note queued by a large import, unless a note with the same content was imported before
+func importNote(ctx context.Context, i note.ImportNote, m dispatch.Message) error {
+	n, _, err := note.Import(ctx, i.NewNote, m.Metadata[authorKey])
+	if err != nil {
+		return err
+	}
+	replied(ctx, n.Id)
+	return nil
+}
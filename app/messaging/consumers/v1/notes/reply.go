@@ -0,0 +1,129 @@
+package notes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/ribgsilva/note-api/platform/dispatch"
+	"github.com/ribgsilva/note-api/sys"
+	"gocloud.dev/pubsub"
+	"strings"
+	"sync"
+)
+
+// replier sends the result of the messages with a reply topic, for producers that need the id of the note they created.
+// Replies only go to urls starting with one of MESSAGING_REPLY_URLS, and the topics are kept open while consuming
+type replier struct {
+	allowed []string
+
+	mu     sync.Mutex
+	topics map[string]*pubsub.Topic
+}
+
+func newReplier() *replier {
+	return &replier{
+		allowed: sys.Configs.Messaging.ReplyURLs,
+		topics:  map[string]*pubsub.Topic{},
+	}
+}
+
+// result holds the id of the note a handler created or changed, to be replied
+type result struct {
+	id uint64
+}
+
+type resultKey struct{}
+
+// replied sets the id of the note a handler created or changed, when the message is waiting for a reply
+func replied(ctx context.Context, id uint64) {
+	if r, ok := ctx.Value(resultKey{}).(*result); ok {
+		r.id = id
+	}
+}
+
+// errUnhandled is replied for messages that failed for reasons the producer can not act on, as a panic
+var errUnhandled = errors.New("failed to handle the message")
+
+// replyError is what is replied for a message that failed for good, the cause when the producer can act on it,
+// without the details of how it failed
+func replyError(err error) error {
+	if cause := permanentCause(err); cause != nil && cause != dispatch.ErrPanic {
+		return cause
+	}
+	return errUnhandled
+}
+
+// reply sends the result of a message to its reply topic, if it has one. The message was already handled,
+// so failing to reply is only logged
+func (r *replier) reply(ctx context.Context, eventType string, metadata map[string]string, id uint64, err error) {
+	to := metadata[dispatch.ReplyToKey]
+	if to == "" {
+		return
+	}
+
+	topic, openErr := r.topic(ctx, to)
+	if openErr != nil {
+		sys.R.Log.Errorw("failed to reply", "replyTo", to, "ERROR", openErr)
+		return
+	}
+
+	reply := dispatch.Reply{
+		CorrelationId: metadata[dispatch.CorrelationIdKey],
+		Type:          eventType,
+		Id:            id,
+	}
+	if err != nil {
+		reply.Id = 0
+		reply.Error = err.Error()
+	}
+	body, _ := json.Marshal(reply)
+
+	replyMetadata := map[string]string{}
+	if reply.CorrelationId != "" {
+		replyMetadata[dispatch.CorrelationIdKey] = reply.CorrelationId
+	}
+	if err := topic.Send(ctx, &pubsub.Message{Body: body, Metadata: replyMetadata}); err != nil {
+		sys.R.Log.Errorw("failed to reply", "replyTo", to, "ERROR", err)
+	}
+}
+
+// topic returns the open topic of a reply url, opening it on its first reply
+func (r *replier) topic(ctx context.Context, url string) (*pubsub.Topic, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if t, ok := r.topics[url]; ok {
+		return t, nil
+	}
+	if !r.allow(url) {
+		return nil, errReplyNotAllowed
+	}
+	t, err := pubsub.OpenTopic(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	r.topics[url] = t
+	return t, nil
+}
+
+func (r *replier) allow(url string) bool {
+	for _, prefix := range r.allowed {
+		if prefix != "" && strings.HasPrefix(url, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// shutdown closes the reply topics, sending the replies left
+func (r *replier) shutdown(ctx context.Context) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for url, t := range r.topics {
+		if err := t.Shutdown(ctx); err != nil {
+			sys.R.Log.Errorf("could not stop reply topic %s gracefully: %s", url, err)
+		}
+	}
+	r.topics = map[string]*pubsub.Topic{}
+}
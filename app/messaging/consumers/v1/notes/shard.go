@@ -0,0 +1,195 @@
+package notes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"expvar"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/platform/concurrency"
+	"github.com/ribgsilva/note-api/platform/dispatch"
+	"github.com/ribgsilva/note-api/sys"
+	"gocloud.dev/pubsub"
+	"time"
+)
+
+// batched counts the create messages inserted in batches, served by the expvar handler
+var batched = expvar.NewInt("messages_batched")
+
+// shard handles its messages one at a time. With MESSAGING_BATCH_SIZE > 1, consecutive creates are held and inserted
+// together once the batch is full, MESSAGING_BATCH_WAIT went by since the first one, or another message arrives,
+// so the order of the messages is kept
+type shard struct {
+	registry *dispatch.Registry
+	replier  *replier
+	limiter  *concurrency.Limiter
+	progress *progress
+	size     int
+	wait     time.Duration
+
+	messages []*pubsub.Message
+	events   []dispatch.Message
+	creates  []note.NewNote
+	authors  []string
+}
+
+func newShard(registry *dispatch.Registry, rep *replier, l *concurrency.Limiter, p *progress) *shard {
+	return &shard{
+		registry: registry,
+		replier:  rep,
+		limiter:  l,
+		progress: p,
+		size:     sys.Configs.Messaging.BatchSize,
+		wait:     sys.Configs.Messaging.BatchWait,
+	}
+}
+
+// run handles the messages until the channel is closed, inserting the batch left. Once ctx is done the messages
+// left are abandoned without being handled
+func (s *shard) run(ctx context.Context, messages <-chan *pubsub.Message) {
+	var timer *time.Timer
+	var expired <-chan time.Time
+	flush := func() {
+		if timer != nil {
+			timer.Stop()
+			timer, expired = nil, nil
+		}
+		s.flush(ctx)
+	}
+
+	for {
+		select {
+		case m, open := <-messages:
+			if !open {
+				flush()
+				return
+			}
+
+			if ctx.Err() != nil {
+				s.progress.abandon(m)
+				continue
+			}
+
+			e, ok, err := decode(ctx, s.replier, m)
+			switch {
+			case !ok && err != nil && ctx.Err() != nil:
+				s.progress.abandon(m)
+				continue
+			case !ok:
+				s.progress.done(m, err)
+				continue
+			}
+
+			if s.add(m, e) {
+				if len(s.messages) >= s.size {
+					flush()
+				} else if timer == nil {
+					timer = time.NewTimer(s.wait)
+					expired = timer.C
+				}
+				continue
+			}
+
+			flush()
+			s.dispatch(ctx, m, e)
+		case <-expired:
+			timer, expired = nil, nil
+			s.flush(ctx)
+		}
+	}
+}
+
+// add holds a create message in the batch, returning false when batching is disabled or the message is not a create
+func (s *shard) add(m *pubsub.Message, e dispatch.Message) bool {
+	if s.size <= 1 || e.Type != "create" || (e.Version != "" && e.Version != dispatch.DefaultVersion) {
+		return false
+	}
+
+	// data that does not decode is handled alone, so the registry reports it
+	var c note.NewNote
+	decoder := json.NewDecoder(bytes.NewReader(e.Data))
+	if sys.Configs.Messaging.StrictDecoding {
+		decoder.DisallowUnknownFields()
+	}
+	if err := decoder.Decode(&c); err != nil {
+		return false
+	}
+
+	s.messages = append(s.messages, m)
+	s.events = append(s.events, e)
+	s.creates = append(s.creates, c)
+	s.authors = append(s.authors, e.Metadata[authorKey])
+	return true
+}
+
+// flush inserts the batch in a single transaction and acks its messages. When it fails, the messages are handled
+// one by one, so each one is acked or nacked on its own outcome and a bad message does not fail the others
+func (s *shard) flush(ctx context.Context) {
+	if len(s.messages) == 0 {
+		return
+	}
+	defer func() {
+		s.messages, s.events, s.creates, s.authors = nil, nil, nil, nil
+	}()
+
+	if err := s.limiter.Acquire(ctx); err != nil {
+		for _, m := range s.messages {
+			s.progress.abandon(m)
+		}
+		return
+	}
+	start := time.Now()
+	created, err := note.CreateMany(ctx, s.creates, s.authors)
+	s.limiter.Release(time.Since(start)/time.Duration(len(s.messages)), err)
+	if err == nil {
+		sys.R.Log.Infow("batch handled", "type", "create", "size", len(s.messages), "duration", time.Since(start))
+		batched.Add(int64(len(s.messages)))
+		for i, m := range s.messages {
+			s.replier.reply(ctx, s.events[i].Type, m.Metadata, created[i].Id, nil)
+			s.progress.done(m, nil)
+		}
+		return
+	}
+	if ctx.Err() != nil {
+		for _, m := range s.messages {
+			s.progress.abandon(m)
+		}
+		return
+	}
+
+	sys.R.Log.Errorw("batch failed, handling its messages one by one", "type", "create", "size", len(s.messages), "ERROR", err)
+	for i, m := range s.messages {
+		s.dispatch(ctx, m, s.events[i])
+	}
+}
+
+// dispatch handles a message with its registered handler, acks it and replies its result. A message that failed for
+// what it carries is dead-lettered and replied why, one that failed for being cancelled is abandoned, and any other
+// failure is nacked to be tried again, without a reply until its final outcome
+func (s *shard) dispatch(ctx context.Context, m *pubsub.Message, e dispatch.Message) {
+	if err := s.limiter.Acquire(ctx); err != nil {
+		s.progress.abandon(m)
+		return
+	}
+	start := time.Now()
+	// failures are reported by the registry middleware
+	res := &result{}
+	err := s.registry.Dispatch(context.WithValue(ctx, resultKey{}, res), e)
+	s.limiter.Release(time.Since(start), err)
+	switch {
+	case err == nil:
+		s.replier.reply(ctx, e.Type, e.Metadata, res.id, nil)
+		s.progress.done(m, nil)
+	case ctx.Err() != nil:
+		s.progress.abandon(m)
+	case permanentCause(err) != nil:
+		if dlErr := deadLetter(ctx, m, unhandled, []string{err.Error()}); dlErr != nil {
+			s.progress.done(m, dlErr)
+			return
+		}
+		s.replier.reply(ctx, e.Type, e.Metadata, 0, replyError(err))
+		s.progress.done(m, nil)
+	default:
+		s.progress.done(m, err)
+	}
+}
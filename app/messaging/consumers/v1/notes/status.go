@@ -0,0 +1,103 @@
+package notes
+
+import (
+	"context"
+	"expvar"
+	"github.com/ribgsilva/note-api/platform/concurrency"
+	"github.com/ribgsilva/note-api/sys"
+	"sync"
+	"time"
+)
+
+// Status is the state of the consumer, served by the healthcheck and the expvar handler
+type Status struct {
+	Concurrency int  `json:"concurrency" example:"4"`
+	InFlight    int  `json:"inFlight" example:"2"`
+	Paused      bool `json:"paused" example:"false"`
+}
+
+var (
+	statusMu sync.Mutex
+	limiter  *concurrency.Limiter
+	paused   bool
+)
+
+func init() {
+	expvar.Publish("messages_consumer", expvar.Func(func() any {
+		return CurrentStatus()
+	}))
+}
+
+// CurrentStatus returns how many messages the consumer can handle at once, how many it is handling,
+// and whether it stopped receiving for the database being unhealthy
+func CurrentStatus() Status {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+
+	s := Status{Paused: paused}
+	if limiter != nil {
+		s.Concurrency = limiter.Limit()
+		s.InFlight = limiter.InFlight()
+	}
+	return s
+}
+
+// newLimiter limits the messages handled at once to maxWorkers, or, with MESSAGING_ADAPTIVE, between
+// MESSAGING_MIN_WORKERS and maxWorkers depending on their latency and error rate
+func newLimiter(maxWorkers int) *concurrency.Limiter {
+	l := concurrency.Fixed(maxWorkers)
+	if c := sys.Configs.Messaging; c.Adaptive {
+		l = concurrency.New(c.MinWorkers, maxWorkers, c.TargetLatency, c.MaxErrorRate, c.AdaptInterval)
+	}
+
+	statusMu.Lock()
+	defer statusMu.Unlock()
+	limiter, paused = l, false
+	return l
+}
+
+// health pauses receiving while the database does not answer its pings, checking it at most once every interval
+type health struct {
+	interval time.Duration
+	checked  time.Time
+}
+
+// wait returns once the database is healthy, or ctx is done
+func (h *health) wait(ctx context.Context) error {
+	if h.interval <= 0 || time.Since(h.checked) < h.interval {
+		return nil
+	}
+
+	for {
+		pingCtx, pingCancel := context.WithTimeout(ctx, sys.Configs.Database.PingTimeout)
+		err := sys.R.Database.PingContext(pingCtx)
+		pingCancel()
+		h.checked = time.Now()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		setPaused(err != nil, err)
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(h.interval):
+		}
+	}
+}
+
+func setPaused(p bool, err error) {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+
+	switch {
+	case p && !paused:
+		sys.R.Log.Errorw("database unhealthy, receiving paused", "ERROR", err)
+	case !p && paused:
+		sys.R.Log.Infow("database healthy, receiving resumed")
+	}
+	paused = p
+}
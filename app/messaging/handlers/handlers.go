@@ -0,0 +1,11 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ribgsilva/note-api/app/messaging/handlers/v1/healthcheck"
+	"github.com/ribgsilva/note-api/platform/web/handler"
+)
+
+func MapDefaults(r *gin.Engine) {
+	r.GET("/v1/healthcheck", handler.Wrapper(healthcheck.Get))
+}
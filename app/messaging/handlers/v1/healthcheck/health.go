@@ -0,0 +1,19 @@
+package healthcheck
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ribgsilva/note-api/app/messaging/consumers/v1/notes"
+	"github.com/ribgsilva/note-api/platform/web/handler"
+	"net/http"
+)
+
+// Get godoc
+// @Summary Check if it is running
+// @Description Check if it is running, with how many messages it handles at once and whether receiving is paused
+// @Tags Healthcheck
+// @Produce json
+// @Success 200 {object} notes.Status
+// @Router /v1/healthcheck [get]
+func Get(ctx *gin.Context) handler.Result {
+	return handler.Result{Status: http.StatusOK, Body: notes.CurrentStatus()}
+}
@@ -3,25 +3,39 @@ package main
 import (
 	"context"
 	"database/sql"
+	"expvar"
 	"fmt"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	"github.com/gin-gonic/gin"
 	"github.com/go-redis/redis/v8"
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/mattn/go-sqlite3"
 	"github.com/newrelic/go-agent/v3/integrations/nrgin"
 	"github.com/newrelic/go-agent/v3/newrelic"
-	"github.com/ribgsilva/note-api/app/api/handlers"
 	"github.com/ribgsilva/note-api/app/messaging/consumers/v1/notes"
+	"github.com/ribgsilva/note-api/app/messaging/handlers"
+	"github.com/ribgsilva/note-api/app/messaging/workers/v1/outbox"
+	"github.com/ribgsilva/note-api/app/messaging/workers/v1/trash"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/persistence/v1/schema"
 	"github.com/ribgsilva/note-api/platform/env"
+	_ "github.com/ribgsilva/note-api/platform/filepubsub"
 	"github.com/ribgsilva/note-api/platform/logger"
 	"github.com/ribgsilva/note-api/sys"
 	"go.uber.org/automaxprocs/maxprocs"
 	"go.uber.org/zap"
+	"gocloud.dev/pubsub"
 	"gocloud.dev/pubsub/awssnssqs"
+	_ "gocloud.dev/pubsub/kafkapubsub"
+	_ "gocloud.dev/pubsub/mempubsub"
+	_ "gocloud.dev/pubsub/natspubsub"
+	_ "gocloud.dev/pubsub/rabbitpubsub"
 	"net/http"
 	"os"
 	"os/signal"
 	"runtime"
+	"strings"
 	"syscall"
 )
 
@@ -57,21 +71,48 @@ func run(log *zap.SugaredLogger) error {
 	sys.Configs.Database.ConnectionURL = env.OrDefault(log, "DATABASE_CONNECTION_URL", "root:admin@localhost:3306/note")
 	sys.Configs.Database.PingTimeout = env.DurationDefault(log, "DATABASE_PING_TIMEOUT", "2s")
 	sys.Configs.Database.OperationTimeout = env.DurationDefault(log, "DATABASE_OPERATION_TIMEOUT", "5s")
+	sys.Configs.Database.Dialect = env.OrDefault(log, "DATABASE_DIALECT", "mysql")
+	sys.Configs.Database.Migrate = env.BoolDefault(log, "DATABASE_MIGRATE", "t")
 	sys.Configs.Cache.ConnectionURL = env.OrDefault(log, "CACHE_CONNECTION_URL", "localhost:6379")
 	sys.Configs.Cache.User = env.OrDefault(log, "CACHE_USER", "")
 	sys.Configs.Cache.Pass = env.OrDefault(log, "CACHE_PASS", "")
 	sys.Configs.Cache.PingTimeout = env.DurationDefault(log, "CACHE_PING_TIMEOUT", "2s")
 	sys.Configs.Cache.OperationTimeout = env.DurationDefault(log, "CACHE_PING_TIMEOUT", "10s")
 	sys.Configs.Cache.CacheTTL = env.DurationDefault(log, "CACHE_CACHE_TTL", "24h")
+	sys.Configs.Events.TopicURL = env.OrDefault(log, "EVENTS_TOPIC_URL", "")
+	sys.Configs.Events.Format = env.OrDefault(log, "EVENTS_FORMAT", note.LegacyFormat)
+	sys.Configs.Events.Source = env.OrDefault(log, "EVENTS_SOURCE", "/note-api")
+	sys.Configs.Events.ShutdownTimeout = env.DurationDefault(log, "EVENTS_SHUTDOWN_TIMEOUT", "10s")
+	sys.Configs.Events.Outbox = env.BoolDefault(log, "EVENTS_OUTBOX", "f")
+	sys.Configs.Events.RelayInterval = env.DurationDefault(log, "EVENTS_RELAY_INTERVAL", "1s")
+	sys.Configs.Events.RelayBatch = env.IntDefault(log, "EVENTS_RELAY_BATCH", "100")
+	sys.Configs.Events.RelayMaxBackoff = env.DurationDefault(log, "EVENTS_RELAY_MAX_BACKOFF", "1m")
+	sys.Configs.Events.OutboxRetention = env.DurationDefault(log, "EVENTS_OUTBOX_RETENTION", "24h")
 	sys.Configs.NewRelic.AppName = env.OrDefault(log, "NEW_RELIC_APP_NAME", "person-api")
 	sys.Configs.NewRelic.Licence = env.OrDefault(log, "NEW_RELIC_LICENCE", "")
 	sys.Configs.NewRelic.Enabled = env.BoolDefault(log, "NEW_RELIC_ENABLED", "f")
 	sys.Configs.NewRelic.ConnectionTimeout = env.DurationDefault(log, "NEW_RELIC_CONNECTION_TIMEOUT", "10s")
 	sys.Configs.NewRelic.ShutdownTimeout = env.DurationDefault(log, "NEW_RELIC_SHUTDOWN_TIMEOUT", "10s")
-	sys.Configs.Messaging.TopicName = env.Must(log, "MESSAGING_TOPIC_NAME")
+	sys.Configs.Messaging.SubscriptionURL = env.OrDefault(log, "MESSAGING_SUBSCRIPTION_URL", "")
+	if sys.Configs.Messaging.SubscriptionURL == "" {
+		sys.Configs.Messaging.TopicName = env.Must(log, "MESSAGING_TOPIC_NAME")
+	}
+	sys.Configs.Messaging.DeadLetterURL = env.OrDefault(log, "MESSAGING_DEAD_LETTER_URL", "")
+	sys.Configs.Messaging.ReplyURLs = strings.Split(env.OrDefault(log, "MESSAGING_REPLY_URLS", ""), ",")
+	sys.Configs.Messaging.StrictDecoding = env.BoolDefault(log, "MESSAGING_STRICT_DECODING", "f")
 	sys.Configs.Messaging.MaxWorkers = env.IntDefault(log, "MESSAGING_MAX_WORKERS", "1")
+	sys.Configs.Messaging.Adaptive = env.BoolDefault(log, "MESSAGING_ADAPTIVE", "f")
+	sys.Configs.Messaging.MinWorkers = env.IntDefault(log, "MESSAGING_MIN_WORKERS", "1")
+	sys.Configs.Messaging.TargetLatency = env.DurationDefault(log, "MESSAGING_TARGET_LATENCY", "500ms")
+	sys.Configs.Messaging.MaxErrorRate = env.FloatDefault(log, "MESSAGING_MAX_ERROR_RATE", "0.1")
+	sys.Configs.Messaging.AdaptInterval = env.DurationDefault(log, "MESSAGING_ADAPT_INTERVAL", "5s")
+	sys.Configs.Messaging.HealthInterval = env.DurationDefault(log, "MESSAGING_HEALTH_INTERVAL", "5s")
+	sys.Configs.Messaging.BatchSize = env.IntDefault(log, "MESSAGING_BATCH_SIZE", "1")
+	sys.Configs.Messaging.BatchWait = env.DurationDefault(log, "MESSAGING_BATCH_WAIT", "100ms")
 	sys.Configs.Messaging.WaitTime = env.DurationDefault(log, "MESSAGING_WAIT_TIME", "10s")
 	sys.Configs.Messaging.ShutdownTimeout = env.DurationDefault(log, "MESSAGING_SHUTDOWN_TIMEOUT", "10s")
+	sys.Configs.Trash.Retention = env.DurationDefault(log, "TRASH_RETENTION", "720h")
+	sys.Configs.Trash.PurgeInterval = env.DurationDefault(log, "TRASH_PURGE_INTERVAL", "1h")
 
 	// =======================================================================================================
 	// Setup static resources
@@ -79,10 +120,10 @@ func run(log *zap.SugaredLogger) error {
 	// logger
 	sys.R.Log = log
 
-	// mysql
+	// database of the configured dialect
 	var db *sql.DB
 	if err := func() error {
-		mysqlDb, err := sql.Open("mysql", sys.Configs.Database.ConnectionURL)
+		mysqlDb, err := sql.Open(sys.Configs.Database.Dialect, sys.Configs.Database.ConnectionURL)
 		if err != nil {
 			return fmt.Errorf("error to connecto to database: %w", err)
 		}
@@ -103,6 +144,15 @@ func run(log *zap.SugaredLogger) error {
 	}()
 	sys.R.Database = db
 
+	// migrations
+	if sys.Configs.Database.Migrate {
+		applied, err := schema.Migrate(context.Background())
+		if err != nil {
+			return fmt.Errorf("could not migrate the database: %w", err)
+		}
+		log.Infof("applied %d migrations", applied)
+	}
+
 	// redis
 	// doing in a func, so I can use defer to cancel the contexts
 	var rdb *redis.Client
@@ -129,6 +179,43 @@ func run(log *zap.SugaredLogger) error {
 
 	sys.R.Cache = rdb
 
+	// events
+	if !note.ValidFormat(sys.Configs.Events.Format) {
+		return fmt.Errorf("invalid events format: %s", sys.Configs.Events.Format)
+	}
+	if sys.Configs.Events.TopicURL != "" {
+		topic, err := pubsub.OpenTopic(context.Background(), sys.Configs.Events.TopicURL)
+		if err != nil {
+			return fmt.Errorf("could not open events topic: %w", err)
+		}
+		defer func() {
+			stdCtx, stdCancel := context.WithTimeout(context.Background(), sys.Configs.Events.ShutdownTimeout)
+			defer stdCancel()
+
+			if err := topic.Shutdown(stdCtx); err != nil {
+				log.Errorf("could not stop events topic gracefully: %s", err)
+			}
+		}()
+		sys.R.Events = topic
+	}
+
+	// dead letter
+	if sys.Configs.Messaging.DeadLetterURL != "" {
+		topic, err := pubsub.OpenTopic(context.Background(), sys.Configs.Messaging.DeadLetterURL)
+		if err != nil {
+			return fmt.Errorf("could not open dead-letter topic: %w", err)
+		}
+		defer func() {
+			stdCtx, stdCancel := context.WithTimeout(context.Background(), sys.Configs.Messaging.ShutdownTimeout)
+			defer stdCancel()
+
+			if err := topic.Shutdown(stdCtx); err != nil {
+				log.Errorf("could not stop dead-letter topic gracefully: %s", err)
+			}
+		}()
+		sys.R.DeadLetter = topic
+	}
+
 	// =======================================================================================================
 	// NR
 
@@ -148,22 +235,11 @@ func run(log *zap.SugaredLogger) error {
 	// =======================================================================================================
 	// Messaging configuration
 
-	cfg, err := config.LoadDefaultConfig(context.Background())
+	subscription, err := openSubscription()
 	if err != nil {
 		return err
 	}
 
-	sqsCli := sqs.NewFromConfig(cfg)
-
-	subscription := awssnssqs.OpenSubscriptionV2(
-		context.Background(),
-		sqsCli,
-		sys.Configs.Messaging.TopicName,
-		&awssnssqs.SubscriptionOptions{
-			Raw:      true,
-			WaitTime: sys.Configs.Messaging.WaitTime,
-		})
-
 	defer func() {
 		stdCtx, stdCancel := context.WithTimeout(context.Background(), sys.Configs.Messaging.ShutdownTimeout)
 		defer stdCancel()
@@ -182,6 +258,7 @@ func run(log *zap.SugaredLogger) error {
 	}), gin.Recovery(), nrgin.Middleware(nrApp))
 
 	handlers.MapDefaults(router)
+	router.GET("/debug/vars", gin.WrapH(expvar.Handler()))
 
 	// =======================================================================================================
 	// App start and shutdown
@@ -211,9 +288,57 @@ func run(log *zap.SugaredLogger) error {
 		cancelFunc()
 	}()
 
+	// every replica purges on its own, so TRASH_PURGE_INTERVAL should be 0s on all but one of them
+	if sys.Configs.Trash.PurgeInterval > 0 {
+		go trash.Purge(withCancel, sys.Configs.Trash.PurgeInterval, sys.Configs.Trash.Retention)
+	}
+
+	if sys.Configs.Events.Outbox && sys.R.Events != nil && sys.Configs.Events.RelayInterval > 0 {
+		go outbox.Relay(withCancel, sys.R.Events, sys.Configs.Events.RelayInterval, sys.Configs.Events.RelayBatch,
+			sys.Configs.Events.RelayMaxBackoff, sys.Configs.Events.OutboxRetention)
+	}
+
+	// consuming stops receiving on the signal and drains the messages received, then the healthcheck server is stopped
+	// before the resources are closed
 	if err := notes.Consume(withCancel, subscription, sys.Configs.Messaging.MaxWorkers); err != nil {
+		_ = svr.Close()
 		return fmt.Errorf("listener error: %w", err)
 	}
 
+	ctx, cancel := context.WithTimeout(context.Background(), sys.Configs.Messaging.ShutdownTimeout)
+	defer cancel()
+
+	if err := svr.Shutdown(ctx); err != nil {
+		_ = svr.Close()
+		return fmt.Errorf("could not stop healthcheck server gracefully: %w", err)
+	}
 	return nil
 }
+
+// openSubscription opens the MESSAGING_SUBSCRIPTION_URL with any of the imported drivers,
+// or the MESSAGING_TOPIC_NAME queue url in SQS when there is no subscription url
+func openSubscription() (*pubsub.Subscription, error) {
+	if sys.Configs.Messaging.SubscriptionURL != "" {
+		subscription, err := pubsub.OpenSubscription(context.Background(), sys.Configs.Messaging.SubscriptionURL)
+		if err != nil {
+			return nil, fmt.Errorf("could not open subscription: %w", err)
+		}
+		return subscription, nil
+	}
+
+	cfg, err := config.LoadDefaultConfig(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	sqsCli := sqs.NewFromConfig(cfg)
+
+	return awssnssqs.OpenSubscriptionV2(
+		context.Background(),
+		sqsCli,
+		sys.Configs.Messaging.TopicName,
+		&awssnssqs.SubscriptionOptions{
+			Raw:      true,
+			WaitTime: sys.Configs.Messaging.WaitTime,
+		}), nil
+}
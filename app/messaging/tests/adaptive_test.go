@@ -0,0 +1,150 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/ribgsilva/note-api/app/messaging/consumers/v1/notes"
+	"github.com/ribgsilva/note-api/sys"
+	"gocloud.dev/pubsub"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func (nt *NoteTests) testAdaptive(t *testing.T) {
+	sys.Configs.Messaging.Adaptive = true
+	sys.Configs.Messaging.MinWorkers = 1
+	sys.Configs.Messaging.AdaptInterval = time.Nanosecond
+	defer func() {
+		sys.Configs.Messaging.Adaptive = false
+		sys.Configs.Messaging.MinWorkers = 0
+		sys.Configs.Messaging.AdaptInterval = 0
+		sys.Configs.Messaging.TargetLatency = 0
+		sys.Configs.Messaging.MaxErrorRate = 0
+	}()
+
+	// fast messages raise the concurrency up to the max workers, and slow ones bring it down to the min
+	sys.Configs.Messaging.TargetLatency = time.Hour
+	sys.Configs.Messaging.MaxErrorRate = 1
+	nt.consumeAdaptive(t, "fast", 4)
+	if status := notes.CurrentStatus(); status.Concurrency != 4 || status.InFlight != 0 {
+		t.Fatalf("Test testAdaptive: should have raised the concurrency to the max workers: %+v", status)
+	}
+
+	sys.Configs.Messaging.TargetLatency = time.Nanosecond
+	nt.consumeAdaptive(t, "slow", 4)
+	if status := notes.CurrentStatus(); status.Concurrency != 1 {
+		t.Fatalf("Test testAdaptive: should have kept the concurrency at the min workers: %+v", status)
+	}
+
+	nt.testPause(t)
+}
+
+// consumeAdaptive creates notes with different ordering keys, so they are spread between the workers
+func (nt *NoteTests) consumeAdaptive(t *testing.T, name string, maxWorkers int) {
+	var lines []string
+	for i := 0; i < 50; i++ {
+		lines = append(lines, fmt.Sprintf(`{"body":{"type":"create","data":{"title":"%s %d","text":"adaptive text"}},"metadata":{"orderingKey":"%d"}}`, name, i, i))
+	}
+	path := filepath.Join(t.TempDir(), name+".jsonl")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o600); err != nil {
+		t.Fatalf("Test testAdaptive: failed to write the messages: %s", err)
+	}
+
+	subscription, err := pubsub.OpenSubscription(context.Background(), "file://"+path)
+	if err != nil {
+		t.Fatalf("Test testAdaptive: failed to open the file subscription: %s", err)
+	}
+	defer func() {
+		_ = subscription.Shutdown(context.Background())
+	}()
+
+	// the only connection of the database is held from time to time, so the workers pile up waiting for it
+	consumed := make(chan struct{})
+	defer close(consumed)
+	go func() {
+		for {
+			select {
+			case <-consumed:
+				return
+			case <-time.After(5 * time.Millisecond):
+			}
+			conn, err := sys.R.Database.Conn(context.Background())
+			if err != nil {
+				return
+			}
+			time.Sleep(5 * time.Millisecond)
+			_ = conn.Close()
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := notes.Consume(ctx, subscription, maxWorkers); err != nil {
+		t.Fatalf("Test testAdaptive: should have consumed the messages: %s", err)
+	}
+
+	for range lines {
+		nt.receiveEvent(t, "note.created", "")
+	}
+}
+
+// testPause consumes with a database that does not answer, so receiving stays paused
+func (nt *NoteTests) testPause(t *testing.T) {
+	unhealthy, err := sql.Open("sqlite3", "file:"+filepath.Join(t.TempDir(), "missing", "notes.db")+"?mode=ro")
+	if err != nil {
+		t.Fatalf("Test testPause: failed to open the database: %s", err)
+	}
+	healthy := sys.R.Database
+	sys.R.Database = unhealthy
+	sys.Configs.Messaging.HealthInterval = 10 * time.Millisecond
+	defer func() {
+		sys.R.Database = healthy
+		sys.Configs.Messaging.HealthInterval = 0
+		_ = unhealthy.Close()
+	}()
+
+	path := filepath.Join(t.TempDir(), "paused.jsonl")
+	if err := os.WriteFile(path, []byte(`{"type":"create","data":{"title":"paused","text":"paused text"}}`), 0o600); err != nil {
+		t.Fatalf("Test testPause: failed to write the messages: %s", err)
+	}
+	subscription, err := pubsub.OpenSubscription(context.Background(), "file://"+path)
+	if err != nil {
+		t.Fatalf("Test testPause: failed to open the file subscription: %s", err)
+	}
+	defer func() {
+		_ = subscription.Shutdown(context.Background())
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- notes.Consume(ctx, subscription, 1)
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for !notes.CurrentStatus().Paused && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !notes.CurrentStatus().Paused {
+		t.Fatal("Test testPause: should have paused receiving")
+	}
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Test testPause: should have stopped consuming: %s", err)
+	}
+
+	sys.R.Database = healthy
+	var count int
+	if err := sys.R.Database.QueryRow("SELECT COUNT(*) FROM notes WHERE title = 'paused'").Scan(&count); err != nil {
+		t.Fatalf("Test testPause: failed to count the notes: %s", err)
+	}
+	if count != 0 {
+		t.Fatalf("Test testPause: should not have received messages while paused: %v", count)
+	}
+}
@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"github.com/ribgsilva/note-api/app/messaging/consumers/v1/notes"
+	"github.com/ribgsilva/note-api/sys"
+	"gocloud.dev/pubsub"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func (nt *NoteTests) testBatch(t *testing.T) {
+	sys.Configs.Database.Dialect = "sqlite3"
+	sys.Configs.Messaging.BatchSize = 5
+	sys.Configs.Messaging.BatchWait = time.Second
+	defer func() {
+		sys.Configs.Database.Dialect = ""
+		sys.Configs.Messaging.BatchSize = 0
+		sys.Configs.Messaging.BatchWait = 0
+	}()
+	batched := expvar.Get("messages_batched").(*expvar.Int).Value()
+
+	// the first 5 creates fill a batch. The other batch fails for the missing notebook,
+	// so its messages are created one by one, but the one of the missing notebook
+	var lines []string
+	for i := 1; i <= 9; i++ {
+		notebook := 0
+		if i == 8 {
+			notebook = 9999
+		}
+		lines = append(lines, fmt.Sprintf(`{"body":{"type":"create","data":{"title":"batch %d","text":"batch text","tags":["batch"],"notebookId":%d}},"metadata":{"user":"bia"}}`, i, notebook))
+	}
+	path := filepath.Join(t.TempDir(), "batch.jsonl")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o600); err != nil {
+		t.Fatalf("Test testBatch: failed to write the messages: %s", err)
+	}
+
+	subscription, err := pubsub.OpenSubscription(context.Background(), "file://"+path)
+	if err != nil {
+		t.Fatalf("Test testBatch: failed to open the file subscription: %s", err)
+	}
+	defer func() {
+		_ = subscription.Shutdown(context.Background())
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := notes.Consume(ctx, subscription, 1); err != nil {
+		t.Fatalf("Test testBatch: should have consumed the messages: %s", err)
+	}
+
+	var count int
+	if err := sys.R.Database.QueryRow("SELECT COUNT(*) FROM notes WHERE title LIKE 'batch %'").Scan(&count); err != nil {
+		t.Fatalf("Test testBatch: failed to count the created notes: %s", err)
+	}
+	if count != 8 {
+		t.Fatalf("Test testBatch: should have created the 8 notes with a notebook: %v", count)
+	}
+	if got := expvar.Get("messages_batched").(*expvar.Int).Value() - batched; got != 5 {
+		t.Fatalf("Test testBatch: should have inserted the first 5 notes in a batch: %v", got)
+	}
+
+	// every note got its own revision and tags
+	if err := sys.R.Database.QueryRow(`SELECT COUNT(*) FROM notes n
+		JOIN revisions r ON r.noteId = n.id AND r.revision = 1 AND r.title = n.title AND r.author = 'bia'
+		JOIN note_tags nt ON nt.noteId = n.id JOIN tags t ON t.id = nt.tagId AND t.name = 'batch'
+		WHERE n.title LIKE 'batch %'`).Scan(&count); err != nil {
+		t.Fatalf("Test testBatch: failed to count the revisions: %s", err)
+	}
+	if count != 8 {
+		t.Fatalf("Test testBatch: should have created the revisions and tags of the 8 notes: %v", count)
+	}
+
+	// the created notes were published
+	published := map[string]bool{}
+	for i := 0; i < 8; i++ {
+		if e := nt.receiveEvent(t, "note.created", "bia"); e.Data.After != nil {
+			published[e.Data.After.Title] = true
+		}
+	}
+	if len(published) != 8 || published["batch 8"] {
+		t.Fatalf("Test testBatch: should have published the 8 created notes: %v", published)
+	}
+}
@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/platform/cloudevents"
+	"github.com/ribgsilva/note-api/sys"
+	"gocloud.dev/pubsub"
+	"testing"
+	"time"
+)
+
+func (nt *NoteTests) testCloudEvents(t *testing.T) {
+	sys.Configs.Events.Format = cloudevents.Binary
+	sys.Configs.Events.Source = "/tests"
+	defer func() {
+		sys.Configs.Events.Format = ""
+		sys.Configs.Events.Source = ""
+	}()
+
+	data, _ := json.Marshal(note.NewNote{Title: "cloud", Text: "cloud text"})
+	structured, _ := json.Marshal(cloudevents.Event{
+		Id:          "1",
+		Source:      "/producer",
+		SpecVersion: cloudevents.SpecVersion,
+		Type:        "create",
+		Data:        data,
+	})
+	nt.send(t, structured, map[string]string{"content-type": cloudevents.ContentType})
+
+	var id uint64
+	if err := sys.R.Database.QueryRow("SELECT id FROM notes WHERE title = 'cloud'").Scan(&id); err != nil {
+		t.Fatalf("Test testCloudEvents: should have created the note of the structured event: %s", err)
+	}
+
+	created := nt.receiveCloudEvent(t, note.Created)
+	if created.Subject != fmt.Sprint(id) || created.Source != "/tests" {
+		t.Fatalf("Test testCloudEvents: should have published the created note %d from /tests: %+v", id, created)
+	}
+
+	binary, _ := json.Marshal(note.UpdateNote{Id: id, Title: "cloud", Text: "binary text", ExpectedVersion: 1})
+	nt.send(t, binary, map[string]string{
+		"ce-id":          "2",
+		"ce-source":      "/producer",
+		"ce-specversion": cloudevents.SpecVersion,
+		"ce-type":        "update",
+		"content-type":   cloudevents.JSONContentType,
+	})
+
+	updated := nt.receiveCloudEvent(t, note.Updated)
+	var change note.Change
+	if err := json.Unmarshal(updated.Data, &change); err != nil {
+		t.Fatalf("Test testCloudEvents: failed to parse the published change: %s", err)
+	}
+	if change.Before == nil || change.Before.Text != "cloud text" || change.After == nil || change.After.Text != "binary text" {
+		t.Fatalf("Test testCloudEvents: should have published the note before and after the binary update: %+v", change)
+	}
+
+	unsupported, _ := json.Marshal(note.UpdateNote{Id: id, Title: "cloud", Text: "unsupported text"})
+	nt.send(t, unsupported, map[string]string{
+		"ce-id":          "3",
+		"ce-source":      "/producer",
+		"ce-specversion": "0.3",
+		"ce-type":        "update",
+	})
+
+	var text string
+	if err := sys.R.Database.QueryRow("SELECT notes FROM notes WHERE id = ?", id).Scan(&text); err != nil {
+		t.Fatalf("Test testCloudEvents: failed to get the note: %s", err)
+	}
+	if text != "binary text" {
+		t.Fatalf("Test testCloudEvents: should have ignored the event of an unsupported spec version: %v", text)
+	}
+}
+
+func (nt *NoteTests) send(t *testing.T, body []byte, metadata map[string]string) {
+	if err := nt.topic.Send(context.Background(), &pubsub.Message{Body: body, Metadata: metadata}); err != nil {
+		t.Fatal("Test send: failed to post message to topic: ", err)
+	}
+
+	time.Sleep(time.Second * 1)
+}
+
+func (nt *NoteTests) receiveCloudEvent(t *testing.T, eventType string) cloudevents.Event {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	m, err := nt.events.Receive(ctx)
+	if err != nil {
+		t.Fatalf("Test receiveCloudEvent: should have received a %s event: %s", eventType, err)
+	}
+	m.Ack()
+
+	e, err := cloudevents.Decode(m.Body, m.Metadata)
+	if err != nil {
+		t.Fatalf("Test receiveCloudEvent: failed to decode the event: %s", err)
+	}
+	if e.Type != eventType || m.Metadata["type"] != eventType {
+		t.Fatalf("Test receiveCloudEvent: should have received a %s event: %s", eventType, e.Type)
+	}
+	return e
+}
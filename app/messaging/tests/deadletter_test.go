@@ -0,0 +1,74 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ribgsilva/note-api/platform/dispatch"
+	"github.com/ribgsilva/note-api/sys"
+	"gocloud.dev/pubsub"
+	"gocloud.dev/pubsub/mempubsub"
+	"strings"
+	"testing"
+	"time"
+)
+
+func (nt *NoteTests) testDeadLetter(t *testing.T) {
+	topic := mempubsub.NewTopic()
+	subscription := mempubsub.NewSubscription(topic, time.Second)
+	sys.R.DeadLetter = topic
+	defer func() {
+		sys.R.DeadLetter = nil
+		_ = subscription.Shutdown(context.Background())
+		_ = topic.Shutdown(context.Background())
+	}()
+
+	nt.send(t, []byte(`{"type":"create","data":{"title":"","text":"no title"}}`), map[string]string{"user": "bob"})
+	letter := receiveDeadLetter(t, subscription)
+	if letter.Reason != "invalid" || len(letter.Report) != 1 || !strings.HasPrefix(letter.Report[0], "/title") {
+		t.Fatalf("Test testDeadLetter: should have reported the empty title: %+v", letter)
+	}
+	if letter.Metadata["user"] != "bob" || !strings.Contains(letter.Body, "no title") {
+		t.Fatalf("Test testDeadLetter: should have kept the original message: %+v", letter)
+	}
+
+	nt.send(t, []byte(`{"type":"delete","data":{"id":1}}`), nil)
+	if letter := receiveDeadLetter(t, subscription); letter.Reason != "invalid" || letter.Report[0] != "no schema for delete version 1" {
+		t.Fatalf("Test testDeadLetter: should have reported the unknown type: %+v", letter)
+	}
+
+	nt.send(t, []byte(`not json`), nil)
+	if letter := receiveDeadLetter(t, subscription); letter.Reason != "undecodable" {
+		t.Fatalf("Test testDeadLetter: should have reported the undecodable message: %+v", letter)
+	}
+
+	// failures of the handler that would repeat on every attempt are dead-lettered instead of nacked
+	nt.send(t, []byte(`{"type":"update","data":{"id":999999,"title":"missing","text":"missing","expectedVersion":1}}`), nil)
+	if letter := receiveDeadLetter(t, subscription); letter.Reason != "unhandled" || len(letter.Report) != 1 || letter.Report[0] != "note not found" {
+		t.Fatalf("Test testDeadLetter: should have reported the missing note: %+v", letter)
+	}
+
+	var count int
+	if err := sys.R.Database.QueryRow("SELECT COUNT(*) FROM notes WHERE notes = 'no title'").Scan(&count); err != nil {
+		t.Fatalf("Test testDeadLetter: failed to count notes: %s", err)
+	}
+	if count != 0 {
+		t.Fatalf("Test testDeadLetter: should not have created the invalid note: %v", count)
+	}
+}
+
+func receiveDeadLetter(t *testing.T, subscription *pubsub.Subscription) dispatch.DeadLetter {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	m, err := subscription.Receive(ctx)
+	if err != nil {
+		t.Fatalf("Test receiveDeadLetter: should have received a dead letter: %s", err)
+	}
+	m.Ack()
+
+	var letter dispatch.DeadLetter
+	if err := json.Unmarshal(m.Body, &letter); err != nil {
+		t.Fatalf("Test receiveDeadLetter: failed to parse the dead letter: %s", err)
+	}
+	return letter
+}
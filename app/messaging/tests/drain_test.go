@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"github.com/ribgsilva/note-api/app/messaging/consumers/v1/notes"
+	"github.com/ribgsilva/note-api/sys"
+	"gocloud.dev/pubsub"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func (nt *NoteTests) testDrain(t *testing.T) {
+	sys.Configs.Messaging.ShutdownTimeout = 5 * time.Second
+	defer func() {
+		sys.Configs.Messaging.ShutdownTimeout = 0
+	}()
+	drained := expvarInt("messages_drained")
+	abandoned := expvarInt("messages_abandoned")
+	handled := expvarMapInt("messages_handled", "create")
+	failed := expvarMapInt("messages_failed", "create")
+
+	var lines []string
+	for i := 0; i < 200; i++ {
+		lines = append(lines, fmt.Sprintf(`{"type":"create","data":{"title":"drain %d","text":"drain text"}}`, i))
+	}
+	path := filepath.Join(t.TempDir(), "drain.jsonl")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o600); err != nil {
+		t.Fatalf("Test testDrain: failed to write the messages: %s", err)
+	}
+
+	subscription, err := pubsub.OpenSubscription(context.Background(), "file://"+path)
+	if err != nil {
+		t.Fatalf("Test testDrain: failed to open the file subscription: %s", err)
+	}
+	defer func() {
+		_ = subscription.Shutdown(context.Background())
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- notes.Consume(ctx, subscription, 1)
+	}()
+
+	// stops while the messages are being handled, holding the only connection of the database
+	// so the worker is still busy and has messages waiting when receiving stops
+	for expvarMapInt("messages_handled", "create") == handled {
+		time.Sleep(time.Millisecond)
+	}
+	conn, err := sys.R.Database.Conn(context.Background())
+	if err != nil {
+		t.Fatalf("Test testDrain: failed to hold the database connection: %s", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	_ = conn.Close()
+	if err := <-done; err != nil {
+		t.Fatalf("Test testDrain: should have stopped consuming: %s", err)
+	}
+
+	var count int
+	if err := sys.R.Database.QueryRow("SELECT COUNT(*) FROM notes WHERE title LIKE 'drain %'").Scan(&count); err != nil {
+		t.Fatalf("Test testDrain: failed to count the created notes: %s", err)
+	}
+	if count == len(lines) {
+		t.Fatalf("Test testDrain: should have stopped receiving before the end of the file: %v", count)
+	}
+	if got := expvarMapInt("messages_failed", "create") - failed; got != 0 {
+		t.Fatalf("Test testDrain: should not have cancelled the messages in flight: %v", got)
+	}
+	if got := expvarMapInt("messages_handled", "create") - handled; got != int64(count) {
+		t.Fatalf("Test testDrain: should have created a note for every message handled: %v %v", got, count)
+	}
+	if got := expvarInt("messages_drained") - drained; got < 1 {
+		t.Fatalf("Test testDrain: should have drained the messages received: %v", got)
+	}
+	if got := expvarInt("messages_abandoned") - abandoned; got != 0 {
+		t.Fatalf("Test testDrain: should not have abandoned messages: %v", got)
+	}
+
+	// the created notes were published
+	for i := 0; i < count; i++ {
+		nt.receiveEvent(t, "note.created", "")
+	}
+}
+
+func expvarInt(name string) int64 {
+	return expvar.Get(name).(*expvar.Int).Value()
+}
+
+func expvarMapInt(name, key string) int64 {
+	if v, ok := expvar.Get(name).(*expvar.Map).Get(key).(*expvar.Int); ok {
+		return v.Value()
+	}
+	return 0
+}
@@ -0,0 +1,64 @@
+package tests
+
+import (
+	"context"
+	"github.com/ribgsilva/note-api/app/messaging/consumers/v1/notes"
+	_ "github.com/ribgsilva/note-api/platform/filepubsub"
+	"github.com/ribgsilva/note-api/sys"
+	"gocloud.dev/pubsub"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func (nt *NoteTests) testFileSubscription(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "messages.jsonl")
+	lines := `{"type":"create","data":{"title":"from file","text":"file text"}}
+
+{"body":{"type":"create","data":{"title":"captured","text":"captured text"}},"metadata":{"user":"ana"}}
+{"body":"{\"type\":\"create\",\"data\":{\"title\":\"captured text body\",\"text\":\"text body\"}}"}
+`
+	if err := os.WriteFile(path, []byte(lines), 0o600); err != nil {
+		t.Fatalf("Test testFileSubscription: failed to write the messages: %s", err)
+	}
+
+	subscription, err := pubsub.OpenSubscription(context.Background(), "file://"+path)
+	if err != nil {
+		t.Fatalf("Test testFileSubscription: failed to open the file subscription: %s", err)
+	}
+	defer func() {
+		_ = subscription.Shutdown(context.Background())
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := notes.Consume(ctx, subscription, 2); err != nil {
+		t.Fatalf("Test testFileSubscription: should have consumed the file until its end: %s", err)
+	}
+
+	var count int
+	if err := sys.R.Database.QueryRow("SELECT COUNT(*) FROM notes WHERE title IN ('from file', 'captured', 'captured text body')").Scan(&count); err != nil {
+		t.Fatalf("Test testFileSubscription: failed to count the created notes: %s", err)
+	}
+	if count != 3 {
+		t.Fatalf("Test testFileSubscription: should have created the 3 notes of the file: %v", count)
+	}
+
+	var author string
+	if err := sys.R.Database.QueryRow("SELECT r.author FROM revisions r JOIN notes n ON n.id = r.noteId WHERE n.title = 'captured'").Scan(&author); err != nil {
+		t.Fatalf("Test testFileSubscription: failed to get the captured note revision: %s", err)
+	}
+	if author != "ana" {
+		t.Fatalf("Test testFileSubscription: should have kept the metadata of the captured message: %v", author)
+	}
+
+	// the created notes were published
+	for i := 0; i < 3; i++ {
+		m, err := nt.events.Receive(ctx)
+		if err != nil {
+			t.Fatalf("Test testFileSubscription: should have published the created note: %s", err)
+		}
+		m.Ack()
+	}
+}
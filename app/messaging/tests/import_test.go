@@ -0,0 +1,44 @@
+package tests
+
+import (
+	"context"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/sys"
+	"testing"
+	"time"
+)
+
+func (nt *NoteTests) testImport(t *testing.T) {
+	imported := note.NewNote{Title: "imported", Text: "imported text", Tags: []string{"Import"}}
+	files := []note.ImportFile{
+		{Name: "imported.md", Note: imported},
+		{Name: "copy/imported.md", Note: imported},
+		{Name: "invalid.md", Error: "title is required"},
+	}
+	results := note.QueueImport(context.Background(), nt.topic, files, "ann")
+	if results[0].Status != note.ImportQueued || results[1].Status != note.ImportQueued || results[2].Status != note.ImportFailed {
+		t.Fatalf("Test testImport: should have queued the valid notes: %+v", results)
+	}
+
+	time.Sleep(time.Second * 1)
+
+	// the notes with the same content share the ordering key, so only the first one handled is created
+	var count int
+	if err := sys.R.Database.QueryRow("SELECT COUNT(*) FROM notes WHERE title = 'imported'").Scan(&count); err != nil {
+		t.Fatalf("Test testImport: failed to count the imported notes: %s", err)
+	}
+	if count != 1 {
+		t.Fatalf("Test testImport: should have created the imported note once: %v", count)
+	}
+
+	var id uint64
+	var author string
+	if err := sys.R.Database.QueryRow(`SELECT i.noteId, r.author FROM note_imports i JOIN revisions r ON r.noteId = i.noteId AND r.revision = 1
+		WHERE i.hash = ?`, note.ContentHash(imported, "ann")).Scan(&id, &author); err != nil {
+		t.Fatalf("Test testImport: should have recorded the imported hash: %s", err)
+	}
+	found, err := note.Find(context.Background(), id)
+	if err != nil || found.Title != "imported" || len(found.Tags) != 1 || found.Tags[0] != "import" || author != "ann" {
+		t.Fatalf("Test testImport: should have imported the note from ann: %+v %q %v", found, author, err)
+	}
+}
@@ -22,7 +22,8 @@ import (
 )
 
 type NoteTests struct {
-	topic *pubsub.Topic
+	topic  *pubsub.Topic
+	events *pubsub.Subscription
 }
 
 func TestNote(t *testing.T) {
@@ -68,6 +69,8 @@ func TestNote(t *testing.T) {
 		if err := database.PingContext(dbCtx); err != nil {
 			return fmt.Errorf("could not connect to database: %w", err)
 		}
+		// every connection to :memory: opens another database, so the workers share a single one
+		database.SetMaxOpenConns(1)
 		db = database
 		return nil
 	}(); err != nil {
@@ -110,9 +113,56 @@ func TestNote(t *testing.T) {
 			id INTEGER PRIMARY KEY,
 			title VARCHAR(100),
 			notes TEXT,
+			notebookId BIGINT NULL,
+			version BIGINT NOT NULL DEFAULT 1,
 			updatedAt DATETIME,
+			createdAt DATETIME,
+			deletedAt DATETIME NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS revisions(
+			id INTEGER PRIMARY KEY,
+			noteId BIGINT NOT NULL,
+			revision BIGINT NOT NULL,
+			title VARCHAR(100),
+			notes TEXT,
+			author VARCHAR(100),
+			createdAt DATETIME,
+			CONSTRAINT revisions_note_revision UNIQUE (noteId, revision)
+		)`,
+		`CREATE TABLE IF NOT EXISTS tags(
+			id INTEGER PRIMARY KEY,
+			name VARCHAR(50) NOT NULL,
+			CONSTRAINT tags_name UNIQUE (name)
+		)`,
+		`CREATE TABLE IF NOT EXISTS note_tags(
+			noteId BIGINT NOT NULL,
+			tagId BIGINT NOT NULL,
+			PRIMARY KEY (noteId, tagId)
+		)`,
+		`CREATE TABLE IF NOT EXISTS notebooks(
+			id INTEGER PRIMARY KEY,
+			parentId BIGINT NULL,
+			name VARCHAR(100) NOT NULL,
+			path VARCHAR(1000) NOT NULL,
+			updatedAt DATETIME,
+			createdAt DATETIME
+		)`,
+		`CREATE TABLE IF NOT EXISTS note_imports(
+			hash CHAR(64) PRIMARY KEY,
+			noteId BIGINT NOT NULL,
 			createdAt DATETIME
 		)`,
+		`CREATE TABLE IF NOT EXISTS outbox(
+			id INTEGER PRIMARY KEY,
+			type VARCHAR(100) NOT NULL,
+			noteId BIGINT NOT NULL,
+			author VARCHAR(100) NOT NULL DEFAULT '',
+			payload TEXT NOT NULL,
+			attempts INT NOT NULL DEFAULT 0,
+			lastError VARCHAR(1000) NULL,
+			createdAt DATETIME,
+			sentAt DATETIME NULL
+		)`,
 	}
 
 	for _, b := range batch {
@@ -131,6 +181,17 @@ func TestNote(t *testing.T) {
 	}()
 	subscription := mempubsub.NewSubscription(topic, 1*time.Second)
 
+	events := mempubsub.NewTopic()
+	defer func() {
+		_ = events.Shutdown(context.Background())
+	}()
+	eventsSubscription := mempubsub.NewSubscription(events, 1*time.Second)
+	defer func() {
+		_ = eventsSubscription.Shutdown(context.Background())
+	}()
+	sys.R.Events = events
+	sys.Configs.Messaging.ReplyURLs = []string{"mem://replies"}
+
 	defer func() {
 		stdCtx, stdCancel := context.WithTimeout(context.Background(), sys.Configs.Messaging.ShutdownTimeout)
 		defer stdCancel()
@@ -150,13 +211,28 @@ func TestNote(t *testing.T) {
 	// =======================================================================================================
 	// Tun tests
 
-	noteTests := NoteTests{topic: topic}
+	noteTests := NoteTests{topic: topic, events: eventsSubscription}
 
 	t.Run("testCrud", noteTests.testCrud)
 }
 
 func (nt *NoteTests) testCrud(t *testing.T) {
 	nt.testInsertSuccess(t)
+	nt.testUpdateSuccess(t)
+	nt.testUpdateVersionMismatch(t)
+	nt.testInsertInvalidTags(t)
+	nt.testPurge(t)
+	nt.testOutbox(t)
+	nt.testCloudEvents(t)
+	nt.testFileSubscription(t)
+	nt.testRegistry(t)
+	nt.testDeadLetter(t)
+	nt.testBatch(t)
+	nt.testDrain(t)
+	nt.testAdaptive(t)
+	nt.testReply(t)
+	nt.testOrdering(t)
+	nt.testImport(t)
 }
 
 func (nt *NoteTests) testInsertSuccess(t *testing.T) {
@@ -165,6 +241,7 @@ func (nt *NoteTests) testInsertSuccess(t *testing.T) {
 		Data: note.NewNote{
 			Title: "other",
 			Text:  "other text",
+			Tags:  []string{"Work", "ideas"},
 		},
 	}
 
@@ -181,13 +258,13 @@ func (nt *NoteTests) testInsertSuccess(t *testing.T) {
 
 	time.Sleep(time.Second * 1)
 
-	row := sys.R.Database.QueryRow("SELECT * FROM notes WHERE id = 1")
+	row := sys.R.Database.QueryRow("SELECT id, title, notes, version, updatedAt, createdAt FROM notes WHERE id = 1")
 	if row.Err() != nil {
 		t.Fatal("Test testInsertSuccess: failed to get inserted message: ", err)
 	}
 
 	var found note.Note
-	if err := row.Scan(&found.Id, &found.Title, &found.Text, &found.UpdatedAt, &found.CreatedAt); err != nil {
+	if err := row.Scan(&found.Id, &found.Title, &found.Text, &found.Version, &found.UpdatedAt, &found.CreatedAt); err != nil {
 		t.Fatalf("error parsing db data: %s", err)
 	}
 
@@ -202,4 +279,189 @@ func (nt *NoteTests) testInsertSuccess(t *testing.T) {
 	if found.Text != "other text" {
 		t.Fatalf("Test testInsertSuccess: should have received \"other text\" as text in the response: %v", found)
 	}
+
+	tagged, err := note.Find(context.Background(), found.Id)
+	if err != nil {
+		t.Fatal("Test testInsertSuccess: failed to find inserted note: ", err)
+	}
+	if fmt.Sprint(tagged.Tags) != "[ideas work]" {
+		t.Fatalf("Test testInsertSuccess: should have received [ideas work] as tags: %v", tagged.Tags)
+	}
+
+	created := nt.receiveEvent(t, note.Created, "")
+	if created.Data.Before != nil || created.Data.After == nil || created.Data.After.Id != found.Id {
+		t.Fatalf("Test testInsertSuccess: should have published the created note: %+v", created.Data)
+	}
+}
+
+func (nt *NoteTests) testUpdateSuccess(t *testing.T) {
+	event := note.Event{
+		Type: "update",
+		Data: note.UpdateNote{
+			Id:              1,
+			Title:           "other",
+			Text:            "updated text",
+			ExpectedVersion: 1,
+		},
+	}
+
+	marshal, err := json.Marshal(event)
+	if err != nil {
+		t.Fatal("Test testUpdateSuccess: failed to parse update request body")
+	}
+
+	if err := nt.topic.Send(context.Background(), &pubsub.Message{
+		Body:     marshal,
+		Metadata: map[string]string{"user": "john"},
+	}); err != nil {
+		t.Fatal("Test testUpdateSuccess: failed to post message to topic: ", err)
+	}
+
+	time.Sleep(time.Second * 1)
+
+	var text, author string
+	row := sys.R.Database.QueryRow("SELECT notes, author FROM revisions WHERE noteId = 1 AND revision = 2")
+	if err := row.Scan(&text, &author); err != nil {
+		t.Fatalf("Test testUpdateSuccess: failed to get the revision of the update: %s", err)
+	}
+
+	if text != "updated text" {
+		t.Fatalf("Test testUpdateSuccess: should have received \"updated text\" as text of the revision: %v", text)
+	}
+
+	if author != "john" {
+		t.Fatalf("Test testUpdateSuccess: should have received \"john\" as author of the revision: %v", author)
+	}
+
+	updated := nt.receiveEvent(t, note.Updated, "john")
+	if updated.Data.Before == nil || updated.Data.Before.Text != "other text" || updated.Data.After == nil || updated.Data.After.Text != "updated text" {
+		t.Fatalf("Test testUpdateSuccess: should have published the note before and after the update: %+v", updated.Data)
+	}
+}
+
+func (nt *NoteTests) testUpdateVersionMismatch(t *testing.T) {
+	event := note.Event{
+		Type: "update",
+		Data: note.UpdateNote{
+			Id:              1,
+			Title:           "other",
+			Text:            "stale text",
+			ExpectedVersion: 1,
+		},
+	}
+
+	marshal, err := json.Marshal(event)
+	if err != nil {
+		t.Fatal("Test testUpdateVersionMismatch: failed to parse update request body")
+	}
+
+	if err := nt.topic.Send(context.Background(), &pubsub.Message{
+		Body: marshal,
+	}); err != nil {
+		t.Fatal("Test testUpdateVersionMismatch: failed to post message to topic: ", err)
+	}
+
+	time.Sleep(time.Second * 1)
+
+	var text string
+	var version uint64
+	row := sys.R.Database.QueryRow("SELECT notes, version FROM notes WHERE id = 1")
+	if err := row.Scan(&text, &version); err != nil {
+		t.Fatalf("Test testUpdateVersionMismatch: failed to get the note: %s", err)
+	}
+
+	if text != "updated text" || version != 2 {
+		t.Fatalf("Test testUpdateVersionMismatch: should have kept \"updated text\" in version 2: %v %v", text, version)
+	}
+}
+
+func (nt *NoteTests) testInsertInvalidTags(t *testing.T) {
+	tags := make([]string, note.MaxTags+1)
+	for i := range tags {
+		tags[i] = fmt.Sprint("tag", i)
+	}
+	event := note.Event{
+		Type: "create",
+		Data: note.NewNote{
+			Title: "too many tags",
+			Text:  "invalid",
+			Tags:  tags,
+		},
+	}
+
+	marshal, err := json.Marshal(event)
+	if err != nil {
+		t.Fatal("Test testInsertInvalidTags: failed to parse insert request body")
+	}
+
+	if err := nt.topic.Send(context.Background(), &pubsub.Message{
+		Body: marshal,
+	}); err != nil {
+		t.Fatal("Test testInsertInvalidTags: failed to post message to topic: ", err)
+	}
+
+	time.Sleep(time.Second * 1)
+
+	var count int
+	row := sys.R.Database.QueryRow("SELECT COUNT(*) FROM notes WHERE title = 'too many tags'")
+	if err := row.Scan(&count); err != nil {
+		t.Fatalf("Test testInsertInvalidTags: failed to count the notes: %s", err)
+	}
+
+	if count != 0 {
+		t.Fatalf("Test testInsertInvalidTags: should not have stored the note with too many tags: %v", count)
+	}
+}
+
+func (nt *NoteTests) testPurge(t *testing.T) {
+	old := time.Now().UTC().Add(-48 * time.Hour)
+	recent := time.Now().UTC()
+	for _, deletedAt := range []time.Time{old, recent} {
+		if _, err := sys.R.Database.Exec("INSERT INTO notes (title, notes, updatedAt, createdAt, deletedAt) VALUES ('trashed', 'trashed text', ?, ?, ?)", deletedAt, deletedAt, deletedAt); err != nil {
+			t.Fatalf("Test testPurge: failed to insert trashed note: %s", err)
+		}
+	}
+
+	purged, err := note.Purge(context.Background(), 24*time.Hour)
+	if err != nil {
+		t.Fatalf("Test testPurge: failed to purge trash: %s", err)
+	}
+	if purged != 1 {
+		t.Fatalf("Test testPurge: should have purged 1 note: %v", purged)
+	}
+
+	var count int
+	if err := sys.R.Database.QueryRow("SELECT COUNT(*) FROM notes WHERE title = 'trashed'").Scan(&count); err != nil {
+		t.Fatalf("Test testPurge: failed to count trashed notes: %s", err)
+	}
+
+	if count != 1 {
+		t.Fatalf("Test testPurge: should have kept only the recently trashed note: %v", count)
+	}
+}
+
+// changeEvent is a note.Event holding the note.Change published on note changes
+type changeEvent struct {
+	Type string      `json:"type"`
+	Data note.Change `json:"data"`
+}
+
+func (nt *NoteTests) receiveEvent(t *testing.T, eventType, author string) changeEvent {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	m, err := nt.events.Receive(ctx)
+	if err != nil {
+		t.Fatalf("Test receiveEvent: should have received a %s event: %s", eventType, err)
+	}
+	m.Ack()
+
+	var e changeEvent
+	if err := json.Unmarshal(m.Body, &e); err != nil {
+		t.Fatalf("Test receiveEvent: failed to parse the event: %s", err)
+	}
+	if e.Type != eventType || m.Metadata["user"] != author {
+		t.Fatalf("Test receiveEvent: should have received a %s event from %q: %s %q", eventType, author, e.Type, m.Metadata["user"])
+	}
+	return e
 }
@@ -0,0 +1,70 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ribgsilva/note-api/app/messaging/consumers/v1/notes"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/sys"
+	"gocloud.dev/pubsub"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func (nt *NoteTests) testOrdering(t *testing.T) {
+	var ids []uint64
+	for _, title := range []string{"ordered a", "ordered b"} {
+		created, err := note.Create(context.Background(), note.NewNote{Title: title, Text: "0"}, "")
+		if err != nil {
+			t.Fatalf("Test testOrdering: failed to create note: %s", err)
+		}
+		ids = append(ids, created.Id)
+	}
+
+	// every update expects the version of the previous one, so any of them processed out of order fails.
+	// The file subscription receives the messages in the order they were written
+	const updates = 20
+	var lines bytes.Buffer
+	for i := 1; i <= updates; i++ {
+		for _, id := range ids {
+			body, _ := json.Marshal(note.Event{
+				Type: "update",
+				Data: note.UpdateNote{Id: id, Title: "ordered", Text: fmt.Sprint(i), ExpectedVersion: uint64(i)},
+			})
+			lines.Write(append(body, '\n'))
+		}
+	}
+	path := filepath.Join(t.TempDir(), "ordered.jsonl")
+	if err := os.WriteFile(path, lines.Bytes(), 0o600); err != nil {
+		t.Fatalf("Test testOrdering: failed to write the messages: %s", err)
+	}
+
+	subscription, err := pubsub.OpenSubscription(context.Background(), "file://"+path)
+	if err != nil {
+		t.Fatalf("Test testOrdering: failed to open the file subscription: %s", err)
+	}
+	defer func() {
+		_ = subscription.Shutdown(context.Background())
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := notes.Consume(ctx, subscription, 4); err != nil {
+		t.Fatalf("Test testOrdering: should have consumed the messages: %s", err)
+	}
+
+	for _, id := range ids {
+		var text string
+		var version uint64
+		if err := sys.R.Database.QueryRow("SELECT notes, version FROM notes WHERE id = ?", id).Scan(&text, &version); err != nil {
+			t.Fatalf("Test testOrdering: failed to get the note: %s", err)
+		}
+		if text != fmt.Sprint(updates) || version != updates+1 {
+			t.Fatalf("Test testOrdering: should have applied the %d updates of note %d in order: %v %v", updates, id, text, version)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/business/v1/outbox"
+	"github.com/ribgsilva/note-api/sys"
+	"gocloud.dev/pubsub"
+	"gocloud.dev/pubsub/mempubsub"
+	"testing"
+	"time"
+)
+
+func (nt *NoteTests) testOutbox(t *testing.T) {
+	sys.Configs.Events.Outbox = true
+	defer func() {
+		sys.Configs.Events.Outbox = false
+	}()
+
+	event := note.Event{
+		Type: "update",
+		Data: note.UpdateNote{
+			Id:              1,
+			Title:           "other",
+			Text:            "outbox text",
+			ExpectedVersion: 2,
+		},
+	}
+
+	marshal, err := json.Marshal(event)
+	if err != nil {
+		t.Fatal("Test testOutbox: failed to parse update request body")
+	}
+
+	if err := nt.topic.Send(context.Background(), &pubsub.Message{
+		Body:     marshal,
+		Metadata: map[string]string{"user": "mary"},
+	}); err != nil {
+		t.Fatal("Test testOutbox: failed to post message to topic: ", err)
+	}
+
+	time.Sleep(time.Second * 1)
+
+	noEventCtx, noEventCancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer noEventCancel()
+	if m, err := nt.events.Receive(noEventCtx); err == nil {
+		m.Ack()
+		t.Fatalf("Test testOutbox: should not have published the change before the relay: %s", m.Body)
+	}
+
+	lag, err := outbox.FindLag(context.Background())
+	if err != nil {
+		t.Fatalf("Test testOutbox: failed to get the outbox lag: %s", err)
+	}
+	if lag.Pending != 1 {
+		t.Fatalf("Test testOutbox: should have 1 pending change: %v", lag)
+	}
+
+	// a topic that can not publish keeps the change pending
+	closed := mempubsub.NewTopic()
+	_ = closed.Shutdown(context.Background())
+	if relayed, err := outbox.Relay(context.Background(), closed, 10); err == nil || relayed != 0 {
+		t.Fatalf("Test testOutbox: should have failed to relay to a closed topic: %v %v", relayed, err)
+	}
+	var attempts int
+	if err := sys.R.Database.QueryRow("SELECT attempts FROM outbox WHERE sentAt IS NULL").Scan(&attempts); err != nil {
+		t.Fatalf("Test testOutbox: failed to get the pending change: %s", err)
+	}
+	if attempts != 1 {
+		t.Fatalf("Test testOutbox: should have recorded the failed attempt: %v", attempts)
+	}
+
+	relayed, err := outbox.Relay(context.Background(), sys.R.Events, 10)
+	if err != nil {
+		t.Fatalf("Test testOutbox: failed to relay the outbox: %s", err)
+	}
+	if relayed != 1 {
+		t.Fatalf("Test testOutbox: should have relayed 1 change: %v", relayed)
+	}
+
+	updated := nt.receiveEvent(t, note.Updated, "mary")
+	if updated.Data.Before == nil || updated.Data.Before.Version != 2 || updated.Data.After == nil || updated.Data.After.Text != "outbox text" {
+		t.Fatalf("Test testOutbox: should have published the note before and after the update: %+v", updated.Data)
+	}
+
+	if relayed, err := outbox.Relay(context.Background(), sys.R.Events, 10); err != nil || relayed != 0 {
+		t.Fatalf("Test testOutbox: should not relay a change twice: %v %v", relayed, err)
+	}
+}
@@ -0,0 +1,44 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/platform/dispatch"
+	"github.com/ribgsilva/note-api/sys"
+	"testing"
+)
+
+func (nt *NoteTests) testRegistry(t *testing.T) {
+	r := dispatch.NewRegistry(true)
+	r.Use(dispatch.Logging(sys.R.Log), dispatch.Metrics(), dispatch.Recover())
+
+	var created note.NewNote
+	dispatch.Register(r, "create", "1", func(_ context.Context, c note.NewNote, _ dispatch.Message) error {
+		created = c
+		return nil
+	})
+	dispatch.Register(r, "create", "2", func(context.Context, note.NewNote, dispatch.Message) error {
+		panic("bad handler")
+	})
+
+	if err := r.Dispatch(context.Background(), dispatch.Message{Type: "create", Data: json.RawMessage(`{"title":"typed","text":"typed text"}`)}); err != nil {
+		t.Fatalf("Test testRegistry: should have handled the message as version 1: %s", err)
+	}
+	if created.Title != "typed" {
+		t.Fatalf("Test testRegistry: should have decoded the payload: %+v", created)
+	}
+
+	if err := r.Dispatch(context.Background(), dispatch.Message{Type: "create", Version: "1", Data: json.RawMessage(`{"title":"typed","unknown":1}`)}); !errors.Is(err, dispatch.ErrDecode) {
+		t.Fatalf("Test testRegistry: should have rejected unknown fields: %v", err)
+	}
+
+	if err := r.Dispatch(context.Background(), dispatch.Message{Type: "create", Version: "2", Data: json.RawMessage(`{}`)}); err == nil {
+		t.Fatal("Test testRegistry: should have recovered the panic as an error")
+	}
+
+	if err := r.Dispatch(context.Background(), dispatch.Message{Type: "delete", Data: json.RawMessage(`{}`)}); !errors.Is(err, dispatch.ErrUnknownType) {
+		t.Fatalf("Test testRegistry: should not have a handler for delete: %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ribgsilva/note-api/platform/dispatch"
+	"github.com/ribgsilva/note-api/sys"
+	"gocloud.dev/pubsub"
+	"strings"
+	"testing"
+	"time"
+)
+
+func (nt *NoteTests) testReply(t *testing.T) {
+	topic, err := pubsub.OpenTopic(context.Background(), "mem://replies")
+	if err != nil {
+		t.Fatalf("Test testReply: failed to open the reply topic: %s", err)
+	}
+	defer func() {
+		_ = topic.Shutdown(context.Background())
+	}()
+	replies, err := pubsub.OpenSubscription(context.Background(), "mem://replies")
+	if err != nil {
+		t.Fatalf("Test testReply: failed to open the reply subscription: %s", err)
+	}
+	defer func() {
+		_ = replies.Shutdown(context.Background())
+	}()
+
+	nt.send(t, []byte(`{"type":"create","data":{"title":"replied","text":"replied text"}}`), map[string]string{
+		dispatch.ReplyToKey:       "mem://replies",
+		dispatch.CorrelationIdKey: "c1",
+	})
+	created := receiveReply(t, replies, "c1")
+	if created.Type != "create" || created.Id == 0 || created.Error != "" {
+		t.Fatalf("Test testReply: should have replied the id of the created note: %+v", created)
+	}
+	var title string
+	if err := sys.R.Database.QueryRow("SELECT title FROM notes WHERE id = ?", created.Id).Scan(&title); err != nil || title != "replied" {
+		t.Fatalf("Test testReply: should have replied the id of the replied note: %v %s", title, err)
+	}
+	nt.receiveEvent(t, "note.created", "")
+
+	nt.send(t, []byte(`{"type":"update","data":{"id":999999,"title":"missing","text":"missing"}}`), map[string]string{
+		dispatch.ReplyToKey:       "mem://replies",
+		dispatch.CorrelationIdKey: "c2",
+	})
+	if missing := receiveReply(t, replies, "c2"); missing.Id != 0 || missing.Error != "note not found" {
+		t.Fatalf("Test testReply: should have replied why the update failed: %+v", missing)
+	}
+
+	nt.send(t, []byte(`{"type":"create","data":{"text":"no title"}}`), map[string]string{
+		dispatch.ReplyToKey:       "mem://replies",
+		dispatch.CorrelationIdKey: "c3",
+	})
+	if invalid := receiveReply(t, replies, "c3"); invalid.Id != 0 || !strings.HasPrefix(invalid.Error, "invalid: ") {
+		t.Fatalf("Test testReply: should have replied why the message is invalid: %+v", invalid)
+	}
+
+	// replies only go to the allowed urls
+	nt.send(t, []byte(`{"type":"create","data":{"title":"not replied","text":"not replied"}}`), map[string]string{
+		dispatch.ReplyToKey:       "mem://other",
+		dispatch.CorrelationIdKey: "c4",
+	})
+	nt.receiveEvent(t, "note.created", "")
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if m, err := replies.Receive(ctx); err == nil {
+		t.Fatalf("Test testReply: should not have replied to a url out of the allowed ones: %s", m.Body)
+	}
+}
+
+func receiveReply(t *testing.T, replies *pubsub.Subscription, correlationId string) dispatch.Reply {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	m, err := replies.Receive(ctx)
+	if err != nil {
+		t.Fatalf("Test receiveReply: should have received the reply %s: %s", correlationId, err)
+	}
+	m.Ack()
+
+	var reply dispatch.Reply
+	if err := json.Unmarshal(m.Body, &reply); err != nil {
+		t.Fatalf("Test receiveReply: failed to parse the reply: %s", err)
+	}
+	if reply.CorrelationId != correlationId || m.Metadata[dispatch.CorrelationIdKey] != correlationId {
+		t.Fatalf("Test receiveReply: should have received the reply %s: %+v", correlationId, reply)
+	}
+	return reply
+}
@@ -0,0 +1,71 @@
+package outbox
+
+import (
+	"context"
+	"expvar"
+	"github.com/ribgsilva/note-api/business/v1/outbox"
+	"github.com/ribgsilva/note-api/sys"
+	"gocloud.dev/pubsub"
+	"time"
+)
+
+// lag metrics, served by the expvar handler
+var (
+	pending    = expvar.NewInt("outbox_pending")
+	lagSeconds = expvar.NewFloat("outbox_lag_seconds")
+	sent       = expvar.NewInt("outbox_sent")
+	failures   = expvar.NewInt("outbox_failures")
+)
+
+// Relay publishes the changes written to the outbox every interval, until the context is done.
+// After a failure it waits twice as long as before to try again, up to maxBackoff. Published changes are removed after retention
+func Relay(ctx context.Context, topic *pubsub.Topic, interval time.Duration, batch int, maxBackoff, retention time.Duration) {
+	logger := sys.R.Log
+
+	backoff := interval
+	for {
+		wait := interval
+		if err := drain(ctx, topic, batch); err != nil {
+			logger.Error("failed to relay outbox: ", err)
+			failures.Add(1)
+			if backoff *= 2; backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+			wait = backoff
+		} else {
+			backoff = interval
+		}
+
+		if lag, err := outbox.FindLag(ctx); err != nil {
+			logger.Error("failed to get outbox lag: ", err)
+		} else {
+			pending.Set(lag.Pending)
+			lagSeconds.Set(lag.Age.Seconds())
+		}
+
+		if purged, err := outbox.Purge(ctx, retention); err != nil {
+			logger.Error("failed to purge outbox: ", err)
+		} else if purged > 0 {
+			logger.Infof("purged %d published changes from outbox", purged)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// drain relays batches until the outbox has no pending changes
+func drain(ctx context.Context, topic *pubsub.Topic, batch int) error {
+	for {
+		relayed, err := outbox.Relay(ctx, topic, batch)
+		sent.Add(int64(relayed))
+		if err != nil || relayed < batch {
+			return err
+		}
+	}
+}
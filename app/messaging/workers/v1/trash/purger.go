@@ -0,0 +1,32 @@
+package trash
+
+import (
+	"context"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/sys"
+	"time"
+)
+
+// Purge permanently removes the notes past the trash retention every interval, until the context is done
+func Purge(ctx context.Context, interval, retention time.Duration) {
+	logger := sys.R.Log
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := note.Purge(ctx, retention)
+		if err != nil {
+			logger.Error("failed to purge trash: ", err)
+		}
+		if purged > 0 {
+			logger.Infof("purged %d notes from trash", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
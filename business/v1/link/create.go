@@ -0,0 +1,70 @@
+package link
+
+import (
+	"context"
+	"fmt"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/persistence/v1/link"
+	"github.com/ribgsilva/note-api/sys"
+	"golang.org/x/crypto/bcrypt"
+	"time"
+)
+
+func Create(ctx context.Context, noteId uint64, newL NewLink) (Link, error) {
+	n, err := note.Find(ctx, noteId)
+	if err != nil {
+		return Link{}, err
+	}
+	if n.Id == 0 {
+		return Link{}, ErrNoteNotFound
+	}
+
+	ttl := sys.Configs.Links.DefaultTTL
+	if newL.ExpiresIn > 0 {
+		ttl = time.Duration(newL.ExpiresIn) * time.Second
+	}
+	if ttl > sys.Configs.Links.MaxTTL {
+		ttl = sys.Configs.Links.MaxTTL
+	}
+
+	id, err := newId()
+	if err != nil {
+		return Link{}, err
+	}
+
+	now := time.Now().UTC()
+	l := link.Link{
+		Id:        id,
+		NoteId:    noteId,
+		MaxViews:  newL.MaxViews,
+		ExpiresAt: now.Add(ttl).Truncate(time.Second),
+		CreatedAt: now,
+	}
+	if newL.Password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(newL.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return Link{}, fmt.Errorf("failed to hash link password: %w", err)
+		}
+		l.PasswordHash = hash
+	}
+
+	if err := link.Insert(ctx, l); err != nil {
+		return Link{}, err
+	}
+
+	created := toLink(l, 0)
+	created.Token = sign(l.Id, l.ExpiresAt)
+	return created, nil
+}
+
+func toLink(l link.Link, views int64) Link {
+	return Link{
+		Id:        l.Id,
+		NoteId:    l.NoteId,
+		Protected: len(l.PasswordHash) > 0,
+		MaxViews:  l.MaxViews,
+		Views:     views,
+		ExpiresAt: l.ExpiresAt,
+		CreatedAt: l.CreatedAt,
+	}
+}
@@ -0,0 +1,29 @@
+package link
+
+import (
+	"context"
+	"github.com/ribgsilva/note-api/persistence/v1/link"
+)
+
+// FindByNote lists the active links of a note, without their tokens
+func FindByNote(ctx context.Context, noteId uint64) ([]Link, error) {
+	found, err := link.FindByNote(ctx, noteId)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(found))
+	for i, l := range found {
+		ids[i] = l.Id
+	}
+	views, err := link.Views(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	links := make([]Link, len(found))
+	for i, l := range found {
+		links[i] = toLink(l, views[l.Id])
+	}
+	return links, nil
+}
@@ -0,0 +1,41 @@
+package link
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrNoteNotFound     = errors.New("note not found")
+	ErrNotFound         = errors.New("link not found")
+	ErrExpired          = errors.New("link expired")
+	ErrExhausted        = errors.New("link has no views left")
+	ErrPasswordRequired = errors.New("link password required")
+	ErrInvalidPassword  = errors.New("invalid link password")
+)
+
+type Link struct {
+	Id        string    `json:"id" example:"3q2-7wAAAAAAAAAAAAAAAA"`
+	NoteId    uint64    `json:"noteId" example:"1"`
+	Token     string    `json:"token,omitempty" example:"3q2-7wAAAAAAAAAAAAAAAA.1136214245.bXktc2lnbmF0dXJl"`
+	Protected bool      `json:"protected" example:"true"`
+	MaxViews  int64     `json:"maxViews" example:"10"`
+	Views     int64     `json:"views" example:"2"`
+	ExpiresAt time.Time `json:"expiresAt" example:"2006-01-02T15:04:05Z"`
+	CreatedAt time.Time `json:"createdAt" example:"2006-01-02T15:04:05Z"`
+}
+
+type NewLink struct {
+	ExpiresIn int64  `json:"expiresIn" example:"3600"`
+	MaxViews  int64  `json:"maxViews" example:"10"`
+	Password  string `json:"password" example:"secret"`
+}
+
+type Shared struct {
+	Title     string    `json:"title" example:"my note"`
+	Text      string    `json:"text" example:"my note text"`
+	UpdatedAt time.Time `json:"updatedAt" example:"2006-01-02T15:04:05Z"`
+	ExpiresAt time.Time `json:"expiresAt" example:"2006-01-02T15:04:05Z"`
+	Views     int64     `json:"views" example:"2"`
+	MaxViews  int64     `json:"maxViews" example:"10"`
+}
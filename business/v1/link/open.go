@@ -0,0 +1,60 @@
+package link
+
+import (
+	"context"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/persistence/v1/link"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Open resolves a shared token into the note it points to, counting a view
+func Open(ctx context.Context, token, password string) (Shared, error) {
+	id, err := verify(token)
+	if err != nil {
+		return Shared{}, err
+	}
+
+	found, err := link.Find(ctx, id)
+	if err != nil {
+		return Shared{}, err
+	}
+	if found.Id == "" {
+		return Shared{}, ErrNotFound
+	}
+
+	if len(found.PasswordHash) > 0 {
+		if password == "" {
+			return Shared{}, ErrPasswordRequired
+		}
+		if err := bcrypt.CompareHashAndPassword(found.PasswordHash, []byte(password)); err != nil {
+			return Shared{}, ErrInvalidPassword
+		}
+	}
+
+	n, err := note.Find(ctx, found.NoteId)
+	if err != nil {
+		return Shared{}, err
+	}
+	if n.Id == 0 {
+		return Shared{}, ErrNotFound
+	}
+
+	views, exhausted, err := link.View(ctx, found)
+	switch {
+	case err != nil:
+		return Shared{}, err
+	case exhausted:
+		return Shared{}, ErrExhausted
+	case views == 0:
+		return Shared{}, ErrNotFound
+	}
+
+	return Shared{
+		Title:     n.Title,
+		Text:      n.Text,
+		UpdatedAt: n.UpdatedAt,
+		ExpiresAt: found.ExpiresAt,
+		Views:     views,
+		MaxViews:  found.MaxViews,
+	}, nil
+}
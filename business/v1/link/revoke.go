@@ -0,0 +1,25 @@
+package link
+
+import (
+	"context"
+	"github.com/ribgsilva/note-api/persistence/v1/link"
+)
+
+func Revoke(ctx context.Context, noteId uint64, id string) error {
+	found, err := link.Find(ctx, id)
+	if err != nil {
+		return err
+	}
+	if found.Id == "" || found.NoteId != noteId {
+		return ErrNotFound
+	}
+
+	deleted, err := link.Delete(ctx, noteId, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package link
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"github.com/ribgsilva/note-api/sys"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func newId() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate link id: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func sign(id string, expiresAt time.Time) string {
+	payload := id + "." + strconv.FormatInt(expiresAt.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(sys.Configs.Links.Secret))
+	mac.Write([]byte(payload))
+	return payload + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verify checks the token signature and expiration, returning the link id it was issued for
+func verify(token string) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", ErrNotFound
+	}
+	exp, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return "", ErrNotFound
+	}
+	expiresAt := time.Unix(exp, 0)
+	if !hmac.Equal([]byte(sign(parts[0], expiresAt)), []byte(token)) {
+		return "", ErrNotFound
+	}
+	if time.Now().After(expiresAt) {
+		return "", ErrExpired
+	}
+	return parts[0], nil
+}
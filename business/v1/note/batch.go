@@ -0,0 +1,79 @@
+package note
+
+import (
+	"context"
+	"errors"
+	"github.com/ribgsilva/note-api/persistence/v1/note"
+)
+
+// Apply runs the operations of a batch in order in a single transaction, returning the notes as left by each one,
+// empty for deletes. Either every operation is applied or none is: the first one failing is returned as an
+// *OperationError, with ErrNotFound when the note it updates or deletes does not exist
+func Apply(ctx context.Context, ops []Operation, author string) ([]Note, error) {
+	changes := make([]note.Operation, len(ops))
+	for i, op := range ops {
+		if op.Note != nil && !ValidTags(op.Note.Tags) {
+			return nil, &OperationError{Index: i, Err: ErrInvalidTags}
+		}
+		switch op.Op {
+		case OpCreate:
+			changes[i].Create = &note.NewNote{
+				Title:      op.Note.Title,
+				Text:       op.Note.Text,
+				Tags:       NormalizeTags(op.Note.Tags),
+				NotebookId: op.Note.NotebookId,
+				Author:     author,
+			}
+		case OpUpdate:
+			changes[i].Update = &note.UpdateNote{
+				Id:      op.Id,
+				Title:   op.Note.Title,
+				Text:    op.Note.Text,
+				Tags:    NormalizeTags(op.Note.Tags),
+				Author:  author,
+				Version: op.ExpectedVersion,
+			}
+		case OpDelete:
+			changes[i].Delete = &note.DeleteNote{
+				Id:      op.Id,
+				Version: op.ExpectedVersion,
+				Author:  author,
+			}
+		}
+	}
+
+	applied, err := note.Apply(ctx, changes)
+	var opErr *note.OperationError
+	if errors.As(err, &opErr) {
+		switch {
+		case errors.Is(err, note.ErrNotFound):
+			return nil, &OperationError{Index: opErr.Index, Err: ErrNotFound}
+		case errors.Is(err, note.ErrVersionMismatch):
+			return nil, &OperationError{Index: opErr.Index, Err: ErrVersionMismatch}
+		case errors.Is(err, note.ErrNotebookNotFound):
+			return nil, &OperationError{Index: opErr.Index, Err: ErrNotebookNotFound}
+		}
+		return nil, &OperationError{Index: opErr.Index, Err: opErr.Err}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	notes := make([]Note, len(ops))
+	for i, a := range applied {
+		before, after := Note(a.Before), Note(a.After)
+		switch ops[i].Op {
+		case OpCreate:
+			index(after)
+			publish(ctx, Created, Note{}, after, author)
+		case OpUpdate:
+			index(after)
+			publish(ctx, Updated, before, after, author)
+		case OpDelete:
+			unindex(before.Id)
+			publish(ctx, Deleted, before, Note{}, author)
+		}
+		notes[i] = after
+	}
+	return notes, nil
+}
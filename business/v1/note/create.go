@@ -2,9 +2,68 @@ package note
 
 import (
 	"context"
+	"errors"
 	"github.com/ribgsilva/note-api/persistence/v1/note"
 )
 
-func Create(ctx context.Context, newN NewNote) error {
-	return note.Insert(ctx, note.NewNote(newN))
+// Create inserts a note, returning it as persisted, ErrNotebookNotFound if its notebook does not exist,
+// or ErrInvalidTags if its tags are out of the limits
+func Create(ctx context.Context, newN NewNote, author string) (Note, error) {
+	if !ValidTags(newN.Tags) {
+		return Note{}, ErrInvalidTags
+	}
+	inserted, err := note.Insert(ctx, note.NewNote{
+		Title:      newN.Title,
+		Text:       newN.Text,
+		Tags:       NormalizeTags(newN.Tags),
+		NotebookId: newN.NotebookId,
+		Author:     author,
+	})
+	switch {
+	case errors.Is(err, note.ErrNotebookNotFound):
+		return Note{}, ErrNotebookNotFound
+	case err != nil:
+		return Note{}, err
+	}
+
+	created := Note(inserted)
+	index(created)
+	publish(ctx, Created, Note{}, created, author)
+	return created, nil
+}
+
+// CreateMany inserts notes in a single transaction, authors[i] being the author of newNs[i]. Either every note is
+// created or none is, and ErrNotebookNotFound is returned if the notebook of any of them does not exist,
+// or ErrInvalidTags if the tags of any of them are out of the limits.
+// It returns the notes as persisted, in the same order
+func CreateMany(ctx context.Context, newNs []NewNote, authors []string) ([]Note, error) {
+	news := make([]note.NewNote, len(newNs))
+	for i, newN := range newNs {
+		if !ValidTags(newN.Tags) {
+			return nil, ErrInvalidTags
+		}
+		news[i] = note.NewNote{
+			Title:      newN.Title,
+			Text:       newN.Text,
+			Tags:       NormalizeTags(newN.Tags),
+			NotebookId: newN.NotebookId,
+			Author:     authors[i],
+		}
+	}
+
+	inserted, err := note.InsertMany(ctx, news)
+	switch {
+	case errors.Is(err, note.ErrNotebookNotFound):
+		return nil, ErrNotebookNotFound
+	case err != nil:
+		return nil, err
+	}
+
+	created := make([]Note, len(inserted))
+	for i, n := range inserted {
+		created[i] = Note(n)
+		index(created[i])
+		publish(ctx, Created, Note{}, created[i], authors[i])
+	}
+	return created, nil
 }
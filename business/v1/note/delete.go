@@ -0,0 +1,31 @@
+package note
+
+import (
+	"context"
+	"errors"
+	"github.com/ribgsilva/note-api/persistence/v1/note"
+)
+
+// Delete moves a note to the trash, returning false if it does not exist
+func Delete(ctx context.Context, id, expectedVersion uint64, author string) (bool, error) {
+	var before Note
+	if direct() {
+		var err error
+		if before, err = Find(ctx, id); err != nil {
+			return false, err
+		}
+	}
+
+	deleted, err := note.Delete(ctx, id, expectedVersion, author)
+	switch {
+	case errors.Is(err, note.ErrVersionMismatch):
+		return false, ErrVersionMismatch
+	case err != nil:
+		return false, err
+	}
+	if deleted {
+		unindex(id)
+		publish(ctx, Deleted, before, Note{}, author)
+	}
+	return deleted, nil
+}
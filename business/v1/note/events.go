@@ -0,0 +1,120 @@
+package note
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ribgsilva/note-api/platform/cloudevents"
+	"github.com/ribgsilva/note-api/sys"
+	"gocloud.dev/pubsub"
+	"strconv"
+	"time"
+)
+
+// authorMetadata is the message metadata holding the user that made the change
+const authorMetadata = "user"
+
+// LegacyFormat publishes events in the {type, data} envelope, the other formats are the CloudEvents modes
+const LegacyFormat = "legacy"
+
+// ValidFormat tells if events can be published in a format
+func ValidFormat(format string) bool {
+	return format == LegacyFormat || format == cloudevents.Structured || format == cloudevents.Binary
+}
+
+// NewMessage builds the message of a note change in the configured events format. As CloudEvents, id and at identify
+// the change and when it happened; a random id and the current time are used when they are empty
+func NewMessage(eventType string, noteId uint64, data any, author, id string, at time.Time) (*pubsub.Message, error) {
+	var body []byte
+	metadata := map[string]string{}
+
+	if format := sys.Configs.Events.Format; format == LegacyFormat || format == "" {
+		var err error
+		if body, err = json.Marshal(Event{Type: eventType, Data: data}); err != nil {
+			return nil, fmt.Errorf("failed to parse %s event: %w", eventType, err)
+		}
+	} else {
+		e, err := cloudevents.New(sys.Configs.Events.Source, eventType, strconv.FormatUint(noteId, 10), data)
+		if err != nil {
+			return nil, err
+		}
+		if id != "" {
+			e.Id = id
+		}
+		if !at.IsZero() {
+			e.Time = &at
+		}
+		if body, metadata, err = cloudevents.Encode(e, format); err != nil {
+			return nil, err
+		}
+	}
+
+	metadata["type"] = eventType
+	if author != "" {
+		metadata[authorMetadata] = author
+	}
+	return &pubsub.Message{Body: body, Metadata: metadata}, nil
+}
+
+// direct tells if note changes are published right after being done. With the outbox they are written
+// along with the change instead, and published by the relay of the messaging app
+func direct() bool {
+	return sys.R.Events != nil && !sys.Configs.Events.Outbox
+}
+
+// publish sends a note change to the events topic, when publishing directly. Failures are only logged,
+// as the change is already done
+func publish(ctx context.Context, eventType string, before, after Note, author string) {
+	if !direct() {
+		return
+	}
+
+	var change Change
+	if before.Id != 0 {
+		change.Before = &before
+	}
+	if after.Id != 0 {
+		change.After = &after
+	}
+	noteId := after.Id
+	if noteId == 0 {
+		noteId = before.Id
+	}
+	m, err := NewMessage(eventType, noteId, change, author, "", time.Time{})
+	if err != nil {
+		sys.R.Log.Error(err)
+		return
+	}
+	if err := sys.R.Events.Send(ctx, m); err != nil {
+		sys.R.Log.Errorf("failed to publish %s event: %s", eventType, err)
+	}
+}
+
+// Trashed takes the notes moved to the trash along with their notebook out of the search index and publishes
+// the deletion of the ones in before, their state before being trashed
+func Trashed(ctx context.Context, ids []uint64, before []Note, author string) {
+	unindex(ids...)
+	for _, n := range before {
+		publish(ctx, Deleted, n, Note{}, author)
+	}
+}
+
+// InNotebook lists all notes of a notebook and its descendants, as the ones a cascade delete trashes, so their
+// deletion can be published. It returns no notes when changes are not published directly
+func InNotebook(ctx context.Context, path string) ([]Note, error) {
+	if !direct() {
+		return nil, nil
+	}
+
+	var all []Note
+	for offset := 0; ; offset += reindexBatch {
+		found, err := List(ctx, Filter{NotebookPath: path, Recursive: true, Limit: reindexBatch, Offset: offset})
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, found...)
+		if len(found) < reindexBatch {
+			return all, nil
+		}
+	}
+}
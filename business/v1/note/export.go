@@ -0,0 +1,263 @@
+package note
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/ribgsilva/note-api/persistence/v1/note"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Formats the notes are exported in
+const (
+	ExportJSON     = "json"
+	ExportNDJSON   = "ndjson"
+	ExportCSV      = "csv"
+	ExportMarkdown = "markdown"
+)
+
+// exportPage is how many notes are read from the database at a time
+const exportPage = 500
+
+// ExportFormat is how an export format is served
+type ExportFormat struct {
+	ContentType string
+	Extension   string
+}
+
+var exportFormats = map[string]ExportFormat{
+	ExportJSON:     {ContentType: "application/json", Extension: "json"},
+	ExportNDJSON:   {ContentType: "application/x-ndjson", Extension: "ndjson"},
+	ExportCSV:      {ContentType: "text/csv", Extension: "csv"},
+	ExportMarkdown: {ContentType: "application/zip", Extension: "zip"},
+}
+
+// LookupExport returns how an export format is served, and false if it is not known
+func LookupExport(format string) (ExportFormat, bool) {
+	f, ok := exportFormats[format]
+	return f, ok
+}
+
+// exporter writes the notes of an export one at a time, finishing the output on close
+type exporter interface {
+	write(n Note) error
+	close() error
+}
+
+// Export writes the notes out of the trash created by author to w in the order they were created, or ErrExportAuthor
+// without one. The notes are read a page at a time, so exports of any size are written as they are read
+func Export(ctx context.Context, w io.Writer, format, author string) error {
+	if author == "" {
+		return ErrExportAuthor
+	}
+	return export(ctx, w, format, note.ExportFilter{Author: author})
+}
+
+// ExportAnonymous writes the notes out of the trash created without an author to w, as Export does for the ones
+// of an author. They are the notes of anonymous requests and messages, and the ones created before revisions
+func ExportAnonymous(ctx context.Context, w io.Writer, format string) error {
+	return export(ctx, w, format, note.ExportFilter{Anonymous: true})
+}
+
+// export writes the notes selected by f to w in format, a page at a time
+func export(ctx context.Context, w io.Writer, format string, f note.ExportFilter) error {
+	var e exporter
+	switch format {
+	case ExportJSON:
+		e = &jsonExporter{w: w}
+	case ExportNDJSON:
+		e = &ndjsonExporter{encoder: json.NewEncoder(w)}
+	case ExportCSV:
+		e = &csvExporter{w: csv.NewWriter(w)}
+	case ExportMarkdown:
+		e = &markdownExporter{w: zip.NewWriter(w)}
+	default:
+		return ErrExportFormat
+	}
+
+	f.Limit = exportPage
+	for {
+		page, err := note.Export(ctx, f)
+		if err != nil {
+			return err
+		}
+		for _, n := range page {
+			if err := e.write(Note(n)); err != nil {
+				return fmt.Errorf("failed to write note %d: %w", n.Id, err)
+			}
+		}
+		if len(page) < exportPage {
+			break
+		}
+		f.After = page[len(page)-1].Id
+	}
+	return e.close()
+}
+
+// jsonExporter writes the notes as a JSON array
+type jsonExporter struct {
+	w     io.Writer
+	count int
+}
+
+func (e *jsonExporter) write(n Note) error {
+	encoded, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	separator := ",\n"
+	if e.count == 0 {
+		separator = "[\n"
+	}
+	e.count++
+	if _, err := io.WriteString(e.w, separator); err != nil {
+		return err
+	}
+	_, err = e.w.Write(encoded)
+	return err
+}
+
+func (e *jsonExporter) close() error {
+	end := "\n]\n"
+	if e.count == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(e.w, end)
+	return err
+}
+
+// ndjsonExporter writes a note per line
+type ndjsonExporter struct {
+	encoder *json.Encoder
+}
+
+func (e *ndjsonExporter) write(n Note) error {
+	return e.encoder.Encode(n)
+}
+
+func (e *ndjsonExporter) close() error {
+	return nil
+}
+
+// csvExporter writes a note per row, after a header row. The tags are joined with commas
+type csvExporter struct {
+	w      *csv.Writer
+	header bool
+}
+
+func (e *csvExporter) write(n Note) error {
+	if err := e.start(); err != nil {
+		return err
+	}
+	notebook := ""
+	if n.NotebookId != 0 {
+		notebook = strconv.FormatUint(n.NotebookId, 10)
+	}
+	return e.w.Write([]string{
+		strconv.FormatUint(n.Id, 10),
+		n.Title,
+		n.Text,
+		strings.Join(n.Tags, ","),
+		notebook,
+		strconv.FormatUint(n.Version, 10),
+		n.CreatedAt.Format(time.RFC3339),
+		n.UpdatedAt.Format(time.RFC3339),
+	})
+}
+
+func (e *csvExporter) close() error {
+	if err := e.start(); err != nil {
+		return err
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// start writes the header row, once
+func (e *csvExporter) start() error {
+	if e.header {
+		return nil
+	}
+	e.header = true
+	return e.w.Write([]string{"id", "title", "text", "tags", "notebookId", "version", "createdAt", "updatedAt"})
+}
+
+// markdownExporter writes a zip with a Markdown file per note, named by its id and title
+type markdownExporter struct {
+	w *zip.Writer
+}
+
+func (e *markdownExporter) write(n Note) error {
+	f, err := e.w.CreateHeader(&zip.FileHeader{
+		Name:     markdownName(n),
+		Method:   zip.Deflate,
+		Modified: n.UpdatedAt,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(Markdown(n))
+	return err
+}
+
+func (e *markdownExporter) close() error {
+	return e.w.Close()
+}
+
+// Markdown is the text of a note after a YAML front matter with its title, tags, notebook and timestamps.
+// Title and tags are written as JSON strings, which YAML reads as double-quoted strings
+func Markdown(n Note) []byte {
+	title, _ := json.Marshal(n.Title)
+	tags := n.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	encodedTags, _ := json.Marshal(tags)
+
+	var b strings.Builder
+	b.WriteString("---\n")
+	b.WriteString("title: " + string(title) + "\n")
+	b.WriteString("tags: " + string(encodedTags) + "\n")
+	if n.NotebookId != 0 {
+		b.WriteString("notebookId: " + strconv.FormatUint(n.NotebookId, 10) + "\n")
+	}
+	b.WriteString("createdAt: " + n.CreatedAt.Format(time.RFC3339) + "\n")
+	b.WriteString("updatedAt: " + n.UpdatedAt.Format(time.RFC3339) + "\n")
+	b.WriteString("---\n\n")
+	b.WriteString(n.Text)
+	if !strings.HasSuffix(n.Text, "\n") {
+		b.WriteString("\n")
+	}
+	return []byte(b.String())
+}
+
+// markdownName is the file of a note in a Markdown export, its id followed by its title in lower case,
+// with anything but letters and digits replaced by dashes
+func markdownName(n Note) string {
+	var slug []rune
+	dash := false
+	for _, r := range strings.ToLower(n.Title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && len(slug) > 0 {
+				slug = append(slug, '-')
+			}
+			slug = append(slug, r)
+			dash = false
+		} else {
+			dash = true
+		}
+		if len(slug) >= 50 {
+			break
+		}
+	}
+	if len(slug) == 0 {
+		return fmt.Sprintf("%d.md", n.Id)
+	}
+	return fmt.Sprintf("%d-%s.md", n.Id, string(slug))
+}
@@ -0,0 +1,464 @@
+package note
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/ribgsilva/note-api/persistence/v1/note"
+	"github.com/ribgsilva/note-api/sys"
+	"gocloud.dev/pubsub"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Formats the notes are imported from
+const (
+	ImportMarkdown = "markdown"
+	ImportENEX     = "enex"
+)
+
+const (
+	// maxImportFile is the size of the largest file of a Markdown import
+	maxImportFile = 1 << 20
+	// maxImportFiles and maxImportTotal are how many files a Markdown import has at most, and how many bytes they
+	// hold uncompressed, so a small zip can not hold more than the API can read
+	maxImportFiles = 5000
+	maxImportTotal = 64 << 20
+	// maxImportTitle is the size of the longest title, the same one of the API
+	maxImportTitle = 100
+)
+
+// ImportType is the type of the messages of the notes queued by an import
+const ImportType = "import"
+
+// orderingMetadata is the message metadata holding the key of the messages the messaging app handles in order
+const orderingMetadata = "orderingKey"
+
+// ImportFormatOf is the format of an import file by its extension, empty when it is not known
+func ImportFormatOf(name string) string {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".zip":
+		return ImportMarkdown
+	case ".enex":
+		return ImportENEX
+	}
+	return ""
+}
+
+// ReadImport reads the notes of an import, either a zip of Markdown files or an Evernote export.
+// It returns ErrImportFormat when the content is not in the format
+func ReadImport(format string, content []byte) ([]ImportFile, error) {
+	switch format {
+	case ImportMarkdown:
+		return ReadMarkdown(bytes.NewReader(content), int64(len(content)))
+	case ImportENEX:
+		return ReadENEX(bytes.NewReader(content))
+	}
+	return nil, ErrImportFormat
+}
+
+// ContentHash identifies the content of a note imported by author, so every user imports the same note only once
+func ContentHash(n NewNote, author string) string {
+	h := sha256.New()
+	h.Write([]byte(author))
+	h.Write([]byte{0})
+	h.Write([]byte(n.Title))
+	h.Write([]byte{0})
+	h.Write([]byte(n.Text))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Import creates a note read from an import, unless a note with the same content was imported before.
+// It returns the note created, or only the id of the one imported before and true
+func Import(ctx context.Context, newN NewNote, author string) (Note, bool, error) {
+	if !ValidTags(newN.Tags) {
+		return Note{}, false, ErrInvalidTags
+	}
+	imported, duplicate, err := note.Import(ctx, note.NewNote{
+		Title:      newN.Title,
+		Text:       newN.Text,
+		Tags:       NormalizeTags(newN.Tags),
+		NotebookId: newN.NotebookId,
+		Author:     author,
+	}, ContentHash(newN, author))
+	switch {
+	case errors.Is(err, note.ErrNotebookNotFound):
+		return Note{}, false, ErrNotebookNotFound
+	case err != nil:
+		return Note{}, false, err
+	case duplicate:
+		return Note(imported), true, nil
+	}
+
+	created := Note(imported)
+	index(created)
+	publish(ctx, Created, Note{}, created, author)
+	return created, false, nil
+}
+
+// ImportFiles creates the notes of the files of an import one at a time, returning the result of every file.
+// A file that fails does not stop the others
+func ImportFiles(ctx context.Context, files []ImportFile, author string) []ImportResult {
+	results := make([]ImportResult, len(files))
+	for i, f := range files {
+		results[i] = ImportResult{File: f.Name, Status: ImportFailed, Error: f.Error}
+		if f.Error != "" {
+			continue
+		}
+		n, duplicate, err := Import(ctx, f.Note, author)
+		switch {
+		case errors.Is(err, ErrNotebookNotFound), errors.Is(err, ErrInvalidTags):
+			results[i].Error = err.Error()
+		case err != nil:
+			sys.R.Log.Errorw("import failed", "file", f.Name, "ERROR", err)
+			results[i].Error = "failed to create the note"
+		case duplicate:
+			results[i].Status, results[i].Id = ImportDuplicate, n.Id
+		default:
+			results[i].Status, results[i].Id = ImportCreated, n.Id
+		}
+	}
+	return results
+}
+
+// QueueImport sends the notes of the files of an import to topic, to be created by the messaging app.
+// Notes with the same content share the ordering key, so the messaging app creates only one of them
+func QueueImport(ctx context.Context, topic *pubsub.Topic, files []ImportFile, author string) []ImportResult {
+	results := make([]ImportResult, len(files))
+	for i, f := range files {
+		results[i] = ImportResult{File: f.Name, Status: ImportFailed, Error: f.Error}
+		if f.Error != "" {
+			continue
+		}
+		body, err := json.Marshal(Event{Type: ImportType, Data: ImportNote{File: f.Name, NewNote: f.Note}})
+		if err != nil {
+			results[i].Error = fmt.Sprintf("failed to parse the note: %s", err)
+			continue
+		}
+		metadata := map[string]string{"type": ImportType, orderingMetadata: ContentHash(f.Note, author)}
+		if author != "" {
+			metadata[authorMetadata] = author
+		}
+		if err := topic.Send(ctx, &pubsub.Message{Body: body, Metadata: metadata}); err != nil {
+			sys.R.Log.Errorw("import queue failed", "file", f.Name, "ERROR", err)
+			results[i].Error = "failed to queue the note"
+			continue
+		}
+		results[i].Status = ImportQueued
+	}
+	return results
+}
+
+// ReadMarkdown reads the Markdown files of a zip, in any folder. The title, tags and notebook come from a YAML
+// front matter as the one of the Markdown exports; without a title, the first heading or the file name is used.
+// It returns ErrImportTooLarge when the zip has more than maxImportFiles files or maxImportTotal bytes uncompressed
+func ReadMarkdown(r io.ReaderAt, size int64) ([]ImportFile, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, ErrImportFormat
+	}
+	if len(archive.File) > maxImportFiles {
+		return nil, ErrImportTooLarge
+	}
+
+	var files []ImportFile
+	var total int
+	for _, f := range archive.File {
+		base := path.Base(f.Name)
+		if f.FileInfo().IsDir() || strings.HasPrefix(f.Name, "__MACOSX/") || strings.HasPrefix(base, ".") ||
+			!strings.EqualFold(path.Ext(base), ".md") {
+			continue
+		}
+
+		file := ImportFile{Name: f.Name}
+		content, err := readZipFile(f)
+		if total += len(content); total > maxImportTotal {
+			return nil, ErrImportTooLarge
+		}
+		switch {
+		case err != nil:
+			file.Error = err.Error()
+		case !utf8.Valid(content):
+			file.Error = "must be UTF-8 text"
+		default:
+			file.Note, file.Error = parseMarkdown(string(content), strings.TrimSuffix(base, path.Ext(base)))
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// readZipFile reads a file of a zip up to maxImportFile bytes
+func readZipFile(f *zip.File) ([]byte, error) {
+	if f.UncompressedSize64 > maxImportFile {
+		return nil, fmt.Errorf("must have at most %d bytes", maxImportFile)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, errors.New("could not be read")
+	}
+	defer func() {
+		_ = rc.Close()
+	}()
+	content, err := io.ReadAll(io.LimitReader(rc, maxImportFile+1))
+	switch {
+	case err != nil:
+		return nil, errors.New("could not be read")
+	case len(content) > maxImportFile:
+		return nil, fmt.Errorf("must have at most %d bytes", maxImportFile)
+	}
+	return content, nil
+}
+
+// parseMarkdown reads a note from a Markdown file, returning why it is not valid when it is not
+func parseMarkdown(content, name string) (NewNote, string) {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	var newN NewNote
+
+	if strings.HasPrefix(content, "---\n") {
+		rest := content[len("---\n"):]
+		if strings.HasSuffix(rest, "\n---") {
+			rest += "\n"
+		}
+		if end := strings.Index(rest, "\n---\n"); end >= 0 {
+			if err := frontMatter(rest[:end], &newN); err != "" {
+				return NewNote{}, err
+			}
+			content = strings.TrimPrefix(rest[end+len("\n---\n"):], "\n")
+		}
+	}
+	newN.Text = strings.TrimSuffix(content, "\n")
+
+	if newN.Title == "" {
+		for _, line := range strings.Split(newN.Text, "\n") {
+			if strings.HasPrefix(line, "# ") {
+				newN.Title = strings.TrimSpace(line[len("# "):])
+				break
+			}
+		}
+	}
+	if newN.Title == "" {
+		newN.Title = strings.TrimSpace(name)
+	}
+	return checkImport(newN)
+}
+
+// frontMatter reads the title, tags and notebook of a YAML front matter. Only scalars and lists of scalars,
+// in flow or block style, are understood, the other keys are ignored
+func frontMatter(yaml string, newN *NewNote) string {
+	lines := strings.Split(yaml, "\n")
+	for i := 0; i < len(lines); i++ {
+		key, value, ok := strings.Cut(lines[i], ":")
+		if !ok || strings.HasPrefix(key, " ") || strings.HasPrefix(key, "#") {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "title":
+			newN.Title = scalar(value)
+		case "tags":
+			switch {
+			case strings.HasPrefix(value, "["):
+				newN.Tags = flowList(value)
+			case value == "":
+				for ; i+1 < len(lines); i++ {
+					item := strings.TrimSpace(lines[i+1])
+					if !strings.HasPrefix(item, "- ") {
+						break
+					}
+					newN.Tags = append(newN.Tags, scalar(item[len("- "):]))
+				}
+			default:
+				newN.Tags = []string{scalar(value)}
+			}
+		case "notebookId":
+			if value == "" || value == "null" {
+				continue
+			}
+			id, err := strconv.ParseUint(scalar(value), 10, 64)
+			if err != nil {
+				return "notebookId must be a number"
+			}
+			newN.NotebookId = id
+		}
+	}
+	return ""
+}
+
+// scalar is the string of a YAML scalar, unquoting it when quoted
+func scalar(value string) string {
+	value = strings.TrimSpace(value)
+	switch {
+	case len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"':
+		var s string
+		if err := json.Unmarshal([]byte(value), &s); err == nil {
+			return s
+		}
+		return value[1 : len(value)-1]
+	case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+		return strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+	}
+	if comment := strings.Index(value, " #"); comment >= 0 {
+		value = strings.TrimSpace(value[:comment])
+	}
+	return value
+}
+
+// flowList is the strings of a YAML flow sequence, as [a, "b"]
+func flowList(value string) []string {
+	var list []string
+	if err := json.Unmarshal([]byte(value), &list); err == nil {
+		return list
+	}
+	value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+	for _, item := range strings.Split(value, ",") {
+		if item = scalar(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// enexNote is a note of an Evernote export, its content in ENML
+type enexNote struct {
+	Title   string   `xml:"title"`
+	Content string   `xml:"content"`
+	Tags    []string `xml:"tag"`
+}
+
+// ReadENEX reads the notes of an Evernote export. Their content is converted to plain text, dropping the formatting
+// and the attachments
+func ReadENEX(r io.Reader) ([]ImportFile, error) {
+	decoder := xml.NewDecoder(r)
+	var files []ImportFile
+	root := false
+	for {
+		token, err := decoder.Token()
+		switch {
+		case errors.Is(err, io.EOF) && root:
+			return files, nil
+		case err != nil:
+			return nil, ErrImportFormat
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if !root {
+			if start.Name.Local != "en-export" {
+				return nil, ErrImportFormat
+			}
+			root = true
+			continue
+		}
+		if start.Name.Local != "note" {
+			if err := decoder.Skip(); err != nil {
+				return nil, ErrImportFormat
+			}
+			continue
+		}
+
+		var e enexNote
+		if err := decoder.DecodeElement(&e, &start); err != nil {
+			return nil, ErrImportFormat
+		}
+		file := ImportFile{Name: fmt.Sprintf("note %d", len(files)+1)}
+		if title := strings.TrimSpace(e.Title); title != "" {
+			file.Name = title
+		}
+		text, err := enmlText(e.Content)
+		if err != nil {
+			file.Error = "content is not valid ENML"
+		} else {
+			file.Note, file.Error = checkImport(NewNote{Title: strings.TrimSpace(e.Title), Text: text, Tags: e.Tags})
+		}
+		files = append(files, file)
+	}
+}
+
+// enmlBlocks are the ENML elements that start a line of their own
+var enmlBlocks = map[string]bool{
+	"div": true, "p": true, "br": true, "hr": true, "li": true, "tr": true, "blockquote": true, "pre": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "ul": true, "ol": true, "table": true,
+}
+
+// enmlText converts the ENML content of a note to plain text, a line per block and a dash before each list item
+func enmlText(enml string) (string, error) {
+	decoder := xml.NewDecoder(strings.NewReader(enml))
+	decoder.Strict = false
+	decoder.AutoClose = xml.HTMLAutoClose
+	decoder.Entity = xml.HTMLEntity
+
+	var b strings.Builder
+	newLine := func() {
+		if s := b.String(); s != "" && !strings.HasSuffix(s, "\n") {
+			b.WriteString("\n")
+		}
+	}
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if enmlBlocks[t.Name.Local] {
+				newLine()
+			}
+			switch t.Name.Local {
+			case "br":
+				b.WriteString("\n")
+			case "li":
+				b.WriteString("- ")
+			case "en-todo":
+				box := "[ ] "
+				for _, a := range t.Attr {
+					if a.Name.Local == "checked" && a.Value == "true" {
+						box = "[x] "
+					}
+				}
+				b.WriteString(box)
+			}
+		case xml.EndElement:
+			if enmlBlocks[t.Name.Local] {
+				newLine()
+			}
+		case xml.CharData:
+			b.Write(t)
+		}
+	}
+
+	text := b.String()
+	for strings.Contains(text, "\n\n\n") {
+		text = strings.ReplaceAll(text, "\n\n\n", "\n\n")
+	}
+	return strings.TrimSpace(text), nil
+}
+
+// checkImport tells why a note read from an import is not valid, with the limits of the API.
+// Long titles are cut, as they may come from headings and file names
+func checkImport(newN NewNote) (NewNote, string) {
+	if utf8.RuneCountInString(newN.Title) > maxImportTitle {
+		newN.Title = strings.TrimSpace(string([]rune(newN.Title)[:maxImportTitle]))
+	}
+	switch {
+	case newN.Title == "":
+		return newN, "title is required"
+	case !ValidTags(newN.Tags):
+		return newN, ErrInvalidTags.Error()
+	}
+	return newN, ""
+}
@@ -0,0 +1,105 @@
+package note
+
+import (
+	"context"
+	"github.com/ribgsilva/note-api/persistence/v1/note"
+	"github.com/ribgsilva/note-api/platform/search"
+	"github.com/ribgsilva/note-api/sys"
+	"sync"
+)
+
+const (
+	// titleWeight makes words of the title count more than words of the text
+	titleWeight  = 3
+	reindexBatch = 500
+)
+
+// rebuild keeps the notes changed while Reindex builds a new index, with their fields or nil when they were
+// removed, so they are replayed on the new index before it replaces the live one. changed is nil out of a rebuild
+var rebuild struct {
+	sync.Mutex
+	running sync.Mutex
+	changed map[uint64][]search.Field
+}
+
+func fields(title, text string) []search.Field {
+	return []search.Field{{Text: title, Weight: titleWeight}, {Text: text, Weight: 1}}
+}
+
+// index keeps a note in the embedded search index, when it is enabled. The index lives in the API process only,
+// so writes of the messaging app and of other replicas reach it when it is rebuilt by Reindex
+func index(n Note) {
+	if sys.R.Search == nil || n.Id == 0 {
+		return
+	}
+	f := fields(n.Title, n.Text)
+	rebuild.Lock()
+	defer rebuild.Unlock()
+	sys.R.Search.Add(n.Id, f...)
+	if rebuild.changed != nil {
+		rebuild.changed[n.Id] = f
+	}
+}
+
+// unindex takes notes out of the embedded search index, when it is enabled
+func unindex(ids ...uint64) {
+	if sys.R.Search == nil {
+		return
+	}
+	rebuild.Lock()
+	defer rebuild.Unlock()
+	for _, id := range ids {
+		sys.R.Search.Remove(id)
+		if rebuild.changed != nil {
+			rebuild.changed[id] = nil
+		}
+	}
+}
+
+// Reindex rebuilds the embedded search index from the notes out of the trash, returning how many were indexed.
+// It pages by id, the way Export does, so notes written while rebuilding do not shift the pages, and the notes
+// indexed or removed meanwhile are replayed on the new index before it replaces the live one
+func Reindex(ctx context.Context) (int, error) {
+	if sys.R.Search == nil {
+		return 0, ErrSearchUnsupported
+	}
+
+	rebuild.running.Lock()
+	defer rebuild.running.Unlock()
+	rebuild.Lock()
+	rebuild.changed = make(map[uint64][]search.Field)
+	rebuild.Unlock()
+	defer func() {
+		rebuild.Lock()
+		rebuild.changed = nil
+		rebuild.Unlock()
+	}()
+
+	built := search.New(sys.R.Search.Language())
+	var after uint64
+	for {
+		found, err := note.Export(ctx, note.ExportFilter{After: after, Limit: reindexBatch})
+		if err != nil {
+			return 0, err
+		}
+		for _, n := range found {
+			built.Add(n.Id, fields(n.Title, n.Text)...)
+		}
+		if len(found) < reindexBatch {
+			break
+		}
+		after = found[len(found)-1].Id
+	}
+
+	rebuild.Lock()
+	defer rebuild.Unlock()
+	for id, f := range rebuild.changed {
+		if f == nil {
+			built.Remove(id)
+		} else {
+			built.Add(id, f...)
+		}
+	}
+	sys.R.Search.Replace(built)
+	return built.Len(), nil
+}
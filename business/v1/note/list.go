@@ -0,0 +1,19 @@
+package note
+
+import (
+	"context"
+	"github.com/ribgsilva/note-api/persistence/v1/note"
+)
+
+func List(ctx context.Context, f Filter) ([]Note, error) {
+	f.Tags = NormalizeTags(f.Tags)
+	found, err := note.List(ctx, note.Filter(f))
+	if err != nil {
+		return nil, err
+	}
+	notes := make([]Note, len(found))
+	for i, n := range found {
+		notes[i] = Note(n)
+	}
+	return notes, nil
+}
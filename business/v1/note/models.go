@@ -1,13 +1,72 @@
 package note
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"github.com/ribgsilva/note-api/persistence/v1/note"
+	"time"
+)
+
+var (
+	// ErrVersionMismatch is returned when a note changed since the version the caller expected
+	ErrVersionMismatch = errors.New("note version mismatch")
+	// ErrNotebookNotFound is returned when a note is put in a notebook that does not exist
+	ErrNotebookNotFound = errors.New("notebook not found")
+	// ErrNotFound is returned by Apply when a note updated or deleted does not exist
+	ErrNotFound = errors.New("note not found")
+	// ErrInvalidTags is returned when a note has more than MaxTags tags, or tags longer than MaxTagLength
+	ErrInvalidTags = errors.New("notes have at most 20 tags of at most 50 characters")
+	// ErrExportFormat is returned when exporting in a format that is not known
+	ErrExportFormat = errors.New("unknown export format")
+	// ErrExportAuthor is returned when exporting without the user whose notes are exported
+	ErrExportAuthor = errors.New("exports need the user whose notes are exported")
+	// ErrImportFormat is returned when an import is not a zip of Markdown files or an Evernote export
+	ErrImportFormat = errors.New("import is not a zip of markdown files or an enex export")
+	// ErrImportTooLarge is returned when a Markdown import has too many files or too many bytes uncompressed
+	ErrImportTooLarge = errors.New("import has more than 5000 files or 64 MiB uncompressed")
+	// ErrEmptyQuery is returned when a search has no words
+	ErrEmptyQuery = errors.New("search query has no words")
+	// ErrSearchUnsupported is returned when searching a database dialect without full-text support
+	ErrSearchUnsupported = errors.New("full-text search not supported by the database dialect")
+)
 
 type Note struct {
-	Id        uint64    `json:"id" example:"1"`
-	Title     string    `json:"title" example:"my note"`
-	Text      string    `json:"text" example:"my note text"`
-	UpdatedAt time.Time `json:"updatedAt" example:"2006-01-02T15:04:05Z"`
-	CreatedAt time.Time `json:"createdAt" example:"2006-01-02T15:04:05Z"`
+	Id         uint64     `json:"id" example:"1"`
+	Title      string     `json:"title" example:"my note"`
+	Text       string     `json:"text" example:"my note text"`
+	Tags       []string   `json:"tags" example:"work,ideas"`
+	NotebookId uint64     `json:"notebookId,omitempty" example:"1"`
+	Version    uint64     `json:"version" example:"1"`
+	UpdatedAt  time.Time  `json:"updatedAt" example:"2006-01-02T15:04:05Z"`
+	CreatedAt  time.Time  `json:"createdAt" example:"2006-01-02T15:04:05Z"`
+	DeletedAt  *time.Time `json:"deletedAt,omitempty" example:"2006-01-02T15:04:05Z"`
+}
+
+// Filter selects the notes of a listing. When Tags are set, notes must have any of them, or all of them if MatchAll is set.
+// When NotebookPath is set, only notes of that notebook are listed, including its descendants if Recursive is set
+type Filter struct {
+	Trashed      bool
+	Tags         []string
+	MatchAll     bool
+	NotebookPath string
+	Recursive    bool
+	Limit        int
+	Offset       int
+}
+
+// Types of the events published on note changes
+const (
+	Created  = note.Created
+	Updated  = note.Updated
+	Deleted  = note.Deleted
+	Restored = note.Restored
+)
+
+// Change is the data of the events published on note changes, with the note before and after it.
+// Before is not set for created and restored notes, and After is not set for deleted notes
+type Change struct {
+	Before *Note `json:"before,omitempty"`
+	After  *Note `json:"after,omitempty"`
 }
 
 type Event struct {
@@ -16,6 +75,109 @@ type Event struct {
 }
 
 type NewNote struct {
-	Title string `json:"title"`
-	Text  string `json:"text"`
+	Title      string   `json:"title"`
+	Text       string   `json:"text"`
+	Tags       []string `json:"tags,omitempty" example:"work,ideas"`
+	NotebookId uint64   `json:"notebookId,omitempty" example:"1"`
+}
+
+// UpdateNote replaces the content of a note. When ExpectedVersion is set, the note is only changed if it is still in that version.
+// Tags are kept when nil
+type UpdateNote struct {
+	Id              uint64   `json:"id"`
+	Title           string   `json:"title"`
+	Text            string   `json:"text"`
+	Tags            []string `json:"tags,omitempty"`
+	ExpectedVersion uint64   `json:"expectedVersion,omitempty"`
+}
+
+// Operations of a batch
+const (
+	OpCreate = "create"
+	OpUpdate = "update"
+	OpDelete = "delete"
+)
+
+// Operation is a change of a batch. Note holds the content of creates and updates, and Id the note updated or deleted.
+// When ExpectedVersion is set, the note is only changed if it is still in that version
+type Operation struct {
+	Op              string   `json:"op" enums:"create,update,delete" example:"update"`
+	Id              uint64   `json:"id,omitempty" example:"1"`
+	ExpectedVersion uint64   `json:"expectedVersion,omitempty" example:"1"`
+	Note            *NewNote `json:"note,omitempty"`
+}
+
+// Batch is a list of operations applied in order. When Transactional is set either every operation is applied or none is
+type Batch struct {
+	Transactional bool        `json:"transactional"`
+	Operations    []Operation `json:"operations"`
+}
+
+// OperationError tells which operation of a batch failed, and why
+type OperationError struct {
+	Index int
+	Err   error
+}
+
+func (e *OperationError) Error() string {
+	return fmt.Sprintf("operation %d failed: %s", e.Index, e.Err)
+}
+
+func (e *OperationError) Unwrap() error {
+	return e.Err
+}
+
+// Statuses of the files of an import
+const (
+	ImportCreated   = "created"
+	ImportDuplicate = "duplicate"
+	ImportQueued    = "queued"
+	ImportFailed    = "failed"
+)
+
+// ImportFile is a note read from a file of an import, or why it could not be read
+type ImportFile struct {
+	Name  string
+	Note  NewNote
+	Error string
+}
+
+// ImportNote is a note read from a file of an import, queued to be created by the messaging app
+type ImportNote struct {
+	File string `json:"file"`
+	NewNote
+}
+
+// ImportResult is the outcome of a file of an import. Id is the note created, or the one imported before with the
+// same content for duplicates
+type ImportResult struct {
+	File   string `json:"file" example:"notes/my note.md"`
+	Status string `json:"status" enums:"created,duplicate,queued,failed" example:"created"`
+	Id     uint64 `json:"id,omitempty" example:"1"`
+	Error  string `json:"error,omitempty"`
+}
+
+// PatchNote changes only the fields that are set
+type PatchNote struct {
+	Title *string  `json:"title" example:"my note"`
+	Text  *string  `json:"text" example:"my note text"`
+	Tags  []string `json:"tags" example:"work,ideas"`
+}
+
+// MoveNote puts a note in a notebook, or in the root when NotebookId is 0
+type MoveNote struct {
+	NotebookId uint64 `json:"notebookId" example:"1"`
+}
+
+// SearchResult is a note found by a search, with its relevance and the title and a snippet of the text
+// with the matched words highlighted
+type SearchResult struct {
+	Note      Note      `json:"note"`
+	Score     float64   `json:"score" example:"1.5"`
+	Highlight Highlight `json:"highlight"`
+}
+
+type Highlight struct {
+	Title string `json:"title" example:"my <mark>note</mark>"`
+	Text  string `json:"text" example:"…text of my <mark>note</mark>…"`
 }
@@ -0,0 +1,37 @@
+package note
+
+import (
+	"context"
+	"errors"
+	"github.com/ribgsilva/note-api/persistence/v1/note"
+)
+
+// Move puts a note in a notebook, returning an empty note if it does not exist
+func Move(ctx context.Context, id uint64, m MoveNote, expectedVersion uint64, author string) (Note, error) {
+	var before Note
+	if direct() {
+		var err error
+		if before, err = Find(ctx, id); err != nil {
+			return Note{}, err
+		}
+	}
+
+	moved, err := note.Move(ctx, id, m.NotebookId, expectedVersion, author)
+	switch {
+	case errors.Is(err, note.ErrVersionMismatch):
+		return Note{}, ErrVersionMismatch
+	case errors.Is(err, note.ErrNotebookNotFound):
+		return Note{}, ErrNotebookNotFound
+	case err != nil:
+		return Note{}, err
+	case !moved:
+		return Note{}, nil
+	}
+
+	found, err := Find(ctx, id)
+	if err != nil {
+		return Note{}, err
+	}
+	publish(ctx, Updated, before, found, author)
+	return found, nil
+}
@@ -0,0 +1,33 @@
+package note
+
+import "context"
+
+// Patch changes only the fields set in the patch, returning an empty note if it does not exist.
+// The note is updated only if it did not change since it was read, or since the expected version when it is set
+func Patch(ctx context.Context, id uint64, p PatchNote, expectedVersion uint64, author string) (Note, error) {
+	current, err := Find(ctx, id)
+	if err != nil {
+		return Note{}, err
+	}
+	if current.Id == 0 {
+		return Note{}, nil
+	}
+	if expectedVersion != 0 && expectedVersion != current.Version {
+		return Note{}, ErrVersionMismatch
+	}
+
+	upd := UpdateNote{
+		Id:              id,
+		Title:           current.Title,
+		Text:            current.Text,
+		ExpectedVersion: current.Version,
+	}
+	if p.Title != nil {
+		upd.Title = *p.Title
+	}
+	if p.Text != nil {
+		upd.Text = *p.Text
+	}
+	upd.Tags = p.Tags
+	return Update(ctx, upd, author)
+}
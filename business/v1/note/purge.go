@@ -0,0 +1,27 @@
+package note
+
+import (
+	"context"
+	"github.com/ribgsilva/note-api/persistence/v1/note"
+	"time"
+)
+
+// purgeBatch is how many notes are removed per transaction, so a large trash does not lock the table for long
+const purgeBatch = 500
+
+// Purge permanently removes the notes that are in the trash for longer than the retention, returning how many were removed
+func Purge(ctx context.Context, retention time.Duration) (int64, error) {
+	before := time.Now().UTC().Add(-retention)
+
+	var total int64
+	for {
+		purged, err := note.Purge(ctx, before, purgeBatch)
+		total += purged
+		if err != nil {
+			return total, err
+		}
+		if purged < purgeBatch {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package note
+
+import (
+	"context"
+	"github.com/ribgsilva/note-api/persistence/v1/note"
+)
+
+// Restore takes a note out of the trash, returning an empty note if it is not in the trash
+func Restore(ctx context.Context, id uint64, author string) (Note, error) {
+	restored, err := note.Restore(ctx, id, author)
+	if err != nil {
+		return Note{}, err
+	}
+	if !restored {
+		return Note{}, nil
+	}
+
+	found, err := Find(ctx, id)
+	if err != nil {
+		return Note{}, err
+	}
+	index(found)
+	publish(ctx, Restored, Note{}, found, author)
+	return found, nil
+}
@@ -0,0 +1,109 @@
+package note
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+)
+
+// schemaFiles holds the JSON Schema of the data of each event the messaging app handles, named <type>.v<version>.json
+//
+//go:embed schemas/*.json
+var schemaFiles embed.FS
+
+var (
+	schemasOnce sync.Once
+	schemas     map[string]*jsonschema.Schema
+	schemasErr  error
+)
+
+// loadSchemas compiles the embedded schemas, keyed by their file name
+func loadSchemas() (map[string]*jsonschema.Schema, error) {
+	schemasOnce.Do(func() {
+		files, err := fs.Glob(schemaFiles, "schemas/*.json")
+		if err != nil {
+			schemasErr = fmt.Errorf("failed to list event schemas: %w", err)
+			return
+		}
+
+		compiler := jsonschema.NewCompiler()
+		for _, f := range files {
+			b, err := schemaFiles.ReadFile(f)
+			if err != nil {
+				schemasErr = fmt.Errorf("failed to read event schema %s: %w", f, err)
+				return
+			}
+			if err := compiler.AddResource(path.Base(f), bytes.NewReader(b)); err != nil {
+				schemasErr = fmt.Errorf("failed to add event schema %s: %w", f, err)
+				return
+			}
+		}
+
+		compiled := make(map[string]*jsonschema.Schema, len(files))
+		for _, f := range files {
+			name := path.Base(f)
+			s, err := compiler.Compile(name)
+			if err != nil {
+				schemasErr = fmt.Errorf("failed to compile event schema %s: %w", f, err)
+				return
+			}
+			compiled[name] = s
+		}
+		schemas = compiled
+	})
+	return schemas, schemasErr
+}
+
+// ValidateEvent checks the data of an event against the schema of its type and version,
+// returning a report with every violation found. An empty report means the data is valid
+func ValidateEvent(eventType, version string, data []byte) ([]string, error) {
+	compiled, err := loadSchemas()
+	if err != nil {
+		return nil, err
+	}
+
+	schema, ok := compiled[fmt.Sprintf("%s.v%s.json", eventType, version)]
+	if !ok {
+		return []string{fmt.Sprintf("no schema for %s version %s", eventType, version)}, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var v any
+	if err := decoder.Decode(&v); err != nil {
+		return []string{fmt.Sprintf("data is not valid JSON: %s", err)}, nil
+	}
+
+	err = schema.Validate(v)
+	var invalid *jsonschema.ValidationError
+	switch {
+	case err == nil:
+		return nil, nil
+	case !errors.As(err, &invalid):
+		return nil, fmt.Errorf("failed to validate %s version %s: %w", eventType, version, err)
+	}
+
+	var report []string
+	for _, e := range invalid.BasicOutput().Errors {
+		// the first errors only tell the document or a property did not validate, the causes come after them
+		if strings.HasPrefix(e.Error, "doesn't validate with") {
+			continue
+		}
+		location := e.InstanceLocation
+		if location == "" {
+			location = "/"
+		}
+		report = append(report, fmt.Sprintf("%s: %s", location, e.Error))
+	}
+	if len(report) == 0 {
+		report = []string{invalid.Error()}
+	}
+	return report, nil
+}
@@ -0,0 +1,141 @@
+package note
+
+import (
+	"context"
+	"errors"
+	"github.com/ribgsilva/note-api/persistence/v1/note"
+	"github.com/ribgsilva/note-api/platform/highlight"
+	"github.com/ribgsilva/note-api/sys"
+	"strings"
+	"unicode"
+)
+
+const (
+	maxTerms    = 10
+	snippetSize = 30
+)
+
+// Search finds the notes with all the words of the query, the most relevant first. Words between double quotes
+// are searched as a phrase, and a word ending in * as a prefix. The embedded search index is used when enabled,
+// matching the words of a phrase in any order, otherwise the full-text index of the database
+func Search(ctx context.Context, q string, limit, offset int) ([]SearchResult, error) {
+	terms := ParseQuery(q)
+	if len(terms) == 0 {
+		return nil, ErrEmptyQuery
+	}
+
+	var matches []note.Match
+	var err error
+	if sys.R.Search != nil {
+		matches, err = searchIndex(ctx, terms, limit, offset)
+	} else {
+		matches, err = note.Search(ctx, terms, limit, offset)
+	}
+	switch {
+	case errors.Is(err, note.ErrSearchUnsupported):
+		return nil, ErrSearchUnsupported
+	case err != nil:
+		return nil, err
+	}
+
+	match := matcher(terms)
+	results := make([]SearchResult, len(matches))
+	for i, m := range matches {
+		results[i] = SearchResult{
+			Note:  Note(m.Note),
+			Score: m.Score,
+			Highlight: Highlight{
+				Title: highlight.Snippet(m.Note.Title, match, 0),
+				Text:  highlight.Snippet(m.Note.Text, match, snippetSize),
+			},
+		}
+	}
+	return results, nil
+}
+
+// searchIndex finds the notes in the embedded search index, reading them from the cache or the database
+func searchIndex(ctx context.Context, terms []note.Term, limit, offset int) ([]note.Match, error) {
+	var words, prefixes []string
+	for _, t := range terms {
+		if t.Prefix {
+			prefixes = append(prefixes, t.Text)
+		} else {
+			words = append(words, t.Text)
+		}
+	}
+
+	hits := sys.R.Search.Search(words, prefixes)
+	if offset >= len(hits) {
+		return nil, nil
+	}
+	hits = hits[offset:]
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+
+	matches := make([]note.Match, 0, len(hits))
+	for _, h := range hits {
+		found, err := note.Find(ctx, h.Id)
+		if err != nil {
+			return nil, err
+		}
+		// the note may have left the index after the search, as when deleted
+		if found.Id != 0 {
+			matches = append(matches, note.Match{Note: found, Score: h.Score})
+		}
+	}
+	return matches, nil
+}
+
+// ParseQuery splits a query in lower cased terms, keeping only letters and digits so nothing is read as an operator
+func ParseQuery(q string) []note.Term {
+	var terms []note.Term
+	add := func(t note.Term) {
+		if t.Text != "" && len(terms) < maxTerms {
+			terms = append(terms, t)
+		}
+	}
+
+	for i, part := range strings.Split(q, `"`) {
+		// odd parts are between double quotes
+		if i%2 == 1 {
+			words := words(part)
+			add(note.Term{Text: strings.Join(words, " "), Phrase: len(words) > 1})
+			continue
+		}
+		for _, field := range strings.Fields(part) {
+			words := words(field)
+			for j, w := range words {
+				add(note.Term{Text: w, Prefix: j == len(words)-1 && strings.HasSuffix(field, "*")})
+			}
+		}
+	}
+	return terms
+}
+
+func words(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// matcher tells if a word is one of the terms, or one of the words of a phrase
+func matcher(terms []note.Term) func(string) bool {
+	return func(word string) bool {
+		for _, t := range terms {
+			switch {
+			case t.Prefix && strings.HasPrefix(word, t.Text):
+				return true
+			case t.Phrase:
+				for _, w := range strings.Fields(t.Text) {
+					if w == word {
+						return true
+					}
+				}
+			case word == t.Text:
+				return true
+			}
+		}
+		return false
+	}
+}
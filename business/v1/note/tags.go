@@ -0,0 +1,45 @@
+package note
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Limits of the tags of a note
+const (
+	MaxTags      = 20
+	MaxTagLength = 50
+)
+
+// ValidTags tells if the tags are within the limits of a note, as they were sent, the same way the API validates them
+func ValidTags(tags []string) bool {
+	if len(tags) > MaxTags {
+		return false
+	}
+	for _, t := range tags {
+		if utf8.RuneCountInString(t) > MaxTagLength {
+			return false
+		}
+	}
+	return true
+}
+
+// NormalizeTags trims and lower cases the tags, dropping empty and repeated ones. A nil slice stays nil
+func NormalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+	seen := make(map[string]struct{}, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if _, ok := seen[t]; ok || t == "" {
+			continue
+		}
+		seen[t] = struct{}{}
+		normalized = append(normalized, t)
+	}
+	sort.Strings(normalized)
+	return normalized
+}
@@ -0,0 +1,38 @@
+package note
+
+import (
+	"context"
+	"errors"
+	"github.com/ribgsilva/note-api/persistence/v1/note"
+)
+
+// Update replaces the note content, returning an empty note if it does not exist, or ErrInvalidTags if its tags
+// are out of the limits
+func Update(ctx context.Context, upd UpdateNote, author string) (Note, error) {
+	if !ValidTags(upd.Tags) {
+		return Note{}, ErrInvalidTags
+	}
+
+	before, after, updated, err := note.Update(ctx, note.UpdateNote{
+		Id:      upd.Id,
+		Title:   upd.Title,
+		Text:    upd.Text,
+		Tags:    NormalizeTags(upd.Tags),
+		Author:  author,
+		Version: upd.ExpectedVersion,
+	})
+	switch {
+	case errors.Is(err, note.ErrVersionMismatch):
+		return Note{}, ErrVersionMismatch
+	case err != nil:
+		return Note{}, err
+	case !updated:
+		return Note{}, nil
+	}
+
+	// the note before and after are the ones read while it was locked, so the change published is the one made
+	found := Note(after)
+	index(found)
+	publish(ctx, Updated, Note(before), found, author)
+	return found, nil
+}
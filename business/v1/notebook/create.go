@@ -0,0 +1,19 @@
+package notebook
+
+import (
+	"context"
+	"errors"
+	"github.com/ribgsilva/note-api/persistence/v1/notebook"
+)
+
+// Create inserts a notebook, returning ErrParentNotFound if its parent does not exist
+func Create(ctx context.Context, newN NewNotebook) (Notebook, error) {
+	id, err := notebook.Insert(ctx, notebook.NewNotebook(newN))
+	switch {
+	case errors.Is(err, notebook.ErrParentNotFound):
+		return Notebook{}, ErrParentNotFound
+	case err != nil:
+		return Notebook{}, err
+	}
+	return Find(ctx, id)
+}
@@ -0,0 +1,37 @@
+package notebook
+
+import (
+	"context"
+	"errors"
+	notes "github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/persistence/v1/note"
+	"github.com/ribgsilva/note-api/persistence/v1/notebook"
+)
+
+// Delete removes a notebook and its descendants, returning false if it does not exist.
+// Unless cascade is set it returns ErrNotEmpty when the notebook has notebooks or notes, otherwise the notes are moved to the trash
+func Delete(ctx context.Context, id uint64, cascade bool, author string) (bool, error) {
+	var before []notes.Note
+	if cascade {
+		found, err := Find(ctx, id)
+		if err != nil {
+			return false, err
+		}
+		if found.Id != 0 {
+			if before, err = notes.InNotebook(ctx, found.Path); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	deleted, trashed, err := notebook.Delete(ctx, id, cascade, author)
+	switch {
+	case errors.Is(err, notebook.ErrNotEmpty):
+		return false, ErrNotEmpty
+	case err != nil:
+		return false, err
+	}
+	note.Evict(ctx, trashed...)
+	notes.Trashed(ctx, trashed, before, author)
+	return deleted, nil
+}
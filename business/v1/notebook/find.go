@@ -0,0 +1,28 @@
+package notebook
+
+import (
+	"context"
+	"github.com/ribgsilva/note-api/persistence/v1/notebook"
+)
+
+// Find returns a notebook, or an empty notebook if it does not exist
+func Find(ctx context.Context, id uint64) (Notebook, error) {
+	found, err := notebook.Find(ctx, id)
+	if err != nil {
+		return Notebook{}, err
+	}
+	return Notebook(found), nil
+}
+
+// List returns all the notebooks, parents before their children
+func List(ctx context.Context) ([]Notebook, error) {
+	found, err := notebook.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	notebooks := make([]Notebook, len(found))
+	for i, nb := range found {
+		notebooks[i] = Notebook(nb)
+	}
+	return notebooks, nil
+}
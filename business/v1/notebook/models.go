@@ -0,0 +1,35 @@
+package notebook
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrParentNotFound is returned when the parent notebook does not exist
+	ErrParentNotFound = errors.New("parent notebook not found")
+	// ErrCycle is returned when a notebook would be moved into itself or one of its descendants
+	ErrCycle = errors.New("notebook cannot be moved into itself or its descendants")
+	// ErrNotEmpty is returned when deleting a notebook that still has notebooks or notes
+	ErrNotEmpty = errors.New("notebook is not empty")
+)
+
+type Notebook struct {
+	Id        uint64    `json:"id" example:"4"`
+	ParentId  uint64    `json:"parentId,omitempty" example:"1"`
+	Name      string    `json:"name" example:"projects"`
+	Path      string    `json:"path" example:"/1/4/"`
+	UpdatedAt time.Time `json:"updatedAt" example:"2006-01-02T15:04:05Z"`
+	CreatedAt time.Time `json:"createdAt" example:"2006-01-02T15:04:05Z"`
+}
+
+// NewNotebook creates a notebook inside its parent, or in the root when ParentId is 0
+type NewNotebook struct {
+	ParentId uint64 `json:"parentId,omitempty" example:"1"`
+	Name     string `json:"name" example:"projects"`
+}
+
+// MoveNotebook changes the parent of a notebook, moving it to the root when ParentId is 0
+type MoveNotebook struct {
+	ParentId uint64 `json:"parentId" example:"1"`
+}
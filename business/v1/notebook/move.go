@@ -0,0 +1,23 @@
+package notebook
+
+import (
+	"context"
+	"errors"
+	"github.com/ribgsilva/note-api/persistence/v1/notebook"
+)
+
+// Move changes the parent of a notebook, returning an empty notebook if it does not exist
+func Move(ctx context.Context, id uint64, m MoveNotebook) (Notebook, error) {
+	moved, err := notebook.Move(ctx, id, m.ParentId)
+	switch {
+	case errors.Is(err, notebook.ErrParentNotFound):
+		return Notebook{}, ErrParentNotFound
+	case errors.Is(err, notebook.ErrCycle):
+		return Notebook{}, ErrCycle
+	case err != nil:
+		return Notebook{}, err
+	case !moved:
+		return Notebook{}, nil
+	}
+	return Find(ctx, id)
+}
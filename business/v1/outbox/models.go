@@ -0,0 +1,9 @@
+package outbox
+
+import "time"
+
+// Lag tells how far behind the relay is: how many changes wait to be published and for how long the oldest waits
+type Lag struct {
+	Pending int64         `json:"pending"`
+	Age     time.Duration `json:"age"`
+}
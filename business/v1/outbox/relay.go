@@ -0,0 +1,67 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ribgsilva/note-api/business/v1/note"
+	"github.com/ribgsilva/note-api/persistence/v1/outbox"
+	"gocloud.dev/pubsub"
+	"strconv"
+	"time"
+)
+
+// Relay publishes up to limit pending changes to the topic, in the order they were done, returning how many were published.
+// It stops at the first failure, so a change is never published before the ones done earlier. It holds the relay lock
+// while publishing, so replicas relaying together publish every change once, and publishes nothing while another
+// replica holds it
+func Relay(ctx context.Context, topic *pubsub.Topic, limit int) (int, error) {
+	release, locked, err := outbox.Lock(ctx)
+	if err != nil || !locked {
+		return 0, err
+	}
+	defer release()
+
+	pending, err := outbox.Pending(ctx, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, m := range pending {
+		message, err := note.NewMessage(m.Type, m.NoteId, json.RawMessage(m.Payload), m.Author, strconv.FormatUint(m.Id, 10), m.CreatedAt)
+		if err != nil {
+			return i, fmt.Errorf("failed to build outbox message %d: %w", m.Id, err)
+		}
+
+		if err := topic.Send(ctx, message); err != nil {
+			if markErr := outbox.MarkFailed(ctx, m.Id, err.Error()); markErr != nil {
+				return i, fmt.Errorf("failed to publish outbox message %d: %s, and to record the failure: %w", m.Id, err, markErr)
+			}
+			return i, fmt.Errorf("failed to publish outbox message %d: %w", m.Id, err)
+		}
+
+		if err := outbox.MarkSent(ctx, m.Id); err != nil {
+			return i, err
+		}
+	}
+	return len(pending), nil
+}
+
+// FindLag returns how many changes wait to be published and the age of the oldest of them
+func FindLag(ctx context.Context) (Lag, error) {
+	backlog, err := outbox.Stats(ctx)
+	if err != nil {
+		return Lag{}, err
+	}
+
+	lag := Lag{Pending: backlog.Pending}
+	if !backlog.Oldest.IsZero() {
+		lag.Age = time.Since(backlog.Oldest)
+	}
+	return lag, nil
+}
+
+// Purge removes the changes published for longer than the retention, returning how many were removed
+func Purge(ctx context.Context, retention time.Duration) (int64, error) {
+	return outbox.Purge(ctx, time.Now().UTC().Add(-retention))
+}
@@ -0,0 +1,42 @@
+package revision
+
+import (
+	"context"
+	"errors"
+	"github.com/ribgsilva/note-api/platform/diff"
+)
+
+// ErrDiffTooLarge is returned when the revisions have too many changed lines to be compared
+var ErrDiffTooLarge = errors.New("revisions have too many changed lines to compare")
+
+// Compare compares two revisions of a note, returning an empty diff if any of them does not exist,
+// or ErrDiffTooLarge when they have more than diff.MaxLines changed lines
+func Compare(ctx context.Context, noteId, from, to uint64) (Diff, error) {
+	a, err := Find(ctx, noteId, from)
+	if err != nil {
+		return Diff{}, err
+	}
+	b, err := Find(ctx, noteId, to)
+	if err != nil {
+		return Diff{}, err
+	}
+	if a.Revision == 0 || b.Revision == 0 {
+		return Diff{}, nil
+	}
+
+	title, err := diff.Lines(a.Title, b.Title)
+	if err != nil {
+		return Diff{}, ErrDiffTooLarge
+	}
+	text, err := diff.Lines(a.Text, b.Text)
+	if err != nil {
+		return Diff{}, ErrDiffTooLarge
+	}
+	return Diff{
+		NoteId: noteId,
+		From:   from,
+		To:     to,
+		Title:  title,
+		Text:   text,
+	}, nil
+}
@@ -0,0 +1,29 @@
+package revision
+
+import (
+	"context"
+	"github.com/ribgsilva/note-api/persistence/v1/revision"
+)
+
+func Find(ctx context.Context, noteId, rev uint64) (Revision, error) {
+	find, err := revision.Find(ctx, noteId, rev)
+	if err != nil {
+		return Revision{}, err
+	}
+	if find.Revision == 0 {
+		return Revision{}, nil
+	}
+	return Revision(find), nil
+}
+
+func FindByNote(ctx context.Context, noteId uint64) ([]Revision, error) {
+	found, err := revision.FindByNote(ctx, noteId)
+	if err != nil {
+		return nil, err
+	}
+	revisions := make([]Revision, len(found))
+	for i, r := range found {
+		revisions[i] = Revision(r)
+	}
+	return revisions, nil
+}
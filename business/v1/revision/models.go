@@ -0,0 +1,23 @@
+package revision
+
+import (
+	"github.com/ribgsilva/note-api/platform/diff"
+	"time"
+)
+
+type Revision struct {
+	NoteId    uint64    `json:"noteId" example:"1"`
+	Revision  uint64    `json:"revision" example:"2"`
+	Title     string    `json:"title" example:"my note"`
+	Text      string    `json:"text" example:"my note text"`
+	Author    string    `json:"author" example:"john"`
+	CreatedAt time.Time `json:"createdAt" example:"2006-01-02T15:04:05Z"`
+}
+
+type Diff struct {
+	NoteId uint64      `json:"noteId" example:"1"`
+	From   uint64      `json:"from" example:"1"`
+	To     uint64      `json:"to" example:"2"`
+	Title  []diff.Line `json:"title"`
+	Text   []diff.Line `json:"text"`
+}
@@ -0,0 +1,24 @@
+package revision
+
+import (
+	"context"
+	"github.com/ribgsilva/note-api/business/v1/note"
+)
+
+// Restore brings back the content of an old revision. The restored content is stored as a new revision,
+// so the history is never rewritten. It returns an empty note if the revision does not exist
+func Restore(ctx context.Context, noteId, rev uint64, author string) (note.Note, error) {
+	found, err := Find(ctx, noteId, rev)
+	if err != nil {
+		return note.Note{}, err
+	}
+	if found.Revision == 0 {
+		return note.Note{}, nil
+	}
+
+	return note.Update(ctx, note.UpdateNote{
+		Id:    noteId,
+		Title: found.Title,
+		Text:  found.Text,
+	}, author)
+}
@@ -0,0 +1,19 @@
+package tag
+
+import (
+	"context"
+	"github.com/ribgsilva/note-api/persistence/v1/tag"
+)
+
+// List returns the tags in use with their note counts, the most used first
+func List(ctx context.Context) ([]Tag, error) {
+	found, err := tag.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tags := make([]Tag, len(found))
+	for i, t := range found {
+		tags[i] = Tag(t)
+	}
+	return tags, nil
+}
@@ -0,0 +1,6 @@
+package tag
+
+type Tag struct {
+	Name  string `json:"name" example:"work"`
+	Count int64  `json:"count" example:"3"`
+}
@@ -0,0 +1,27 @@
+package link
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"github.com/ribgsilva/note-api/sys"
+)
+
+// Delete removes a link and its view counter, returning false if the link did not exist anymore
+func Delete(ctx context.Context, noteId uint64, id string) (bool, error) {
+	cache := sys.R.Cache
+
+	tcCtx, tcCancel := context.WithTimeout(ctx, sys.Configs.Cache.OperationTimeout)
+	defer tcCancel()
+	var del *redis.IntCmd
+	_, err := cache.TxPipelined(tcCtx, func(pipe redis.Pipeliner) error {
+		del = pipe.Del(tcCtx, fmt.Sprintf(linkKey, id))
+		pipe.Del(tcCtx, fmt.Sprintf(linkViewsKey, id))
+		pipe.SRem(tcCtx, fmt.Sprintf(noteLinksKey, noteId), id)
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete link: %w", err)
+	}
+	return del.Val() > 0, nil
+}
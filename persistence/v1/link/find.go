@@ -0,0 +1,80 @@
+package link
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"github.com/ribgsilva/note-api/sys"
+)
+
+func Find(ctx context.Context, id string) (Link, error) {
+	cache := sys.R.Cache
+
+	tcCtx, tcCancel := context.WithTimeout(ctx, sys.Configs.Cache.OperationTimeout)
+	defer tcCancel()
+	get, err := cache.Get(tcCtx, fmt.Sprintf(linkKey, id)).Result()
+	switch {
+	case err == redis.Nil:
+		return Link{}, nil
+	case err != nil:
+		return Link{}, fmt.Errorf("failed to get link: %w", err)
+	}
+
+	var l Link
+	if err := json.Unmarshal([]byte(get), &l); err != nil {
+		return Link{}, fmt.Errorf("error parsing link %s: %w", id, err)
+	}
+	return l, nil
+}
+
+// FindByNote returns the links still alive for a note, cleaning up the ones already expired
+func FindByNote(ctx context.Context, noteId uint64) ([]Link, error) {
+	logger := sys.R.Log
+	cache := sys.R.Cache
+
+	setKey := fmt.Sprintf(noteLinksKey, noteId)
+
+	tcCtx, tcCancel := context.WithTimeout(ctx, sys.Configs.Cache.OperationTimeout)
+	defer tcCancel()
+	ids, err := cache.SMembers(tcCtx, setKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get note links: %w", err)
+	}
+	if len(ids) == 0 {
+		return []Link{}, nil
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = fmt.Sprintf(linkKey, id)
+	}
+	values, err := cache.MGet(tcCtx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get links: %w", err)
+	}
+
+	links := make([]Link, 0, len(values))
+	var expired []any
+	for i, v := range values {
+		s, ok := v.(string)
+		if !ok {
+			expired = append(expired, ids[i])
+			continue
+		}
+		var l Link
+		if err := json.Unmarshal([]byte(s), &l); err != nil {
+			logger.Errorf("error parsing link %s: %s", ids[i], err)
+			continue
+		}
+		links = append(links, l)
+	}
+
+	if len(expired) > 0 {
+		if err := cache.SRem(tcCtx, setKey, expired...).Err(); err != nil {
+			logger.Errorf("failure to remove expired links of note %d: %s", noteId, err)
+		}
+	}
+
+	return links, nil
+}
@@ -0,0 +1,31 @@
+package link
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"github.com/ribgsilva/note-api/sys"
+	"time"
+)
+
+func Insert(ctx context.Context, l Link) error {
+	cache := sys.R.Cache
+
+	data, err := json.Marshal(l)
+	if err != nil {
+		return fmt.Errorf("failed to parse link: %w", err)
+	}
+
+	tcCtx, tcCancel := context.WithTimeout(ctx, sys.Configs.Cache.OperationTimeout)
+	defer tcCancel()
+	_, err = cache.TxPipelined(tcCtx, func(pipe redis.Pipeliner) error {
+		pipe.Set(tcCtx, fmt.Sprintf(linkKey, l.Id), string(data), time.Until(l.ExpiresAt))
+		pipe.SAdd(tcCtx, fmt.Sprintf(noteLinksKey, l.NoteId), l.Id)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store link: %w", err)
+	}
+	return nil
+}
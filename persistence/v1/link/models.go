@@ -0,0 +1,18 @@
+package link
+
+import "time"
+
+const (
+	linkKey      = "links.%s"
+	linkViewsKey = "links.%s.views"
+	noteLinksKey = "notes.%d.links"
+)
+
+type Link struct {
+	Id           string
+	NoteId       uint64
+	PasswordHash []byte
+	MaxViews     int64
+	ExpiresAt    time.Time
+	CreatedAt    time.Time
+}
@@ -0,0 +1,88 @@
+package link
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"github.com/ribgsilva/note-api/sys"
+	"time"
+)
+
+const (
+	viewMissing   = -1
+	viewExhausted = -2
+)
+
+// viewScript increments the view counter only while the link exists and has views left,
+// so concurrent readers can never go over the limit
+var viewScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return -1
+end
+local views = redis.call('INCR', KEYS[2])
+if views == 1 then
+	redis.call('PEXPIRE', KEYS[2], ARGV[2])
+end
+local max = tonumber(ARGV[1])
+if max > 0 and views > max then
+	redis.call('DECR', KEYS[2])
+	return -2
+end
+return views
+`)
+
+// View atomically counts a view of the link, returning the views so far.
+// views is 0 when the link does not exist anymore, and exhausted is true when there are no views left
+func View(ctx context.Context, l Link) (views int64, exhausted bool, err error) {
+	cache := sys.R.Cache
+
+	tcCtx, tcCancel := context.WithTimeout(ctx, sys.Configs.Cache.OperationTimeout)
+	defer tcCancel()
+	keys := []string{fmt.Sprintf(linkKey, l.Id), fmt.Sprintf(linkViewsKey, l.Id)}
+	ttl := time.Until(l.ExpiresAt).Milliseconds()
+	if ttl <= 0 {
+		return 0, false, nil
+	}
+	res, err := viewScript.Run(tcCtx, cache, keys, l.MaxViews, ttl).Int64()
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to count link view: %w", err)
+	}
+	switch res {
+	case viewMissing:
+		return 0, false, nil
+	case viewExhausted:
+		return l.MaxViews, true, nil
+	default:
+		return res, false, nil
+	}
+}
+
+// Views returns how many times each link was viewed
+func Views(ctx context.Context, ids ...string) (map[string]int64, error) {
+	cache := sys.R.Cache
+
+	views := make(map[string]int64, len(ids))
+	if len(ids) == 0 {
+		return views, nil
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = fmt.Sprintf(linkViewsKey, id)
+	}
+
+	tcCtx, tcCancel := context.WithTimeout(ctx, sys.Configs.Cache.OperationTimeout)
+	defer tcCancel()
+	values, err := cache.MGet(tcCtx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get link views: %w", err)
+	}
+	for i, v := range values {
+		var count int64
+		if s, ok := v.(string); ok {
+			_, _ = fmt.Sscan(s, &count)
+		}
+		views[ids[i]] = count
+	}
+	return views, nil
+}
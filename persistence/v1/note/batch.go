@@ -0,0 +1,64 @@
+package note
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ribgsilva/note-api/sys"
+	"time"
+)
+
+// Apply runs the operations of a batch in order in a single transaction, returning the notes before and after each one.
+// Either every operation is applied or none is: the first one failing is returned as an *OperationError, with
+// ErrNotFound when the note it updates or deletes does not exist
+func Apply(ctx context.Context, ops []Operation) ([]Applied, error) {
+	if len(ops) == 0 {
+		return nil, nil
+	}
+	db := sys.R.Database
+
+	n := time.Now().UTC()
+
+	// every operation runs a few statements, so the batch gets the timeout of an operation for each one
+	dbCtx, dbCancel := context.WithTimeout(ctx, sys.Configs.Database.OperationTimeout*time.Duration(len(ops)))
+	defer dbCancel()
+	tx, err := db.BeginTx(dbCtx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin batch tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	applied := make([]Applied, len(ops))
+	var changed []uint64
+	for i, op := range ops {
+		var err error
+		found := true
+		switch {
+		case op.Create != nil:
+			applied[i].After, err = create(dbCtx, tx, *op.Create, n)
+		case op.Update != nil:
+			applied[i].Before, applied[i].After, found, err = update(dbCtx, tx, *op.Update, n)
+			changed = append(changed, op.Update.Id)
+		case op.Delete != nil:
+			applied[i].Before, found, err = trash(dbCtx, tx, op.Delete.Id, op.Delete.Version, op.Delete.Author, n)
+			changed = append(changed, op.Delete.Id)
+		default:
+			err = errors.New("empty operation")
+		}
+		if err == nil && !found {
+			err = ErrNotFound
+		}
+		if err != nil {
+			return nil, &OperationError{Index: i, Err: err}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit batch tx: %w", err)
+	}
+
+	Evict(ctx, changed...)
+	return applied, nil
+}
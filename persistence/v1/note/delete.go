@@ -0,0 +1,75 @@
+package note
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/ribgsilva/note-api/sys"
+	"time"
+)
+
+// Delete moves a note to the trash. It returns false if the note does not exist or is already in the trash,
+// and ErrVersionMismatch if it is not in the expected version. A version 0 deletes any version
+func Delete(ctx context.Context, id, version uint64, author string) (bool, error) {
+	db := sys.R.Database
+
+	n := time.Now().UTC()
+
+	dbCtx, dbCancel := context.WithTimeout(ctx, sys.Configs.Database.OperationTimeout)
+	defer dbCancel()
+	tx, err := db.BeginTx(dbCtx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin delete tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	_, deleted, err := trash(dbCtx, tx, id, version, author, n)
+	if err != nil || !deleted {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit delete tx: %w", err)
+	}
+
+	Evict(ctx, id)
+	return true, nil
+}
+
+// trash moves a note to the trash inside a transaction, recording the change. It returns the note before being
+// deleted, and false if it does not exist or is already in the trash
+func trash(ctx context.Context, tx *sql.Tx, id, version uint64, author string, n time.Time) (Note, bool, error) {
+	var current uint64
+	err := tx.QueryRowContext(ctx, "SELECT version FROM notes WHERE id = ? AND deletedAt IS NULL", id).Scan(&current)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return Note{}, false, nil
+	case err != nil:
+		return Note{}, false, fmt.Errorf("failed to query note to delete: %w", err)
+	case version != 0 && version != current:
+		return Note{}, false, ErrVersionMismatch
+	}
+
+	before, err := read(ctx, tx, id)
+	if err != nil {
+		return Note{}, false, err
+	}
+
+	res, err := tx.ExecContext(ctx, "UPDATE notes SET deletedAt = ?, version = version + 1 WHERE id = ? AND version = ?", n, id, current)
+	if err != nil {
+		return Note{}, false, fmt.Errorf("failed to exec delete stmt: %w", err)
+	}
+	if affected, err := res.RowsAffected(); err != nil {
+		return Note{}, false, fmt.Errorf("failed to get deleted rows: %w", err)
+	} else if affected == 0 {
+		return Note{}, false, ErrVersionMismatch
+	}
+
+	if err := Record(ctx, tx, Deleted, before, Note{}, author); err != nil {
+		return Note{}, false, err
+	}
+	return before, true, nil
+}
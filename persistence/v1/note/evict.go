@@ -0,0 +1,24 @@
+package note
+
+import (
+	"context"
+	"fmt"
+	"github.com/ribgsilva/note-api/sys"
+)
+
+// Evict removes notes from the cache, so they are read again from the database
+func Evict(ctx context.Context, ids ...uint64) {
+	if len(ids) == 0 {
+		return
+	}
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = fmt.Sprintf(noteKey, id)
+	}
+
+	tcCtx, tcCancel := context.WithTimeout(ctx, sys.Configs.Cache.OperationTimeout)
+	defer tcCancel()
+	if err := sys.R.Cache.Del(tcCtx, keys...).Err(); err != nil {
+		sys.R.Log.Error("failure to remove notes ", ids, " from cache: ", err.Error())
+	}
+}
@@ -0,0 +1,59 @@
+package note
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/ribgsilva/note-api/sys"
+)
+
+// Export returns a page of the notes out of the trash, in the order they were created, so the pages can be read
+// one after the other without holding a query open
+func Export(ctx context.Context, f ExportFilter) ([]Note, error) {
+	db := sys.R.Database
+
+	query := "SELECT id, title, notes, notebookId, version, updatedAt, createdAt FROM notes WHERE deletedAt IS NULL AND id > ?"
+	args := []any{f.After}
+	// the author of a note is the one of its first revision, backfilled without one for the notes older than revisions
+	if f.Author != "" || f.Anonymous {
+		query += " AND id IN (SELECT noteId FROM revisions WHERE revision = 1 AND author = ?)"
+		args = append(args, f.Author)
+	}
+	query += " ORDER BY id LIMIT ?"
+	args = append(args, f.Limit)
+
+	dbCtx, dbCancel := context.WithTimeout(ctx, sys.Configs.Database.OperationTimeout)
+	defer dbCancel()
+	rows, err := db.QueryContext(dbCtx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query export: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	notes := make([]Note, 0, f.Limit)
+	var ids []uint64
+	for rows.Next() {
+		var n Note
+		var notebookId sql.NullInt64
+		if err := rows.Scan(&n.Id, &n.Title, &n.Text, &notebookId, &n.Version, &n.UpdatedAt, &n.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error parsing db data: %w", err)
+		}
+		n.NotebookId = uint64(notebookId.Int64)
+		notes = append(notes, n)
+		ids = append(ids, n.Id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read exported notes: %w", err)
+	}
+
+	tags, err := findTags(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+	for i := range notes {
+		notes[i].Tags = tags[notes[i].Id]
+	}
+	return notes, nil
+}
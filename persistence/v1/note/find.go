@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/go-redis/redis/v8"
 	"github.com/ribgsilva/note-api/sys"
@@ -26,28 +27,36 @@ func Find(ctx context.Context, id uint64) (Note, error) {
 		var note Note
 		if err := json.Unmarshal([]byte(get), &note); err != nil {
 			logger.Error("error parsing cached response for key %s: %w", key, err)
-		} else {
+		} else if note.Version != 0 {
 			return note, nil
 		}
 	}
 
 	dbCtx, dbCancel := context.WithTimeout(ctx, sys.Configs.Database.OperationTimeout)
 	defer dbCancel()
-	stmt, err := db.PrepareContext(dbCtx, "SELECT * FROM notes WHERE id = ?")
+	stmt, err := db.PrepareContext(dbCtx, "SELECT id, title, notes, notebookId, version, updatedAt, createdAt FROM notes WHERE id = ? AND deletedAt IS NULL")
 	if err != nil {
 		return Note{}, fmt.Errorf("failed to prepare find stmt: %w", err)
 	}
-	row := stmt.QueryRowContext(dbCtx, id)
+	defer func() {
+		_ = stmt.Close()
+	}()
+	var note Note
+	var notebookId sql.NullInt64
+	err = stmt.QueryRowContext(dbCtx, id).Scan(&note.Id, &note.Title, &note.Text, &notebookId, &note.Version, &note.UpdatedAt, &note.CreatedAt)
 	switch {
-	case row.Err() != nil && row.Err() != sql.ErrNoRows:
-		return Note{}, fmt.Errorf("failed to query find stmt: %w", err)
-	case row.Err() != nil && row.Err() == sql.ErrNoRows:
+	case errors.Is(err, sql.ErrNoRows):
 		return Note{}, nil
+	case err != nil:
+		return Note{}, fmt.Errorf("error parsing db data: %w", err)
 	default:
-		var note Note
-		if err := row.Scan(&note.Id, &note.Title, &note.Text, &note.UpdatedAt, &note.CreatedAt); err != nil {
-			return Note{}, fmt.Errorf("error parsing db data: %w", err)
+		note.NotebookId = uint64(notebookId.Int64)
+
+		tags, err := findTags(ctx, note.Id)
+		if err != nil {
+			return Note{}, err
 		}
+		note.Tags = tags[note.Id]
 
 		if data, err := json.Marshal(note); err != nil {
 			logger.Error("error parsing data to cache cached response for key %s: %w", key, err)
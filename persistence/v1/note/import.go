@@ -0,0 +1,57 @@
+package note
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/ribgsilva/note-api/sys"
+	"time"
+)
+
+// Import creates a note unless a note with the same content hash was imported before and is out of the trash,
+// recording the hash in the same transaction. It returns the note created, or a note with only the id of the one
+// imported before and true
+func Import(ctx context.Context, newN NewNote, hash string) (Note, bool, error) {
+	db := sys.R.Database
+
+	n := time.Now().UTC()
+
+	dbCtx, dbCancel := context.WithTimeout(ctx, sys.Configs.Database.OperationTimeout)
+	defer dbCancel()
+	tx, err := db.BeginTx(dbCtx, nil)
+	if err != nil {
+		return Note{}, false, fmt.Errorf("failed to begin import tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var imported uint64
+	err = tx.QueryRowContext(dbCtx, `SELECT i.noteId FROM note_imports i JOIN notes n ON n.id = i.noteId
+		WHERE i.hash = ? AND n.deletedAt IS NULL`, hash).Scan(&imported)
+	switch {
+	case err == nil:
+		return Note{Id: imported}, true, nil
+	case !errors.Is(err, sql.ErrNoRows):
+		return Note{}, false, fmt.Errorf("failed to query imported hash: %w", err)
+	}
+
+	// the note imported before, if any, is in the trash, so it is imported again
+	if _, err := tx.ExecContext(dbCtx, "DELETE FROM note_imports WHERE hash = ?", hash); err != nil {
+		return Note{}, false, fmt.Errorf("failed to exec delete import stmt: %w", err)
+	}
+
+	created, err := create(dbCtx, tx, newN, n)
+	if err != nil {
+		return Note{}, false, err
+	}
+	if _, err := tx.ExecContext(dbCtx, "INSERT INTO note_imports (hash, noteId, createdAt) VALUES (?, ?, ?)", hash, created.Id, n); err != nil {
+		return Note{}, false, fmt.Errorf("failed to exec import stmt: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Note{}, false, fmt.Errorf("failed to commit import tx: %w", err)
+	}
+	return created, false, nil
+}
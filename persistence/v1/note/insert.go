@@ -2,25 +2,182 @@ package note
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"github.com/ribgsilva/note-api/persistence/v1/revision"
 	"github.com/ribgsilva/note-api/sys"
+	"strings"
 	"time"
 )
 
-func Insert(ctx context.Context, newN NewNote) error {
+// Insert creates a note, returning it as persisted
+func Insert(ctx context.Context, newN NewNote) (Note, error) {
 	db := sys.R.Database
 
 	n := time.Now().UTC()
 
 	dbCtx, dbCancel := context.WithTimeout(ctx, sys.Configs.Database.OperationTimeout)
 	defer dbCancel()
-	stmt, err := db.PrepareContext(dbCtx, "INSERT INTO notes (title, notes, updatedAt, createdAt) VALUES (?, ?, ?, ?)")
+	tx, err := db.BeginTx(dbCtx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to prepare insert stmt: %w", err)
+		return Note{}, fmt.Errorf("failed to begin insert tx: %w", err)
 	}
-	_, err = stmt.ExecContext(dbCtx, newN.Title, newN.Text, n, n)
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	created, err := create(dbCtx, tx, newN, n)
+	if err != nil {
+		return Note{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Note{}, fmt.Errorf("failed to commit insert tx: %w", err)
+	}
+	return created, nil
+}
+
+// create inserts a note with its first revision and tags inside a transaction, recording the change
+func create(ctx context.Context, tx *sql.Tx, newN NewNote, n time.Time) (Note, error) {
+	if err := checkNotebook(ctx, tx, newN.NotebookId); err != nil {
+		return Note{}, err
+	}
+
+	id, err := insertNote(ctx, tx, newN, n)
+	if err != nil {
+		return Note{}, err
+	}
+
+	if err := revision.Insert(ctx, tx, revision.NewRevision{
+		NoteId:    id,
+		Title:     newN.Title,
+		Text:      newN.Text,
+		Author:    newN.Author,
+		CreatedAt: n,
+	}); err != nil {
+		return Note{}, err
+	}
+
+	if err := setTags(ctx, tx, id, newN.Tags); err != nil {
+		return Note{}, err
+	}
+
+	created, err := read(ctx, tx, id)
+	if err != nil {
+		return Note{}, err
+	}
+	if err := Record(ctx, tx, Created, Note{}, created, newN.Author); err != nil {
+		return Note{}, err
+	}
+	return created, nil
+}
+
+// insertNote inserts the row of a note, returning its id. SQLite returns it from the statement itself,
+// while the other dialects have it in the result
+func insertNote(ctx context.Context, tx *sql.Tx, newN NewNote, n time.Time) (uint64, error) {
+	query := "INSERT INTO notes (title, notes, notebookId, updatedAt, createdAt) VALUES (?, ?, ?, ?, ?)"
+	args := []any{newN.Title, newN.Text, nullable(newN.NotebookId), n, n}
+
+	if sys.Configs.Database.Dialect == "sqlite3" {
+		var id uint64
+		if err := tx.QueryRowContext(ctx, query+" RETURNING id", args...).Scan(&id); err != nil {
+			return 0, fmt.Errorf("failed to exec insert stmt: %w", err)
+		}
+		return id, nil
+	}
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to exec insert stmt: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get inserted id: %w", err)
+	}
+	return uint64(id), nil
+}
+
+// InsertMany creates notes with a single statement in one transaction, returning them as persisted in the same order.
+// Either every note is created or none is
+func InsertMany(ctx context.Context, newNs []NewNote) ([]Note, error) {
+	if len(newNs) == 0 {
+		return nil, nil
+	}
+	db := sys.R.Database
+
+	n := time.Now().UTC()
+
+	dbCtx, dbCancel := context.WithTimeout(ctx, sys.Configs.Database.OperationTimeout)
+	defer dbCancel()
+	tx, err := db.BeginTx(dbCtx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to exec insert stmt: %w", err)
+		return nil, fmt.Errorf("failed to begin insert many tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	checked := map[uint64]bool{}
+	values := make([]string, 0, len(newNs))
+	args := make([]any, 0, len(newNs)*5)
+	for _, newN := range newNs {
+		if !checked[newN.NotebookId] {
+			if err := checkNotebook(dbCtx, tx, newN.NotebookId); err != nil {
+				return nil, err
+			}
+			checked[newN.NotebookId] = true
+		}
+		values = append(values, "(?, ?, ?, ?, ?)")
+		args = append(args, newN.Title, newN.Text, nullable(newN.NotebookId), n, n)
+	}
+
+	res, err := tx.ExecContext(dbCtx, "INSERT INTO notes (title, notes, notebookId, updatedAt, createdAt) VALUES "+strings.Join(values, ", "), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exec insert many stmt: %w", err)
+	}
+	lastId, err := res.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inserted id: %w", err)
+	}
+
+	// the ids of a multi-row insert are consecutive. MySQL returns the first one, and SQLite the last one
+	first := uint64(lastId)
+	if sys.Configs.Database.Dialect == "sqlite3" {
+		first = uint64(lastId) - uint64(len(newNs)) + 1
+	}
+
+	ids := make([]uint64, len(newNs))
+	revisions := make([]revision.NewRevision, len(newNs))
+	for i, newN := range newNs {
+		ids[i] = first + uint64(i)
+		revisions[i] = revision.NewRevision{
+			NoteId:    ids[i],
+			Title:     newN.Title,
+			Text:      newN.Text,
+			Author:    newN.Author,
+			CreatedAt: n,
+		}
+	}
+	if err := revision.InsertFirst(dbCtx, tx, revisions); err != nil {
+		return nil, err
+	}
+
+	created := make([]Note, len(newNs))
+	for i, newN := range newNs {
+		if err := setTags(dbCtx, tx, ids[i], newN.Tags); err != nil {
+			return nil, err
+		}
+
+		if created[i], err = read(dbCtx, tx, ids[i]); err != nil {
+			return nil, err
+		}
+		if err := Record(dbCtx, tx, Created, Note{}, created[i], newN.Author); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit insert many tx: %w", err)
 	}
-	return nil
+	return created, nil
 }
@@ -0,0 +1,97 @@
+package note
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/ribgsilva/note-api/sys"
+	"strings"
+)
+
+// List returns the notes matching the filter, the most recently changed first,
+// or the most recently deleted first when listing the trash
+func List(ctx context.Context, f Filter) ([]Note, error) {
+	db := sys.R.Database
+
+	var where []string
+	var args []any
+	order := "updatedAt DESC, id DESC"
+	if f.Trashed {
+		where = append(where, "deletedAt IS NOT NULL")
+		order = "deletedAt DESC, id DESC"
+	} else {
+		where = append(where, "deletedAt IS NULL")
+	}
+
+	if len(f.Tags) > 0 {
+		tagged := "SELECT nt.noteId FROM note_tags nt JOIN tags t ON t.id = nt.tagId WHERE t.name IN (" + placeholders(len(f.Tags)) + ")"
+		for _, t := range f.Tags {
+			args = append(args, t)
+		}
+		if f.MatchAll {
+			tagged += " GROUP BY nt.noteId HAVING COUNT(DISTINCT t.id) = ?"
+			args = append(args, len(f.Tags))
+		}
+		where = append(where, "id IN ("+tagged+")")
+	}
+	if f.NotebookPath != "" {
+		if f.Recursive {
+			where = append(where, "notebookId IN (SELECT id FROM notebooks WHERE path LIKE ?)")
+			args = append(args, f.NotebookPath+"%")
+		} else {
+			where = append(where, "notebookId IN (SELECT id FROM notebooks WHERE path = ?)")
+			args = append(args, f.NotebookPath)
+		}
+	}
+	args = append(args, f.Limit, f.Offset)
+
+	query := "SELECT id, title, notes, notebookId, version, updatedAt, createdAt, deletedAt FROM notes WHERE " + strings.Join(where, " AND ") + " ORDER BY " + order + " LIMIT ? OFFSET ?"
+
+	dbCtx, dbCancel := context.WithTimeout(ctx, sys.Configs.Database.OperationTimeout)
+	defer dbCancel()
+	stmt, err := db.PrepareContext(dbCtx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare list stmt: %w", err)
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	rows, err := stmt.QueryContext(dbCtx, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query list stmt: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	notes := make([]Note, 0)
+	var ids []uint64
+	for rows.Next() {
+		var n Note
+		var notebookId sql.NullInt64
+		var deletedAt sql.NullTime
+		if err := rows.Scan(&n.Id, &n.Title, &n.Text, &notebookId, &n.Version, &n.UpdatedAt, &n.CreatedAt, &deletedAt); err != nil {
+			return nil, fmt.Errorf("error parsing db data: %w", err)
+		}
+		n.NotebookId = uint64(notebookId.Int64)
+		if deletedAt.Valid {
+			t := deletedAt.Time
+			n.DeletedAt = &t
+		}
+		notes = append(notes, n)
+		ids = append(ids, n.Id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read notes: %w", err)
+	}
+
+	tags, err := findTags(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+	for i := range notes {
+		notes[i].Tags = tags[notes[i].Id]
+	}
+	return notes, nil
+}
@@ -1,18 +1,133 @@
 package note
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
 const noteKey = "notes.%d"
 
+var (
+	// ErrVersionMismatch is returned when a note changed since the version the caller expected
+	ErrVersionMismatch = errors.New("note version mismatch")
+	// ErrNotebookNotFound is returned when a note is put in a notebook that does not exist
+	ErrNotebookNotFound = errors.New("notebook not found")
+	// ErrNotFound is returned by Apply when a note updated or deleted does not exist
+	ErrNotFound = errors.New("note not found")
+	// ErrSearchUnsupported is returned when searching a database dialect without full-text support
+	ErrSearchUnsupported = errors.New("full-text search not supported by the database dialect")
+)
+
+// Types of the note changes written to the outbox
+const (
+	Created  = "note.created"
+	Updated  = "note.updated"
+	Deleted  = "note.deleted"
+	Restored = "note.restored"
+)
+
 type Note struct {
-	Id        uint64
-	Title     string
-	Text      string
-	UpdatedAt time.Time
-	CreatedAt time.Time
+	Id         uint64     `json:"id"`
+	Title      string     `json:"title"`
+	Text       string     `json:"text"`
+	Tags       []string   `json:"tags"`
+	NotebookId uint64     `json:"notebookId,omitempty"`
+	Version    uint64     `json:"version"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	DeletedAt  *time.Time `json:"deletedAt,omitempty"`
+}
+
+// Change is a note before and after being changed, as written to the outbox
+type Change struct {
+	Before *Note `json:"before,omitempty"`
+	After  *Note `json:"after,omitempty"`
+}
+
+// Filter selects the notes of a listing. When Tags are set, notes must have any of them, or all of them if MatchAll is set.
+// When NotebookPath is set, only notes of that notebook are listed, including its descendants if Recursive is set
+type Filter struct {
+	Trashed      bool
+	Tags         []string
+	MatchAll     bool
+	NotebookPath string
+	Recursive    bool
+	Limit        int
+	Offset       int
+}
+
+// ExportFilter selects a page of the notes exported, the ones after the After id. When Author is set,
+// only the notes it created are exported, and when Anonymous is, only the ones created without an author
+type ExportFilter struct {
+	Author    string
+	Anonymous bool
+	After     uint64
+	Limit     int
 }
 
 type NewNote struct {
-	Title string
-	Text  string
+	Title      string
+	Text       string
+	Tags       []string
+	NotebookId uint64
+	Author     string
+}
+
+// UpdateNote holds the new content of a note. When Version is set, the note is only changed if it is still in that version.
+// Tags are kept when nil
+type UpdateNote struct {
+	Id      uint64
+	Title   string
+	Text    string
+	Tags    []string
+	Author  string
+	Version uint64
+}
+
+// DeleteNote moves a note to the trash. When Version is set, the note is only deleted if it is still in that version
+type DeleteNote struct {
+	Id      uint64
+	Version uint64
+	Author  string
+}
+
+// Operation is a change of a batch, exactly one of Create, Update and Delete being set
+type Operation struct {
+	Create *NewNote
+	Update *UpdateNote
+	Delete *DeleteNote
+}
+
+// Applied is a note before and after an operation of a batch. Before is empty for creates, and After for deletes
+type Applied struct {
+	Before Note
+	After  Note
+}
+
+// OperationError tells which operation of a batch failed, and why
+type OperationError struct {
+	Index int
+	Err   error
+}
+
+func (e *OperationError) Error() string {
+	return fmt.Sprintf("operation %d failed: %s", e.Index, e.Err)
+}
+
+func (e *OperationError) Unwrap() error {
+	return e.Err
+}
+
+// Term is a word, a phrase or a word prefix searched in the title and text of the notes
+type Term struct {
+	Text   string
+	Phrase bool
+	Prefix bool
+}
+
+// Match is a note found by a search, with its relevance. The higher the score, the more relevant the note
+type Match struct {
+	Note  Note
+	Score float64
 }
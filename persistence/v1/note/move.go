@@ -0,0 +1,73 @@
+package note
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/ribgsilva/note-api/sys"
+	"time"
+)
+
+// Move puts a note in a notebook, or in the root when notebookId is 0. It returns false if the note does not exist,
+// ErrNotebookNotFound if the notebook does not exist and ErrVersionMismatch if it is not in the expected version
+func Move(ctx context.Context, id, notebookId, version uint64, author string) (bool, error) {
+	db := sys.R.Database
+
+	n := time.Now().UTC()
+
+	dbCtx, dbCancel := context.WithTimeout(ctx, sys.Configs.Database.OperationTimeout)
+	defer dbCancel()
+	tx, err := db.BeginTx(dbCtx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin move tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var current uint64
+	err = tx.QueryRowContext(dbCtx, "SELECT version FROM notes WHERE id = ? AND deletedAt IS NULL", id).Scan(&current)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("failed to query note to move: %w", err)
+	case version != 0 && version != current:
+		return false, ErrVersionMismatch
+	}
+
+	if err := checkNotebook(dbCtx, tx, notebookId); err != nil {
+		return false, err
+	}
+
+	before, err := Snapshot(dbCtx, tx, id)
+	if err != nil {
+		return false, err
+	}
+
+	res, err := tx.ExecContext(dbCtx, "UPDATE notes SET notebookId = ?, updatedAt = ?, version = version + 1 WHERE id = ? AND version = ?", nullable(notebookId), n, id, current)
+	if err != nil {
+		return false, fmt.Errorf("failed to exec move stmt: %w", err)
+	}
+	if affected, err := res.RowsAffected(); err != nil {
+		return false, fmt.Errorf("failed to get moved rows: %w", err)
+	} else if affected == 0 {
+		return false, ErrVersionMismatch
+	}
+
+	after, err := Snapshot(dbCtx, tx, id)
+	if err != nil {
+		return false, err
+	}
+	if err := Record(dbCtx, tx, Updated, before, after, author); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit move tx: %w", err)
+	}
+
+	Evict(ctx, id)
+	return true, nil
+}
@@ -0,0 +1,29 @@
+package note
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// checkNotebook returns ErrNotebookNotFound if the notebook does not exist. The root, id 0, always exists
+func checkNotebook(ctx context.Context, tx *sql.Tx, notebookId uint64) error {
+	if notebookId == 0 {
+		return nil
+	}
+	var id uint64
+	err := tx.QueryRowContext(ctx, "SELECT id FROM notebooks WHERE id = ?", notebookId).Scan(&id)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return ErrNotebookNotFound
+	case err != nil:
+		return fmt.Errorf("failed to query notebook: %w", err)
+	}
+	return nil
+}
+
+// nullable stores the root notebook as NULL
+func nullable(notebookId uint64) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(notebookId), Valid: notebookId != 0}
+}
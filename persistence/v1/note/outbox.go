@@ -0,0 +1,90 @@
+package note
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ribgsilva/note-api/persistence/v1/outbox"
+	"github.com/ribgsilva/note-api/sys"
+	"time"
+)
+
+// Snapshot reads a note and its tags inside a transaction, including notes in the trash.
+// It returns an empty note when the note does not exist or the outbox is disabled, as it is only needed to Record changes
+func Snapshot(ctx context.Context, tx *sql.Tx, id uint64) (Note, error) {
+	if !sys.Configs.Events.Outbox {
+		return Note{}, nil
+	}
+	return read(ctx, tx, id)
+}
+
+// read reads a note and its tags inside a transaction, as persisted, including notes in the trash.
+// It returns an empty note when the note does not exist
+func read(ctx context.Context, tx *sql.Tx, id uint64) (Note, error) {
+	var note Note
+	var notebookId sql.NullInt64
+	var deletedAt sql.NullTime
+	err := tx.QueryRowContext(ctx, "SELECT id, title, notes, notebookId, version, updatedAt, createdAt, deletedAt FROM notes WHERE id = ?", id).
+		Scan(&note.Id, &note.Title, &note.Text, &notebookId, &note.Version, &note.UpdatedAt, &note.CreatedAt, &deletedAt)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return Note{}, nil
+	case err != nil:
+		return Note{}, fmt.Errorf("failed to query note snapshot: %w", err)
+	}
+	note.NotebookId = uint64(notebookId.Int64)
+	if deletedAt.Valid {
+		note.DeletedAt = &deletedAt.Time
+	}
+
+	rows, err := tx.QueryContext(ctx, "SELECT t.name FROM note_tags nt JOIN tags t ON t.id = nt.tagId WHERE nt.noteId = ? ORDER BY t.name", id)
+	if err != nil {
+		return Note{}, fmt.Errorf("failed to query note snapshot tags: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return Note{}, fmt.Errorf("error parsing db data: %w", err)
+		}
+		note.Tags = append(note.Tags, name)
+	}
+	if err := rows.Err(); err != nil {
+		return Note{}, fmt.Errorf("failed to read note snapshot tags: %w", err)
+	}
+	return note, nil
+}
+
+// Record writes a note change to the outbox inside the transaction of the change, when the outbox is enabled.
+// Before is empty for created and restored notes, and after is empty for deleted notes
+func Record(ctx context.Context, tx *sql.Tx, changeType string, before, after Note, author string) error {
+	if !sys.Configs.Events.Outbox {
+		return nil
+	}
+
+	var change Change
+	noteId := after.Id
+	if before.Id != 0 {
+		change.Before = &before
+		noteId = before.Id
+	}
+	if after.Id != 0 {
+		change.After = &after
+	}
+	payload, err := json.Marshal(change)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s change: %w", changeType, err)
+	}
+
+	return outbox.Insert(ctx, tx, outbox.NewMessage{
+		Type:      changeType,
+		NoteId:    noteId,
+		Author:    author,
+		Payload:   payload,
+		CreatedAt: time.Now().UTC(),
+	})
+}
@@ -0,0 +1,76 @@
+package note
+
+import (
+	"context"
+	"fmt"
+	"github.com/ribgsilva/note-api/sys"
+	"strings"
+	"time"
+)
+
+// Purge permanently removes up to limit notes, and their revisions, that were moved to the trash before a moment.
+// It returns how many notes were removed
+func Purge(ctx context.Context, before time.Time, limit int) (int64, error) {
+	db := sys.R.Database
+
+	dbCtx, dbCancel := context.WithTimeout(ctx, sys.Configs.Database.OperationTimeout)
+	defer dbCancel()
+	tx, err := db.BeginTx(dbCtx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin purge tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	// the notes are locked, so one restored meanwhile waits for the purge instead of losing its revisions and tags
+	rows, err := tx.QueryContext(dbCtx, "SELECT id FROM notes WHERE deletedAt IS NOT NULL AND deletedAt < ? ORDER BY deletedAt LIMIT ?"+forUpdate(), before, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query notes to purge: %w", err)
+	}
+	var ids []any
+	for rows.Next() {
+		var id uint64
+		if err := rows.Scan(&id); err != nil {
+			_ = rows.Close()
+			return 0, fmt.Errorf("error parsing db data: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read notes to purge: %w", err)
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	in := placeholders(len(ids))
+	if _, err := tx.ExecContext(dbCtx, "DELETE FROM revisions WHERE noteId IN ("+in+")", ids...); err != nil {
+		return 0, fmt.Errorf("failed to exec purge revisions stmt: %w", err)
+	}
+	if _, err := tx.ExecContext(dbCtx, "DELETE FROM note_tags WHERE noteId IN ("+in+")", ids...); err != nil {
+		return 0, fmt.Errorf("failed to exec purge note tags stmt: %w", err)
+	}
+	if _, err := tx.ExecContext(dbCtx, "DELETE FROM note_imports WHERE noteId IN ("+in+")", ids...); err != nil {
+		return 0, fmt.Errorf("failed to exec purge note imports stmt: %w", err)
+	}
+	res, err := tx.ExecContext(dbCtx, "DELETE FROM notes WHERE deletedAt IS NOT NULL AND id IN ("+in+")", ids...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to exec purge stmt: %w", err)
+	}
+	purged, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get purged rows: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit purge tx: %w", err)
+	}
+	return purged, nil
+}
+
+// placeholders returns n comma separated bind placeholders, for IN clauses
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
@@ -0,0 +1,54 @@
+package note
+
+import (
+	"context"
+	"fmt"
+	"github.com/ribgsilva/note-api/sys"
+)
+
+// Restore takes a note out of the trash, returning false if it is not in the trash
+func Restore(ctx context.Context, id uint64, author string) (bool, error) {
+	db := sys.R.Database
+
+	dbCtx, dbCancel := context.WithTimeout(ctx, sys.Configs.Database.OperationTimeout)
+	defer dbCancel()
+	tx, err := db.BeginTx(dbCtx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin restore tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	stmt, err := tx.PrepareContext(dbCtx, "UPDATE notes SET deletedAt = NULL, version = version + 1 WHERE id = ? AND deletedAt IS NOT NULL")
+	if err != nil {
+		return false, fmt.Errorf("failed to prepare restore stmt: %w", err)
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+	res, err := stmt.ExecContext(dbCtx, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to exec restore stmt: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get restored rows: %w", err)
+	}
+	if affected == 0 {
+		return false, nil
+	}
+
+	after, err := Snapshot(dbCtx, tx, id)
+	if err != nil {
+		return false, err
+	}
+	if err := Record(dbCtx, tx, Restored, Note{}, after, author); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit restore tx: %w", err)
+	}
+	return true, nil
+}
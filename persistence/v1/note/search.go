@@ -0,0 +1,105 @@
+package note
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/ribgsilva/note-api/sys"
+	"strings"
+)
+
+// Search returns the notes out of the trash with all the terms, the most relevant first,
+// using the native full-text index of the database dialect
+func Search(ctx context.Context, terms []Term, limit, offset int) ([]Match, error) {
+	db := sys.R.Database
+
+	var query string
+	var args []any
+	switch sys.Configs.Database.Dialect {
+	case "mysql":
+		against := mysqlQuery(terms)
+		query = "SELECT id, title, notes, notebookId, version, updatedAt, createdAt, MATCH(title, notes) AGAINST (? IN BOOLEAN MODE) AS score " +
+			"FROM notes WHERE MATCH(title, notes) AGAINST (? IN BOOLEAN MODE) AND deletedAt IS NULL " +
+			"ORDER BY score DESC, id DESC LIMIT ? OFFSET ?"
+		args = []any{against, against, limit, offset}
+	case "sqlite3":
+		// bm25 is lower for the more relevant notes
+		query = "SELECT n.id, n.title, n.notes, n.notebookId, n.version, n.updatedAt, n.createdAt, -bm25(notes_fts) AS score " +
+			"FROM notes_fts JOIN notes n ON n.id = notes_fts.rowid WHERE notes_fts MATCH ? AND n.deletedAt IS NULL " +
+			"ORDER BY score DESC, n.id DESC LIMIT ? OFFSET ?"
+		args = []any{fts5Query(terms), limit, offset}
+	default:
+		return nil, ErrSearchUnsupported
+	}
+
+	dbCtx, dbCancel := context.WithTimeout(ctx, sys.Configs.Database.OperationTimeout)
+	defer dbCancel()
+	stmt, err := db.PrepareContext(dbCtx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare search stmt: %w", err)
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	rows, err := stmt.QueryContext(dbCtx, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query search stmt: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	matches := make([]Match, 0)
+	var ids []uint64
+	for rows.Next() {
+		var m Match
+		var notebookId sql.NullInt64
+		if err := rows.Scan(&m.Note.Id, &m.Note.Title, &m.Note.Text, &notebookId, &m.Note.Version, &m.Note.UpdatedAt, &m.Note.CreatedAt, &m.Score); err != nil {
+			return nil, fmt.Errorf("error parsing db data: %w", err)
+		}
+		m.Note.NotebookId = uint64(notebookId.Int64)
+		matches = append(matches, m)
+		ids = append(ids, m.Note.Id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read search results: %w", err)
+	}
+
+	tags, err := findTags(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+	for i := range matches {
+		matches[i].Note.Tags = tags[matches[i].Note.Id]
+	}
+	return matches, nil
+}
+
+// mysqlQuery requires every term in boolean mode, as in +word +"a phrase" +prefix*
+func mysqlQuery(terms []Term) string {
+	parts := make([]string, len(terms))
+	for i, t := range terms {
+		switch {
+		case t.Phrase:
+			parts[i] = `+"` + t.Text + `"`
+		case t.Prefix:
+			parts[i] = "+" + t.Text + "*"
+		default:
+			parts[i] = "+" + t.Text
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// fts5Query requires every term, quoting them so they are not read as FTS5 operators, as in "word" AND "a phrase" AND "prefix"*
+func fts5Query(terms []Term) string {
+	parts := make([]string, len(terms))
+	for i, t := range terms {
+		parts[i] = `"` + strings.ReplaceAll(t.Text, `"`, `""`) + `"`
+		if t.Prefix {
+			parts[i] += "*"
+		}
+	}
+	return strings.Join(parts, " AND ")
+}
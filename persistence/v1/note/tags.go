@@ -0,0 +1,73 @@
+package note
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/ribgsilva/note-api/sys"
+)
+
+// setTags replaces the tags of a note, creating the ones that do not exist yet. Tags are inserted ignoring the
+// ones that already exist and then read, so concurrent changes introducing the same tag do not collide
+func setTags(ctx context.Context, tx *sql.Tx, noteId uint64, names []string) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM note_tags WHERE noteId = ?", noteId); err != nil {
+		return fmt.Errorf("failed to exec delete note tags stmt: %w", err)
+	}
+
+	upsert := "INSERT INTO tags (name) VALUES (?) ON CONFLICT (name) DO NOTHING"
+	if sys.Configs.Database.Dialect == "mysql" {
+		upsert = "INSERT INTO tags (name) VALUES (?) ON DUPLICATE KEY UPDATE name = name"
+	}
+	for _, name := range names {
+		if _, err := tx.ExecContext(ctx, upsert, name); err != nil {
+			return fmt.Errorf("failed to exec insert tag stmt: %w", err)
+		}
+		var tagId int64
+		if err := tx.QueryRowContext(ctx, "SELECT id FROM tags WHERE name = ?", name).Scan(&tagId); err != nil {
+			return fmt.Errorf("failed to query tag: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, "INSERT INTO note_tags (noteId, tagId) VALUES (?, ?)", noteId, tagId); err != nil {
+			return fmt.Errorf("failed to exec insert note tag stmt: %w", err)
+		}
+	}
+	return nil
+}
+
+// findTags returns the tags of each note, sorted by name
+func findTags(ctx context.Context, ids ...uint64) (map[uint64][]string, error) {
+	db := sys.R.Database
+
+	tags := make(map[uint64][]string, len(ids))
+	if len(ids) == 0 {
+		return tags, nil
+	}
+
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+
+	dbCtx, dbCancel := context.WithTimeout(ctx, sys.Configs.Database.OperationTimeout)
+	defer dbCancel()
+	rows, err := db.QueryContext(dbCtx, "SELECT nt.noteId, t.name FROM note_tags nt JOIN tags t ON t.id = nt.tagId WHERE nt.noteId IN ("+placeholders(len(ids))+") ORDER BY t.name", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query note tags: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		var noteId uint64
+		var name string
+		if err := rows.Scan(&noteId, &name); err != nil {
+			return nil, fmt.Errorf("error parsing db data: %w", err)
+		}
+		tags[noteId] = append(tags[noteId], name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read note tags: %w", err)
+	}
+	return tags, nil
+}
@@ -0,0 +1,109 @@
+package note
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/ribgsilva/note-api/persistence/v1/revision"
+	"github.com/ribgsilva/note-api/sys"
+	"time"
+)
+
+// Update changes the note content, storing it as a new revision and bumping its version. It returns the note before
+// and after the change, as read with the note locked, false if the note does not exist, and ErrVersionMismatch if it
+// is not in the expected version
+func Update(ctx context.Context, upd UpdateNote) (Note, Note, bool, error) {
+	db := sys.R.Database
+
+	n := time.Now().UTC()
+
+	dbCtx, dbCancel := context.WithTimeout(ctx, sys.Configs.Database.OperationTimeout)
+	defer dbCancel()
+	tx, err := db.BeginTx(dbCtx, nil)
+	if err != nil {
+		return Note{}, Note{}, false, fmt.Errorf("failed to begin update tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	before, after, updated, err := update(dbCtx, tx, upd, n)
+	if err != nil || !updated {
+		return Note{}, Note{}, false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Note{}, Note{}, false, fmt.Errorf("failed to commit update tx: %w", err)
+	}
+
+	Evict(ctx, upd.Id)
+	return before, after, true, nil
+}
+
+// update changes the note content inside a transaction, recording the change. It returns the note before and after
+// being changed, and false if it does not exist. The note row is locked first, so concurrent updates of a note
+// number their revisions one after the other
+func update(ctx context.Context, tx *sql.Tx, upd UpdateNote, n time.Time) (Note, Note, bool, error) {
+	var version uint64
+	err := tx.QueryRowContext(ctx, "SELECT version FROM notes WHERE id = ? AND deletedAt IS NULL"+forUpdate(), upd.Id).Scan(&version)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return Note{}, Note{}, false, nil
+	case err != nil:
+		return Note{}, Note{}, false, fmt.Errorf("failed to query note to update: %w", err)
+	case upd.Version != 0 && upd.Version != version:
+		return Note{}, Note{}, false, ErrVersionMismatch
+	}
+
+	before, err := read(ctx, tx, upd.Id)
+	if err != nil {
+		return Note{}, Note{}, false, err
+	}
+
+	res, err := tx.ExecContext(ctx, "UPDATE notes SET title = ?, notes = ?, version = version + 1, updatedAt = ? WHERE id = ? AND version = ?",
+		upd.Title, upd.Text, n, upd.Id, version)
+	if err != nil {
+		return Note{}, Note{}, false, fmt.Errorf("failed to exec update stmt: %w", err)
+	}
+	// someone else changed the note between the read and the update
+	if affected, err := res.RowsAffected(); err != nil {
+		return Note{}, Note{}, false, fmt.Errorf("failed to get updated rows: %w", err)
+	} else if affected == 0 {
+		return Note{}, Note{}, false, ErrVersionMismatch
+	}
+
+	if err := revision.Insert(ctx, tx, revision.NewRevision{
+		NoteId:    upd.Id,
+		Title:     upd.Title,
+		Text:      upd.Text,
+		Author:    upd.Author,
+		CreatedAt: n,
+	}); err != nil {
+		return Note{}, Note{}, false, err
+	}
+
+	if upd.Tags != nil {
+		if err := setTags(ctx, tx, upd.Id, upd.Tags); err != nil {
+			return Note{}, Note{}, false, err
+		}
+	}
+
+	after, err := read(ctx, tx, upd.Id)
+	if err != nil {
+		return Note{}, Note{}, false, err
+	}
+	if err := Record(ctx, tx, Updated, before, after, upd.Author); err != nil {
+		return Note{}, Note{}, false, err
+	}
+	return before, after, true, nil
+}
+
+// forUpdate locks the rows read until the transaction ends on MySQL. SQLite has no row locks, as it runs one write
+// transaction at a time
+func forUpdate() string {
+	if sys.Configs.Database.Dialect == "mysql" {
+		return " FOR UPDATE"
+	}
+	return ""
+}
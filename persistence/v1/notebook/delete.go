@@ -0,0 +1,99 @@
+package notebook
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/ribgsilva/note-api/persistence/v1/note"
+	"github.com/ribgsilva/note-api/sys"
+	"time"
+)
+
+const subtree = "SELECT id FROM notebooks WHERE path LIKE ?"
+
+// Delete removes a notebook and its descendants. Unless cascade is set, it returns ErrNotEmpty when there are
+// notebooks or notes in it; with cascade, the notes are moved to the trash and their ids are returned.
+// Notes in the trash are taken out of the deleted notebooks, so they are restored to the root
+func Delete(ctx context.Context, id uint64, cascade bool, author string) (bool, []uint64, error) {
+	db := sys.R.Database
+
+	n := time.Now().UTC()
+
+	dbCtx, dbCancel := context.WithTimeout(ctx, sys.Configs.Database.OperationTimeout)
+	defer dbCancel()
+	tx, err := db.BeginTx(dbCtx, nil)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to begin delete notebook tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var path string
+	err = tx.QueryRowContext(dbCtx, "SELECT path FROM notebooks WHERE id = ?", id).Scan(&path)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return false, nil, nil
+	case err != nil:
+		return false, nil, fmt.Errorf("failed to query notebook to delete: %w", err)
+	}
+	like := path + "%"
+
+	rows, err := tx.QueryContext(dbCtx, "SELECT id FROM notes WHERE deletedAt IS NULL AND notebookId IN ("+subtree+")", like)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to query notebook notes: %w", err)
+	}
+	var notes []uint64
+	for rows.Next() {
+		var noteId uint64
+		if err := rows.Scan(&noteId); err != nil {
+			_ = rows.Close()
+			return false, nil, fmt.Errorf("error parsing db data: %w", err)
+		}
+		notes = append(notes, noteId)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return false, nil, fmt.Errorf("failed to read notebook notes: %w", err)
+	}
+
+	if !cascade {
+		var notebooks int
+		if err := tx.QueryRowContext(dbCtx, "SELECT COUNT(*) FROM notebooks WHERE path LIKE ?", like).Scan(&notebooks); err != nil {
+			return false, nil, fmt.Errorf("failed to query notebook children: %w", err)
+		}
+		if notebooks > 1 || len(notes) > 0 {
+			return false, nil, ErrNotEmpty
+		}
+	}
+
+	before := make([]note.Note, 0, len(notes))
+	for _, noteId := range notes {
+		snapshot, err := note.Snapshot(dbCtx, tx, noteId)
+		if err != nil {
+			return false, nil, err
+		}
+		before = append(before, snapshot)
+	}
+
+	if _, err := tx.ExecContext(dbCtx, "UPDATE notes SET deletedAt = ?, version = version + 1 WHERE deletedAt IS NULL AND notebookId IN ("+subtree+")", n, like); err != nil {
+		return false, nil, fmt.Errorf("failed to exec trash notebook notes stmt: %w", err)
+	}
+	for _, b := range before {
+		if err := note.Record(dbCtx, tx, note.Deleted, b, note.Note{}, author); err != nil {
+			return false, nil, err
+		}
+	}
+	if _, err := tx.ExecContext(dbCtx, "UPDATE notes SET notebookId = NULL WHERE notebookId IN ("+subtree+")", like); err != nil {
+		return false, nil, fmt.Errorf("failed to exec detach notebook notes stmt: %w", err)
+	}
+	if _, err := tx.ExecContext(dbCtx, "DELETE FROM notebooks WHERE path LIKE ?", like); err != nil {
+		return false, nil, fmt.Errorf("failed to exec delete notebook stmt: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, nil, fmt.Errorf("failed to commit delete notebook tx: %w", err)
+	}
+	return true, notes, nil
+}
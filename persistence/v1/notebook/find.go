@@ -0,0 +1,77 @@
+package notebook
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/ribgsilva/note-api/sys"
+)
+
+const columns = "id, parentId, name, path, updatedAt, createdAt"
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scan(s scanner) (Notebook, error) {
+	var nb Notebook
+	var parentId sql.NullInt64
+	if err := s.Scan(&nb.Id, &parentId, &nb.Name, &nb.Path, &nb.UpdatedAt, &nb.CreatedAt); err != nil {
+		return Notebook{}, err
+	}
+	nb.ParentId = uint64(parentId.Int64)
+	return nb, nil
+}
+
+// Find returns a notebook, or an empty notebook if it does not exist
+func Find(ctx context.Context, id uint64) (Notebook, error) {
+	db := sys.R.Database
+
+	dbCtx, dbCancel := context.WithTimeout(ctx, sys.Configs.Database.OperationTimeout)
+	defer dbCancel()
+	stmt, err := db.PrepareContext(dbCtx, "SELECT "+columns+" FROM notebooks WHERE id = ?")
+	if err != nil {
+		return Notebook{}, fmt.Errorf("failed to prepare find notebook stmt: %w", err)
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	nb, err := scan(stmt.QueryRowContext(dbCtx, id))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return Notebook{}, nil
+	case err != nil:
+		return Notebook{}, fmt.Errorf("error parsing db data: %w", err)
+	}
+	return nb, nil
+}
+
+// List returns all the notebooks ordered by path, so parents come before their children
+func List(ctx context.Context) ([]Notebook, error) {
+	db := sys.R.Database
+
+	dbCtx, dbCancel := context.WithTimeout(ctx, sys.Configs.Database.OperationTimeout)
+	defer dbCancel()
+	rows, err := db.QueryContext(dbCtx, "SELECT "+columns+" FROM notebooks ORDER BY path")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query list notebooks: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	notebooks := make([]Notebook, 0)
+	for rows.Next() {
+		nb, err := scan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing db data: %w", err)
+		}
+		notebooks = append(notebooks, nb)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read notebooks: %w", err)
+	}
+	return notebooks, nil
+}
@@ -0,0 +1,48 @@
+package notebook
+
+import (
+	"context"
+	"fmt"
+	"github.com/ribgsilva/note-api/sys"
+	"time"
+)
+
+// Insert creates a notebook under its parent, returning its id
+func Insert(ctx context.Context, newN NewNotebook) (uint64, error) {
+	db := sys.R.Database
+
+	n := time.Now().UTC()
+
+	dbCtx, dbCancel := context.WithTimeout(ctx, sys.Configs.Database.OperationTimeout)
+	defer dbCancel()
+	tx, err := db.BeginTx(dbCtx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin insert notebook tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	parent, err := parentPath(dbCtx, tx, newN.ParentId)
+	if err != nil {
+		return 0, err
+	}
+
+	// the path needs the id, so it is set once the notebook is inserted
+	res, err := tx.ExecContext(dbCtx, "INSERT INTO notebooks (parentId, name, path, updatedAt, createdAt) VALUES (?, ?, ?, ?, ?)", nullable(newN.ParentId), newN.Name, parent, n, n)
+	if err != nil {
+		return 0, fmt.Errorf("failed to exec insert notebook stmt: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get inserted notebook id: %w", err)
+	}
+	if _, err := tx.ExecContext(dbCtx, "UPDATE notebooks SET path = ? WHERE id = ?", childPath(parent, uint64(id)), id); err != nil {
+		return 0, fmt.Errorf("failed to exec notebook path stmt: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit insert notebook tx: %w", err)
+	}
+	return uint64(id), nil
+}
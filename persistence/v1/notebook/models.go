@@ -0,0 +1,30 @@
+package notebook
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrParentNotFound is returned when the parent notebook does not exist
+	ErrParentNotFound = errors.New("parent notebook not found")
+	// ErrCycle is returned when a notebook would be moved into itself or one of its descendants
+	ErrCycle = errors.New("notebook cannot be moved into itself or its descendants")
+	// ErrNotEmpty is returned when deleting a notebook that still has notebooks or notes
+	ErrNotEmpty = errors.New("notebook is not empty")
+)
+
+// Notebook groups notes. Path is the materialised path of ids from the root, as in /1/4/7/
+type Notebook struct {
+	Id        uint64
+	ParentId  uint64
+	Name      string
+	Path      string
+	UpdatedAt time.Time
+	CreatedAt time.Time
+}
+
+type NewNotebook struct {
+	ParentId uint64
+	Name     string
+}
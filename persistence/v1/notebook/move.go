@@ -0,0 +1,81 @@
+package notebook
+
+import (
+	"context"
+	"fmt"
+	"github.com/ribgsilva/note-api/sys"
+	"strings"
+	"time"
+)
+
+// Move changes the parent of a notebook, rewriting the path of all its descendants.
+// It returns false if the notebook does not exist and ErrCycle if the parent is the notebook or one of its descendants
+func Move(ctx context.Context, id, parentId uint64) (bool, error) {
+	db := sys.R.Database
+
+	n := time.Now().UTC()
+
+	dbCtx, dbCancel := context.WithTimeout(ctx, sys.Configs.Database.OperationTimeout)
+	defer dbCancel()
+	tx, err := db.BeginTx(dbCtx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin move notebook tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	// the notebook and its parent are locked before the cycle check, so two moves of one under the other can not
+	// both pass it
+	locked, err := lockPaths(dbCtx, tx, id, parentId)
+	if err != nil {
+		return false, err
+	}
+	oldPath, ok := locked[id]
+	if !ok {
+		return false, nil
+	}
+	parent := root
+	if parentId != 0 {
+		if parent, ok = locked[parentId]; !ok {
+			return false, ErrParentNotFound
+		}
+	}
+	if strings.HasPrefix(parent, oldPath) {
+		return false, ErrCycle
+	}
+	newPath := childPath(parent, id)
+
+	rows, err := tx.QueryContext(dbCtx, "SELECT id, path FROM notebooks WHERE path LIKE ?", oldPath+"%")
+	if err != nil {
+		return false, fmt.Errorf("failed to query notebooks to move: %w", err)
+	}
+	paths := make(map[uint64]string)
+	for rows.Next() {
+		var childId uint64
+		var path string
+		if err := rows.Scan(&childId, &path); err != nil {
+			_ = rows.Close()
+			return false, fmt.Errorf("error parsing db data: %w", err)
+		}
+		paths[childId] = newPath + strings.TrimPrefix(path, oldPath)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return false, fmt.Errorf("failed to read notebooks to move: %w", err)
+	}
+
+	for childId, path := range paths {
+		if _, err := tx.ExecContext(dbCtx, "UPDATE notebooks SET path = ? WHERE id = ?", path, childId); err != nil {
+			return false, fmt.Errorf("failed to exec notebook path stmt: %w", err)
+		}
+	}
+	if _, err := tx.ExecContext(dbCtx, "UPDATE notebooks SET parentId = ?, updatedAt = ? WHERE id = ?", nullable(parentId), n, id); err != nil {
+		return false, fmt.Errorf("failed to exec move notebook stmt: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit move notebook tx: %w", err)
+	}
+	return true, nil
+}
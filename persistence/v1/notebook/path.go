@@ -0,0 +1,80 @@
+package notebook
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/ribgsilva/note-api/sys"
+	"strconv"
+	"strings"
+)
+
+const root = "/"
+
+// childPath returns the path of a notebook under the given parent path
+func childPath(parent string, id uint64) string {
+	return parent + strconv.FormatUint(id, 10) + "/"
+}
+
+// parentPath returns the path of a parent notebook, or the root path when there is no parent
+func parentPath(ctx context.Context, tx *sql.Tx, parentId uint64) (string, error) {
+	if parentId == 0 {
+		return root, nil
+	}
+	var path string
+	err := tx.QueryRowContext(ctx, "SELECT path FROM notebooks WHERE id = ?", parentId).Scan(&path)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return "", ErrParentNotFound
+	case err != nil:
+		return "", fmt.Errorf("failed to query parent notebook: %w", err)
+	}
+	return path, nil
+}
+
+// lockPaths returns the paths of the notebooks by their ids, locking them in the order of their ids so concurrent
+// moves wait for each other instead of deadlocking. Notebooks that do not exist are left out
+func lockPaths(ctx context.Context, tx *sql.Tx, ids ...uint64) (map[uint64]string, error) {
+	args := make([]any, 0, len(ids))
+	for _, id := range ids {
+		if id != 0 {
+			args = append(args, id)
+		}
+	}
+	in := strings.TrimSuffix(strings.Repeat("?, ", len(args)), ", ")
+	rows, err := tx.QueryContext(ctx, "SELECT id, path FROM notebooks WHERE id IN ("+in+") ORDER BY id"+forUpdate(), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notebooks to lock: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	paths := make(map[uint64]string, len(args))
+	for rows.Next() {
+		var id uint64
+		var path string
+		if err := rows.Scan(&id, &path); err != nil {
+			return nil, fmt.Errorf("error parsing db data: %w", err)
+		}
+		paths[id] = path
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read notebooks to lock: %w", err)
+	}
+	return paths, nil
+}
+
+// forUpdate locks the rows a query reads until the transaction ends, on MySQL. SQLite serializes the writers
+func forUpdate() string {
+	if sys.Configs.Database.Dialect == "mysql" {
+		return " FOR UPDATE"
+	}
+	return ""
+}
+
+// nullable stores the zero id as NULL
+func nullable(id uint64) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(id), Valid: id != 0}
+}
@@ -0,0 +1,24 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Insert stores a message to be published.
+// It runs inside the transaction of the change it tells about, so a change is never saved without its message
+func Insert(ctx context.Context, tx *sql.Tx, newM NewMessage) error {
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO outbox (type, noteId, author, payload, createdAt) VALUES (?, ?, ?, ?, ?)")
+	if err != nil {
+		return fmt.Errorf("failed to prepare insert outbox stmt: %w", err)
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+	_, err = stmt.ExecContext(ctx, newM.Type, newM.NoteId, newM.Author, newM.Payload, newM.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to exec insert outbox stmt: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/ribgsilva/note-api/sys"
+)
+
+// Lock takes the relay lock, so a single messaging replica publishes the outbox at a time and keeps its order.
+// It returns false when another replica holds the lock, and the func releasing it otherwise. The lock is a MySQL
+// named lock, released as well when its connection closes; other dialects are used by a single process, so they
+// always get it
+func Lock(ctx context.Context) (func(), bool, error) {
+	if sys.Configs.Database.Dialect != "mysql" {
+		return func() {}, true, nil
+	}
+
+	conn, err := sys.R.Database.Conn(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get a connection to lock the outbox: %w", err)
+	}
+	dbCtx, dbCancel := context.WithTimeout(ctx, sys.Configs.Database.OperationTimeout)
+	defer dbCancel()
+	var locked sql.NullInt64
+	if err := conn.QueryRowContext(dbCtx, "SELECT GET_LOCK('outbox_relay', 0)").Scan(&locked); err != nil {
+		_ = conn.Close()
+		return nil, false, fmt.Errorf("failed to lock the outbox: %w", err)
+	}
+	if locked.Int64 != 1 {
+		_ = conn.Close()
+		return nil, false, nil
+	}
+	return func() {
+		_, _ = conn.ExecContext(context.Background(), "SELECT RELEASE_LOCK('outbox_relay')")
+		_ = conn.Close()
+	}, true, nil
+}
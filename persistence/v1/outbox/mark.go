@@ -0,0 +1,53 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"github.com/ribgsilva/note-api/sys"
+	"time"
+)
+
+// MarkSent records that a message was published, so it is not published again
+func MarkSent(ctx context.Context, id uint64) error {
+	db := sys.R.Database
+
+	dbCtx, dbCancel := context.WithTimeout(ctx, sys.Configs.Database.OperationTimeout)
+	defer dbCancel()
+	if _, err := db.ExecContext(dbCtx, "UPDATE outbox SET sentAt = ?, attempts = attempts + 1, lastError = NULL WHERE id = ?", time.Now().UTC(), id); err != nil {
+		return fmt.Errorf("failed to exec mark outbox sent stmt: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed records a failed attempt to publish a message, keeping it pending
+func MarkFailed(ctx context.Context, id uint64, reason string) error {
+	db := sys.R.Database
+
+	if len(reason) > 1000 {
+		reason = reason[:1000]
+	}
+
+	dbCtx, dbCancel := context.WithTimeout(ctx, sys.Configs.Database.OperationTimeout)
+	defer dbCancel()
+	if _, err := db.ExecContext(dbCtx, "UPDATE outbox SET attempts = attempts + 1, lastError = ? WHERE id = ?", reason, id); err != nil {
+		return fmt.Errorf("failed to exec mark outbox failed stmt: %w", err)
+	}
+	return nil
+}
+
+// Purge removes the messages published before a moment, returning how many were removed
+func Purge(ctx context.Context, before time.Time) (int64, error) {
+	db := sys.R.Database
+
+	dbCtx, dbCancel := context.WithTimeout(ctx, sys.Configs.Database.OperationTimeout)
+	defer dbCancel()
+	res, err := db.ExecContext(dbCtx, "DELETE FROM outbox WHERE sentAt IS NOT NULL AND sentAt < ?", before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to exec purge outbox stmt: %w", err)
+	}
+	purged, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get purged outbox rows: %w", err)
+	}
+	return purged, nil
+}
@@ -0,0 +1,28 @@
+package outbox
+
+import "time"
+
+// Message is an event waiting in the outbox to be published
+type Message struct {
+	Id        uint64
+	Type      string
+	NoteId    uint64
+	Author    string
+	Payload   []byte
+	Attempts  int
+	CreatedAt time.Time
+}
+
+type NewMessage struct {
+	Type      string
+	NoteId    uint64
+	Author    string
+	Payload   []byte
+	CreatedAt time.Time
+}
+
+// Backlog tells how far behind the relay is. Oldest is the creation of the oldest pending message, zero when there is none
+type Backlog struct {
+	Pending int64
+	Oldest  time.Time
+}
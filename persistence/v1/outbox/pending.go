@@ -0,0 +1,59 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"github.com/ribgsilva/note-api/sys"
+	"time"
+)
+
+// Pending returns up to limit messages not published yet, in the order they were written
+func Pending(ctx context.Context, limit int) ([]Message, error) {
+	db := sys.R.Database
+
+	dbCtx, dbCancel := context.WithTimeout(ctx, sys.Configs.Database.OperationTimeout)
+	defer dbCancel()
+	rows, err := db.QueryContext(dbCtx, "SELECT id, type, noteId, author, payload, attempts, createdAt FROM outbox WHERE sentAt IS NULL ORDER BY id LIMIT ?", limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pending outbox: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var messages []Message
+	for rows.Next() {
+		var m Message
+		if err := rows.Scan(&m.Id, &m.Type, &m.NoteId, &m.Author, &m.Payload, &m.Attempts, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error parsing db data: %w", err)
+		}
+		messages = append(messages, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read pending outbox: %w", err)
+	}
+	return messages, nil
+}
+
+// Stats counts the messages not published yet and finds the oldest of them
+func Stats(ctx context.Context) (Backlog, error) {
+	db := sys.R.Database
+
+	dbCtx, dbCancel := context.WithTimeout(ctx, sys.Configs.Database.OperationTimeout)
+	defer dbCancel()
+
+	var s Backlog
+	if err := db.QueryRowContext(dbCtx, "SELECT COUNT(*) FROM outbox WHERE sentAt IS NULL").Scan(&s.Pending); err != nil {
+		return Backlog{}, fmt.Errorf("failed to count pending outbox: %w", err)
+	}
+	if s.Pending == 0 {
+		return s, nil
+	}
+
+	var oldest time.Time
+	if err := db.QueryRowContext(dbCtx, "SELECT createdAt FROM outbox WHERE sentAt IS NULL ORDER BY id LIMIT 1").Scan(&oldest); err != nil {
+		return Backlog{}, fmt.Errorf("failed to query oldest pending outbox: %w", err)
+	}
+	s.Oldest = oldest
+	return s, nil
+}
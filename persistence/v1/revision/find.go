@@ -0,0 +1,70 @@
+package revision
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/ribgsilva/note-api/sys"
+)
+
+func Find(ctx context.Context, noteId, rev uint64) (Revision, error) {
+	db := sys.R.Database
+
+	dbCtx, dbCancel := context.WithTimeout(ctx, sys.Configs.Database.OperationTimeout)
+	defer dbCancel()
+	stmt, err := db.PrepareContext(dbCtx, "SELECT noteId, revision, title, notes, author, createdAt FROM revisions WHERE noteId = ? AND revision = ?")
+	if err != nil {
+		return Revision{}, fmt.Errorf("failed to prepare find revision stmt: %w", err)
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	var r Revision
+	err = stmt.QueryRowContext(dbCtx, noteId, rev).Scan(&r.NoteId, &r.Revision, &r.Title, &r.Text, &r.Author, &r.CreatedAt)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return Revision{}, nil
+	case err != nil:
+		return Revision{}, fmt.Errorf("error parsing db data: %w", err)
+	default:
+		return r, nil
+	}
+}
+
+// FindByNote returns all the revisions of a note, the oldest first
+func FindByNote(ctx context.Context, noteId uint64) ([]Revision, error) {
+	db := sys.R.Database
+
+	dbCtx, dbCancel := context.WithTimeout(ctx, sys.Configs.Database.OperationTimeout)
+	defer dbCancel()
+	stmt, err := db.PrepareContext(dbCtx, "SELECT noteId, revision, title, notes, author, createdAt FROM revisions WHERE noteId = ? ORDER BY revision")
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare find revisions stmt: %w", err)
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	rows, err := stmt.QueryContext(dbCtx, noteId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query find revisions stmt: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	revisions := make([]Revision, 0)
+	for rows.Next() {
+		var r Revision
+		if err := rows.Scan(&r.NoteId, &r.Revision, &r.Title, &r.Text, &r.Author, &r.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error parsing db data: %w", err)
+		}
+		revisions = append(revisions, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read revisions: %w", err)
+	}
+	return revisions, nil
+}
@@ -0,0 +1,51 @@
+package revision
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// Insert stores a new revision of a note, numbered after the last one.
+// It runs inside the transaction of the note change, so a note is never saved without its revision. Callers lock
+// the note row first, so two changes of a note never read the same last revision
+func Insert(ctx context.Context, tx *sql.Tx, newR NewRevision) error {
+	row := tx.QueryRowContext(ctx, "SELECT COALESCE(MAX(revision), 0) + 1 FROM revisions WHERE noteId = ?", newR.NoteId)
+	var rev uint64
+	if err := row.Scan(&rev); err != nil {
+		return fmt.Errorf("failed to query next revision: %w", err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO revisions (noteId, revision, title, notes, author, createdAt) VALUES (?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return fmt.Errorf("failed to prepare insert revision stmt: %w", err)
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+	_, err = stmt.ExecContext(ctx, newR.NoteId, rev, newR.Title, newR.Text, newR.Author, newR.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to exec insert revision stmt: %w", err)
+	}
+	return nil
+}
+
+// InsertFirst inserts the first revision of new notes with a single statement
+func InsertFirst(ctx context.Context, tx *sql.Tx, newRs []NewRevision) error {
+	if len(newRs) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(newRs))
+	args := make([]any, 0, len(newRs)*5)
+	for _, r := range newRs {
+		values = append(values, "(?, 1, ?, ?, ?, ?)")
+		args = append(args, r.NoteId, r.Title, r.Text, r.Author, r.CreatedAt)
+	}
+	query := "INSERT INTO revisions (noteId, revision, title, notes, author, createdAt) VALUES " + strings.Join(values, ", ")
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to exec insert first revisions stmt: %w", err)
+	}
+	return nil
+}
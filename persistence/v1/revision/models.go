@@ -0,0 +1,20 @@
+package revision
+
+import "time"
+
+type Revision struct {
+	NoteId    uint64
+	Revision  uint64
+	Title     string
+	Text      string
+	Author    string
+	CreatedAt time.Time
+}
+
+type NewRevision struct {
+	NoteId    uint64
+	Title     string
+	Text      string
+	Author    string
+	CreatedAt time.Time
+}
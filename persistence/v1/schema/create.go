@@ -3,15 +3,28 @@ package schema
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/ribgsilva/note-api/sys"
+	"io/fs"
+	"path"
 )
 
+// Create creates the tables of the configured database dialect, at the version the migrations start from
 func Create(ctx context.Context) error {
 	db := sys.R.Database
 
-	_, err := db.ExecContext(ctx, schema)
+	schema, err := creates.ReadFile(path.Join("sql/create", sys.Configs.Database.Dialect+".sql"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("no schema for database dialect %q", sys.Configs.Database.Dialect)
+	}
 	if err != nil {
-		return errors.New("create schema: " + err.Error())
+		return fmt.Errorf("failed to read schema: %w", err)
+	}
+
+	for _, stmt := range statements(string(schema)) {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return errors.New("create schema: " + err.Error())
+		}
 	}
 
 	return nil
@@ -9,9 +9,10 @@ import (
 func Drop(ctx context.Context) error {
 	db := sys.R.Database
 
-	_, err := db.ExecContext(ctx, dropSchema)
-	if err != nil {
-		return errors.New("drop schema: " + err.Error())
+	for _, stmt := range statements(dropSchema) {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return errors.New("drop schema: " + err.Error())
+		}
 	}
 
 	return nil
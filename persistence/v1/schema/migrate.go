@@ -0,0 +1,107 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"github.com/ribgsilva/note-api/sys"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+//go:embed sql/migrations
+var migrations embed.FS
+
+// migrateLockTimeout is how long Migrate waits for another instance migrating the database
+const migrateLockTimeout = time.Minute
+
+// Migrate applies, in order, the migrations of the configured database dialect that were not applied yet,
+// returning how many were applied. Each applied migration is recorded in the schema_migrations table.
+// On MySQL it holds a named lock while migrating, so replicas starting together apply each migration once
+func Migrate(ctx context.Context) (int, error) {
+	db, err := sys.R.Database.Conn(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get a connection: %w", err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+	if sys.Configs.Database.Dialect == "mysql" {
+		var locked sql.NullInt64
+		if err := db.QueryRowContext(ctx, "SELECT GET_LOCK('schema_migrations', ?)", migrateLockTimeout.Seconds()).Scan(&locked); err != nil {
+			return 0, fmt.Errorf("failed to lock migrations: %w", err)
+		}
+		if locked.Int64 != 1 {
+			return 0, errors.New("failed to lock migrations: timed out")
+		}
+		defer func() {
+			_, _ = db.ExecContext(context.Background(), "SELECT RELEASE_LOCK('schema_migrations')")
+		}()
+	}
+
+	dir := path.Join("sql/migrations", sys.Configs.Database.Dialect)
+	entries, err := fs.ReadDir(migrations, dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, fmt.Errorf("no migrations for database dialect %q", sys.Configs.Database.Dialect)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read migrations: %w", err)
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".sql") {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+
+	if _, err := db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS schema_migrations(version VARCHAR(100) PRIMARY KEY, appliedAt DATETIME)"); err != nil {
+		return 0, errors.New("create migrations table: " + err.Error())
+	}
+
+	applied := make(map[string]bool)
+	rows, err := db.QueryContext(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return 0, fmt.Errorf("failed to query applied migrations: %w", err)
+	}
+	for rows.Next() {
+		var version string
+		if err := rows.Scan(&version); err != nil {
+			_ = rows.Close()
+			return 0, fmt.Errorf("error parsing db data: %w", err)
+		}
+		applied[version] = true
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+
+	count := 0
+	for _, name := range names {
+		version := strings.TrimSuffix(name, ".sql")
+		if applied[version] {
+			continue
+		}
+		file, err := migrations.ReadFile(path.Join(dir, name))
+		if err != nil {
+			return count, fmt.Errorf("failed to read migration %s: %w", version, err)
+		}
+		// DDL is not transactional in MySQL, so each statement is applied on its own
+		for _, stmt := range statements(string(file)) {
+			if _, err := db.ExecContext(ctx, stmt); err != nil {
+				return count, fmt.Errorf("migration %s: %w", version, err)
+			}
+		}
+		if _, err := db.ExecContext(ctx, "INSERT INTO schema_migrations (version, appliedAt) VALUES (?, ?)", version, time.Now().UTC()); err != nil {
+			return count, fmt.Errorf("failed to record migration %s: %w", version, err)
+		}
+		count++
+	}
+	return count, nil
+}
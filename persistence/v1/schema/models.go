@@ -1,9 +1,43 @@
 package schema
 
-import _ "embed"
+import (
+	"embed"
+	"strings"
+)
 
-//go:embed sql/create.sql
-var schema string
+//go:embed sql/create
+var creates embed.FS
 
 //go:embed sql/drop.sql
 var dropSchema string
+
+// statements splits a sql file into its statements, as the driver runs one statement per exec.
+// Statements end with a ";" at the end of a line, except inside BEGIN ... END blocks, as in triggers
+func statements(file string) []string {
+	var stmts []string
+	var stmt strings.Builder
+	block := false
+	flush := func() {
+		if s := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(stmt.String()), ";")); s != "" {
+			stmts = append(stmts, s)
+		}
+		stmt.Reset()
+	}
+	for _, line := range strings.Split(file, "\n") {
+		stmt.WriteString(line)
+		stmt.WriteString("\n")
+
+		trimmed := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case !block && strings.HasSuffix(trimmed, "BEGIN"):
+			block = true
+		case block && (trimmed == "END;" || trimmed == "END"):
+			block = false
+			flush()
+		case !block && strings.HasSuffix(trimmed, ";"):
+			flush()
+		}
+	}
+	flush()
+	return stmts
+}
@@ -1,7 +0,0 @@
-CREATE TABLE IF NOT EXISTS notes(
-    id BIGINT AUTO_INCREMENT PRIMARY KEY,
-    title VARCHAR(100),
-    notes TEXT,
-    updatedAt DATETIME,
-    createdAt DATETIME
-)
\ No newline at end of file
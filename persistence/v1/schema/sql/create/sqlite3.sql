@@ -0,0 +1,59 @@
+CREATE TABLE IF NOT EXISTS notes(
+    id INTEGER PRIMARY KEY,
+    title VARCHAR(100),
+    notes TEXT,
+    updatedAt DATETIME,
+    createdAt DATETIME
+);
+
+CREATE TABLE IF NOT EXISTS revisions(
+    id INTEGER PRIMARY KEY,
+    noteId BIGINT NOT NULL,
+    revision BIGINT NOT NULL,
+    title VARCHAR(100),
+    notes TEXT,
+    author VARCHAR(100),
+    createdAt DATETIME,
+    CONSTRAINT revisions_note_revision UNIQUE (noteId, revision)
+);
+
+CREATE TABLE IF NOT EXISTS tags(
+    id INTEGER PRIMARY KEY,
+    name VARCHAR(50) NOT NULL,
+    CONSTRAINT tags_name UNIQUE (name)
+);
+
+CREATE TABLE IF NOT EXISTS note_tags(
+    noteId BIGINT NOT NULL,
+    tagId BIGINT NOT NULL,
+    PRIMARY KEY (noteId, tagId)
+);
+
+CREATE TABLE IF NOT EXISTS notebooks(
+    id INTEGER PRIMARY KEY,
+    parentId BIGINT NULL,
+    name VARCHAR(100) NOT NULL,
+    path VARCHAR(1000) NOT NULL,
+    updatedAt DATETIME,
+    createdAt DATETIME
+);
+
+CREATE TABLE IF NOT EXISTS outbox(
+    id INTEGER PRIMARY KEY,
+    type VARCHAR(100) NOT NULL,
+    noteId BIGINT NOT NULL,
+    author VARCHAR(100) NOT NULL DEFAULT '',
+    payload TEXT NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    lastError VARCHAR(1000) NULL,
+    createdAt DATETIME,
+    sentAt DATETIME NULL
+);
+
+CREATE INDEX IF NOT EXISTS outbox_pending ON outbox (sentAt, id);
+
+CREATE TABLE IF NOT EXISTS note_imports(
+    hash CHAR(64) PRIMARY KEY,
+    noteId BIGINT NOT NULL,
+    createdAt DATETIME
+)
\ No newline at end of file
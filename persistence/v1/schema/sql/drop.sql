@@ -1 +1,17 @@
+DROP TABLE IF EXISTS notes_fts;
+
+DROP TABLE IF EXISTS schema_migrations;
+
+DROP TABLE note_imports;
+
+DROP TABLE outbox;
+
+DROP TABLE notebooks;
+
+DROP TABLE note_tags;
+
+DROP TABLE tags;
+
+DROP TABLE revisions;
+
 DROP TABLE notes
\ No newline at end of file
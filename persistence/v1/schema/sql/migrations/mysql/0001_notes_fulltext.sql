@@ -0,0 +1 @@
+ALTER TABLE notes ADD FULLTEXT INDEX notes_fulltext (title, notes)
@@ -0,0 +1 @@
+ALTER TABLE notes ADD COLUMN deletedAt DATETIME NULL
\ No newline at end of file
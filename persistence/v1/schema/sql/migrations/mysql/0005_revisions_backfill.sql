@@ -0,0 +1,3 @@
+INSERT INTO revisions (noteId, revision, title, notes, author, createdAt)
+SELECT n.id, 1, n.title, n.notes, '', COALESCE(n.updatedAt, n.createdAt) FROM notes n
+WHERE NOT EXISTS (SELECT 1 FROM revisions r WHERE r.noteId = n.id)
@@ -0,0 +1,16 @@
+CREATE VIRTUAL TABLE IF NOT EXISTS notes_fts USING fts5(title, notes, content='notes', content_rowid='id');
+
+CREATE TRIGGER IF NOT EXISTS notes_fts_insert AFTER INSERT ON notes BEGIN
+    INSERT INTO notes_fts(rowid, title, notes) VALUES (new.id, new.title, new.notes);
+END;
+
+CREATE TRIGGER IF NOT EXISTS notes_fts_delete AFTER DELETE ON notes BEGIN
+    INSERT INTO notes_fts(notes_fts, rowid, title, notes) VALUES ('delete', old.id, old.title, old.notes);
+END;
+
+CREATE TRIGGER IF NOT EXISTS notes_fts_update AFTER UPDATE OF title, notes ON notes BEGIN
+    INSERT INTO notes_fts(notes_fts, rowid, title, notes) VALUES ('delete', old.id, old.title, old.notes);
+    INSERT INTO notes_fts(rowid, title, notes) VALUES (new.id, new.title, new.notes);
+END;
+
+INSERT INTO notes_fts(notes_fts) VALUES ('rebuild')
@@ -0,0 +1,3 @@
+ALTER TABLE notes ADD COLUMN notebookId BIGINT NULL;
+
+CREATE INDEX notes_notebook ON notes (notebookId)
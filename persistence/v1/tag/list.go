@@ -0,0 +1,43 @@
+package tag
+
+import (
+	"context"
+	"fmt"
+	"github.com/ribgsilva/note-api/sys"
+)
+
+// List returns the tags in use, with how many notes out of the trash have each of them
+func List(ctx context.Context) ([]Tag, error) {
+	db := sys.R.Database
+
+	dbCtx, dbCancel := context.WithTimeout(ctx, sys.Configs.Database.OperationTimeout)
+	defer dbCancel()
+	stmt, err := db.PrepareContext(dbCtx, "SELECT t.name, COUNT(*) FROM tags t JOIN note_tags nt ON nt.tagId = t.id JOIN notes n ON n.id = nt.noteId WHERE n.deletedAt IS NULL GROUP BY t.name ORDER BY COUNT(*) DESC, t.name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare list tags stmt: %w", err)
+	}
+	defer func() {
+		_ = stmt.Close()
+	}()
+
+	rows, err := stmt.QueryContext(dbCtx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query list tags stmt: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	tags := make([]Tag, 0)
+	for rows.Next() {
+		var t Tag
+		if err := rows.Scan(&t.Name, &t.Count); err != nil {
+			return nil, fmt.Errorf("error parsing db data: %w", err)
+		}
+		tags = append(tags, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read tags: %w", err)
+	}
+	return tags, nil
+}
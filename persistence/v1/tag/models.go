@@ -0,0 +1,6 @@
+package tag
+
+type Tag struct {
+	Name  string
+	Count int64
+}
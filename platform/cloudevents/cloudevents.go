@@ -0,0 +1,156 @@
+package cloudevents
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SpecVersion is the version of the CloudEvents specification supported
+const SpecVersion = "1.0"
+
+// Modes of carrying an event in a message
+const (
+	Structured = "structured"
+	Binary     = "binary"
+)
+
+const (
+	// ContentType is the content type of structured events
+	ContentType = "application/cloudevents+json"
+	// JSONContentType is the content type of JSON data
+	JSONContentType = "application/json"
+
+	// metadataPrefix prefixes the attributes of binary events in the metadata
+	metadataPrefix      = "ce-"
+	contentTypeMetadata = "content-type"
+)
+
+var (
+	// ErrNotCloudEvent is returned when decoding a message that has no CloudEvent in it
+	ErrNotCloudEvent = errors.New("message is not a cloud event")
+	// ErrInvalid is returned when decoding a CloudEvent missing required attributes or of another spec version
+	ErrInvalid = errors.New("invalid cloud event")
+)
+
+// Event is a CloudEvent (https://cloudevents.io). In messages it is either structured, with the whole event as JSON
+// in the body, or binary, with the attributes in the metadata and the data in the body
+type Event struct {
+	Id              string          `json:"id"`
+	Source          string          `json:"source"`
+	SpecVersion     string          `json:"specversion"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            *time.Time      `json:"time,omitempty"`
+	DataContentType string          `json:"datacontenttype,omitempty"`
+	Data            json.RawMessage `json:"data,omitempty"`
+}
+
+// New creates an event with a random id happening now, with data as JSON
+func New(source, eventType, subject string, data any) (Event, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return Event{}, fmt.Errorf("failed to generate event id: %w", err)
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return Event{}, fmt.Errorf("failed to parse event data: %w", err)
+	}
+	now := time.Now().UTC()
+	return Event{
+		Id:              hex.EncodeToString(b),
+		Source:          source,
+		SpecVersion:     SpecVersion,
+		Type:            eventType,
+		Subject:         subject,
+		Time:            &now,
+		DataContentType: JSONContentType,
+		Data:            raw,
+	}, nil
+}
+
+// Encode writes an event as the body and metadata of a message, in the structured or binary mode
+func Encode(e Event, mode string) ([]byte, map[string]string, error) {
+	switch mode {
+	case Structured:
+		body, err := json.Marshal(e)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse cloud event: %w", err)
+		}
+		return body, map[string]string{contentTypeMetadata: ContentType}, nil
+	case Binary:
+		metadata := map[string]string{
+			metadataPrefix + "id":          e.Id,
+			metadataPrefix + "source":      e.Source,
+			metadataPrefix + "specversion": e.SpecVersion,
+			metadataPrefix + "type":        e.Type,
+		}
+		if e.Subject != "" {
+			metadata[metadataPrefix+"subject"] = e.Subject
+		}
+		if e.Time != nil {
+			metadata[metadataPrefix+"time"] = e.Time.Format(time.RFC3339Nano)
+		}
+		if e.DataContentType != "" {
+			metadata[contentTypeMetadata] = e.DataContentType
+		}
+		return e.Data, metadata, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown cloud event mode: %s", mode)
+	}
+}
+
+// Decode reads an event from the body and metadata of a message, in any mode.
+// It returns ErrNotCloudEvent when the message has no event in it, and ErrInvalid when the event is not valid
+func Decode(body []byte, metadata map[string]string) (Event, error) {
+	var e Event
+	switch {
+	case metadata[metadataPrefix+"specversion"] != "":
+		e = Event{
+			Id:              metadata[metadataPrefix+"id"],
+			Source:          metadata[metadataPrefix+"source"],
+			SpecVersion:     metadata[metadataPrefix+"specversion"],
+			Type:            metadata[metadataPrefix+"type"],
+			Subject:         metadata[metadataPrefix+"subject"],
+			DataContentType: metadata[contentTypeMetadata],
+			Data:            body,
+		}
+		if t := metadata[metadataPrefix+"time"]; t != "" {
+			parsed, err := time.Parse(time.RFC3339Nano, t)
+			if err != nil {
+				return Event{}, fmt.Errorf("%w: time: %s", ErrInvalid, err)
+			}
+			e.Time = &parsed
+		}
+	case strings.HasPrefix(metadata[contentTypeMetadata], ContentType) || structured(body):
+		if err := json.Unmarshal(body, &e); err != nil {
+			return Event{}, fmt.Errorf("%w: %s", ErrInvalid, err)
+		}
+	default:
+		return Event{}, ErrNotCloudEvent
+	}
+
+	switch {
+	case e.SpecVersion != SpecVersion:
+		return Event{}, fmt.Errorf("%w: unsupported spec version %q", ErrInvalid, e.SpecVersion)
+	case e.Id == "" || e.Source == "" || e.Type == "":
+		return Event{}, fmt.Errorf("%w: id, source and type are required", ErrInvalid)
+	}
+	return e, nil
+}
+
+// structured tells if a body is a JSON object with the specversion attribute, for messages without a content type
+func structured(body []byte) bool {
+	if !bytes.HasPrefix(bytes.TrimSpace(body), []byte("{")) {
+		return false
+	}
+	var attributes struct {
+		SpecVersion *string `json:"specversion"`
+	}
+	return json.Unmarshal(body, &attributes) == nil && attributes.SpecVersion != nil
+}
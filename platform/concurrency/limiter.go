@@ -0,0 +1,128 @@
+package concurrency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter bounds how many operations run at once. An adaptive limiter moves its limit between min and max AIMD-style:
+// every interval it is halved when the operations were slower than the target latency on average or failed above the
+// max error rate, and raised by one when they kept every slot busy
+type Limiter struct {
+	min          int
+	max          int
+	target       time.Duration
+	maxErrorRate float64
+	interval     time.Duration
+
+	mu        sync.Mutex
+	changed   chan struct{}
+	limit     int
+	inFlight  int
+	saturated bool
+	window    time.Time
+	count     int
+	failures  int
+	latency   time.Duration
+}
+
+// Fixed creates a limiter that always lets n operations run at once
+func Fixed(n int) *Limiter {
+	return New(n, n, 0, 0, 0)
+}
+
+// New creates an adaptive limiter starting at min, adjusted at most once every interval
+func New(min, max int, target time.Duration, maxErrorRate float64, interval time.Duration) *Limiter {
+	if min < 1 {
+		min = 1
+	}
+	if max < min {
+		max = min
+	}
+	return &Limiter{
+		min:          min,
+		max:          max,
+		target:       target,
+		maxErrorRate: maxErrorRate,
+		interval:     interval,
+		changed:      make(chan struct{}),
+		limit:        min,
+		window:       time.Now(),
+	}
+}
+
+// Acquire waits for a free slot, failing when ctx is done first. Every Acquire must be followed by a Release
+func (l *Limiter) Acquire(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		if l.inFlight < l.limit {
+			l.inFlight++
+			if l.inFlight == l.limit {
+				l.saturated = true
+			}
+			l.mu.Unlock()
+			return nil
+		}
+		changed := l.changed
+		l.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Release frees a slot, recording how long the operation took and whether it failed
+func (l *Limiter) Release(latency time.Duration, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.inFlight--
+	l.count++
+	l.latency += latency
+	if err != nil {
+		l.failures++
+	}
+	if l.min != l.max && time.Since(l.window) >= l.interval {
+		l.adjust()
+	}
+
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
+
+// adjust moves the limit on the operations of the window and starts another one
+func (l *Limiter) adjust() {
+	average := l.latency / time.Duration(l.count)
+	errorRate := float64(l.failures) / float64(l.count)
+	switch {
+	case average > l.target || errorRate > l.maxErrorRate:
+		l.limit /= 2
+		if l.limit < l.min {
+			l.limit = l.min
+		}
+	case l.saturated && l.limit < l.max:
+		l.limit++
+	}
+
+	l.window = time.Now()
+	l.saturated = l.inFlight >= l.limit
+	l.count, l.failures, l.latency = 0, 0, 0
+}
+
+// Limit returns how many operations can run at once
+func (l *Limiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
+
+// InFlight returns how many operations are running
+func (l *Limiter) InFlight() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.inFlight
+}
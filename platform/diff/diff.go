@@ -0,0 +1,111 @@
+package diff
+
+import (
+	"errors"
+	"strings"
+)
+
+type Op string
+
+const (
+	Equal  Op = "equal"
+	Insert Op = "insert"
+	Delete Op = "delete"
+)
+
+// MaxLines is how many changed lines each text may have, after the lines both start and end with.
+// The longest common subsequence table grows with the product of the changed lines of both texts
+const MaxLines = 2000
+
+// ErrTooLarge is returned when the texts have more than MaxLines changed lines
+var ErrTooLarge = errors.New("too many changed lines to compare")
+
+// Line is a line of the diff, telling if it was kept, inserted or deleted
+type Line struct {
+	Op   Op     `json:"op" example:"insert"`
+	Text string `json:"text" example:"my note text"`
+}
+
+// Lines compares two texts line by line, using the longest common subsequence of the lines between the ones
+// both texts start and end with. It returns ErrTooLarge when any of the texts has more than MaxLines changed lines
+func Lines(from, to string) ([]Line, error) {
+	a := split(from)
+	b := split(to)
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]Line, 0, len(a)+len(b))
+	for _, l := range a[:prefix] {
+		lines = append(lines, Line{Op: Equal, Text: l})
+	}
+	changed, err := lcsLines(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])
+	if err != nil {
+		return nil, err
+	}
+	lines = append(lines, changed...)
+	for _, l := range a[len(a)-suffix:] {
+		lines = append(lines, Line{Op: Equal, Text: l})
+	}
+	return lines, nil
+}
+
+// lcsLines diffs the lines using their longest common subsequence
+func lcsLines(a, b []string) ([]Line, error) {
+	if len(a) > MaxLines || len(b) > MaxLines {
+		return nil, ErrTooLarge
+	}
+
+	// lcs[i*width+j] holds the size of the longest common subsequence of a[i:] and b[j:]
+	width := len(b) + 1
+	lcs := make([]int32, (len(a)+1)*width)
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i*width+j] = lcs[(i+1)*width+j+1] + 1
+			case lcs[(i+1)*width+j] >= lcs[i*width+j+1]:
+				lcs[i*width+j] = lcs[(i+1)*width+j]
+			default:
+				lcs[i*width+j] = lcs[i*width+j+1]
+			}
+		}
+	}
+
+	lines := make([]Line, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, Line{Op: Equal, Text: a[i]})
+			i++
+			j++
+		case lcs[(i+1)*width+j] >= lcs[i*width+j+1]:
+			lines = append(lines, Line{Op: Delete, Text: a[i]})
+			i++
+		default:
+			lines = append(lines, Line{Op: Insert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, Line{Op: Delete, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, Line{Op: Insert, Text: b[j]})
+	}
+	return lines, nil
+}
+
+func split(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
@@ -0,0 +1,13 @@
+package dispatch
+
+import "time"
+
+// DeadLetter is a message that could not be handled, as sent to the dead-letter topic, with why it failed.
+// It keeps the original body and metadata, so the message can be handled again once fixed
+type DeadLetter struct {
+	Reason   string            `json:"reason"`
+	Report   []string          `json:"report,omitempty"`
+	Body     string            `json:"body"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	FailedAt time.Time         `json:"failedAt"`
+}
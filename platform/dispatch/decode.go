@@ -0,0 +1,46 @@
+package dispatch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ribgsilva/note-api/platform/cloudevents"
+)
+
+// versionKey is the message metadata holding the version of the event type
+const versionKey = "version"
+
+// envelope is the legacy {type, version, data} format of the messages, kept along with CloudEvents
+type envelope struct {
+	Type    string          `json:"type"`
+	Version string          `json:"version"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// Decode reads the type, version and data of a message, either a CloudEvent in any mode or the legacy envelope.
+// The version comes from the metadata or the envelope, and is DefaultVersion when neither has it
+func Decode(body []byte, metadata map[string]string) (Message, error) {
+	m := Message{Version: metadata[versionKey], Metadata: metadata}
+
+	e, err := cloudevents.Decode(body, metadata)
+	switch {
+	case err == nil:
+		m.Type, m.Data = e.Type, e.Data
+	case !errors.Is(err, cloudevents.ErrNotCloudEvent):
+		return Message{}, err
+	default:
+		var env envelope
+		if err := json.Unmarshal(body, &env); err != nil {
+			return Message{}, fmt.Errorf("failed to parse body: %w", err)
+		}
+		m.Type, m.Data = env.Type, env.Data
+		if m.Version == "" {
+			m.Version = env.Version
+		}
+	}
+
+	if m.Version == "" {
+		m.Version = DefaultVersion
+	}
+	return m, nil
+}
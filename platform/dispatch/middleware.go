@@ -0,0 +1,63 @@
+package dispatch
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"go.uber.org/zap"
+	"runtime/debug"
+	"time"
+)
+
+// metrics per message type, served by the expvar handler
+var (
+	handled  = expvar.NewMap("messages_handled")
+	failed   = expvar.NewMap("messages_failed")
+	duration = expvar.NewMap("messages_duration_seconds")
+)
+
+// Logging logs every message handled, with how long it took and why it failed
+func Logging(log *zap.SugaredLogger) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, m Message) error {
+			start := time.Now()
+			err := next(ctx, m)
+			if err != nil {
+				log.Errorw("message failed", "type", m.Type, "version", m.Version, "duration", time.Since(start), "data", string(m.Data), "ERROR", err)
+			} else {
+				log.Infow("message handled", "type", m.Type, "version", m.Version, "duration", time.Since(start))
+			}
+			return err
+		}
+	}
+}
+
+// Metrics counts the messages handled and failed per type, and the time spent handling them
+func Metrics() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, m Message) error {
+			start := time.Now()
+			err := next(ctx, m)
+			handled.Add(m.Type, 1)
+			if err != nil {
+				failed.Add(m.Type, 1)
+			}
+			duration.AddFloat(m.Type, time.Since(start).Seconds())
+			return err
+		}
+	}
+}
+
+// Recover turns a panic of the handler into an error, so a bad message does not stop the whole consumer
+func Recover() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, m Message) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("%w handling %s: %v\n%s", ErrPanic, m.Type, r, debug.Stack())
+				}
+			}()
+			return next(ctx, m)
+		}
+	}
+}
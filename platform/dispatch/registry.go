@@ -0,0 +1,94 @@
+package dispatch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrUnknownType is returned when dispatching a message without a handler for its type and version
+	ErrUnknownType = errors.New("no handler for the message type")
+	// ErrDecode is returned when the data of a message does not fit the payload of its handler
+	ErrDecode = errors.New("failed to decode message data")
+	// ErrPanic is returned when a handler panics, recovered by the Recover middleware
+	ErrPanic = errors.New("panic")
+)
+
+// DefaultVersion is the version of messages that do not tell theirs
+const DefaultVersion = "1"
+
+// Message is an event to be handled, with its data still encoded
+type Message struct {
+	Type     string
+	Version  string
+	Data     json.RawMessage
+	Metadata map[string]string
+}
+
+// Handler handles a message. Errors are reported by the middleware
+type Handler func(ctx context.Context, m Message) error
+
+// Middleware wraps a handler, to run code around every message
+type Middleware func(next Handler) Handler
+
+type key struct {
+	eventType string
+	version   string
+}
+
+// Registry dispatches messages to the handler registered for their type and version
+type Registry struct {
+	strict     bool
+	handlers   map[key]Handler
+	middleware []Middleware
+}
+
+// NewRegistry creates an empty registry. When strict, data with fields the payload does not have is rejected
+func NewRegistry(strict bool) *Registry {
+	return &Registry{
+		strict:   strict,
+		handlers: map[key]Handler{},
+	}
+}
+
+// Use adds middleware to every handler, the first one added being the outermost
+func (r *Registry) Use(middleware ...Middleware) {
+	r.middleware = append(r.middleware, middleware...)
+}
+
+// Register adds the handler of a type and version, receiving the message data decoded as T
+func Register[T any](r *Registry, eventType, version string, h func(ctx context.Context, payload T, m Message) error) {
+	r.handlers[key{eventType, version}] = func(ctx context.Context, m Message) error {
+		var payload T
+		decoder := json.NewDecoder(bytes.NewReader(m.Data))
+		if r.strict {
+			decoder.DisallowUnknownFields()
+		}
+		if err := decoder.Decode(&payload); err != nil {
+			return fmt.Errorf("%w: %s", ErrDecode, err)
+		}
+		return h(ctx, payload, m)
+	}
+}
+
+// Dispatch runs the handler of the message type and version through the middleware.
+// A message without a version is handled as DefaultVersion
+func (r *Registry) Dispatch(ctx context.Context, m Message) error {
+	if m.Version == "" {
+		m.Version = DefaultVersion
+	}
+
+	h, ok := r.handlers[key{m.Type, m.Version}]
+	if !ok {
+		h = func(context.Context, Message) error {
+			return fmt.Errorf("%w: %s version %s", ErrUnknownType, m.Type, m.Version)
+		}
+	}
+	for i := len(r.middleware) - 1; i >= 0; i-- {
+		h = r.middleware[i](h)
+	}
+	return h(ctx, m)
+}
@@ -0,0 +1,16 @@
+package dispatch
+
+// Metadata of the messages asking for a reply: the url of the topic to reply to, and the id to correlate the reply with
+const (
+	ReplyToKey       = "replyTo"
+	CorrelationIdKey = "correlationId"
+)
+
+// Reply is the result of handling a message, sent to its reply topic with the correlation id of the message.
+// Id is the note created or changed, and Error why the message failed
+type Reply struct {
+	CorrelationId string `json:"correlationId,omitempty"`
+	Type          string `json:"type"`
+	Id            uint64 `json:"id,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
@@ -0,0 +1,16 @@
+package env
+
+import (
+	"go.uber.org/zap"
+	"strconv"
+)
+
+// FloatDefault return the result of searching an env var, if the env var value is empty, return a default value as float
+func FloatDefault(log *zap.SugaredLogger, env, def string) float64 {
+	orDefault := OrDefault(log, env, def)
+	value, err := strconv.ParseFloat(orDefault, 64)
+	if err != nil {
+		log.Warn("error parsing ", orDefault, "as float: ", err)
+	}
+	return value
+}
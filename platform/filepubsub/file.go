@@ -0,0 +1,230 @@
+package filepubsub
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/pubsub"
+	"gocloud.dev/pubsub/driver"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// Schemes of the urls opening subscriptions: file:///path/to/messages.jsonl reads a file, and stdin:// the standard input
+const (
+	FileScheme  = "file"
+	StdinScheme = "stdin"
+)
+
+func init() {
+	opener := &URLOpener{}
+	pubsub.DefaultURLMux().RegisterSubscription(FileScheme, opener)
+	pubsub.DefaultURLMux().RegisterSubscription(StdinScheme, opener)
+}
+
+// URLOpener opens file and stdin subscriptions
+type URLOpener struct{}
+
+// OpenSubscriptionURL opens the file in the url path, or the standard input for the stdin scheme
+func (*URLOpener) OpenSubscriptionURL(_ context.Context, u *url.URL) (*pubsub.Subscription, error) {
+	if u.Scheme == StdinScheme {
+		return NewSubscription(os.Stdin), nil
+	}
+
+	f, err := os.Open(u.Path)
+	if err != nil {
+		return nil, fmt.Errorf("open file subscription %s: %w", u, err)
+	}
+	return NewSubscription(f), nil
+}
+
+// Message is a line of the input when it has a body, as captured from another driver.
+// Other lines are taken as the whole body of a message without metadata
+type Message struct {
+	Body     json.RawMessage   `json:"body"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// NewSubscription receives the messages written one per line in r, closing it when the subscription is shut down.
+// Receive returns io.EOF once every message was received and acked
+func NewSubscription(r io.Reader) *pubsub.Subscription {
+	s := &subscription{
+		lines: make(chan []byte),
+		done:  make(chan struct{}),
+		acked: make(chan struct{}, 1),
+	}
+	if c, ok := r.(io.Closer); ok {
+		s.closer = c
+	}
+	go s.read(r)
+	return pubsub.NewSubscription(s, nil, nil)
+}
+
+type subscription struct {
+	lines   chan []byte
+	done    chan struct{}
+	acked   chan struct{}
+	closer  io.Closer
+	readErr error
+
+	mu      sync.Mutex
+	next    int
+	pending int
+}
+
+// read sends the lines of r until it ends, so receiving can be cancelled while waiting for the input
+func (s *subscription) read(r io.Reader) {
+	defer close(s.lines)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := append([]byte(nil), scanner.Bytes()...)
+		if len(line) == 0 {
+			continue
+		}
+		select {
+		case s.lines <- line:
+		case <-s.done:
+			return
+		}
+	}
+	s.readErr = scanner.Err()
+}
+
+// Parse reads a line, as a captured message when it has a body, or as the body itself
+func Parse(line []byte) Message {
+	var m Message
+	if err := json.Unmarshal(line, &m); err == nil && len(m.Body) > 0 {
+		// string bodies are taken as text, other values as their JSON
+		var text string
+		if err := json.Unmarshal(m.Body, &text); err == nil {
+			m.Body = []byte(text)
+		}
+		return m
+	}
+	return Message{Body: line}
+}
+
+// ReceiveBatch implements driver.Subscription.ReceiveBatch. At the end of the input it waits for the received
+// messages to be acked before returning io.EOF, as the portable subscription drops the messages not received
+// yet once the driver fails
+func (s *subscription) ReceiveBatch(ctx context.Context, maxMessages int) ([]*driver.Message, error) {
+	var msgs []*driver.Message
+	for len(msgs) < maxMessages {
+		var line []byte
+		var ok bool
+		if len(msgs) == 0 {
+			select {
+			case line, ok = <-s.lines:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		} else {
+			select {
+			case line, ok = <-s.lines:
+			default:
+				return msgs, nil
+			}
+		}
+
+		if !ok {
+			if len(msgs) > 0 {
+				return msgs, nil
+			}
+			return nil, s.end(ctx)
+		}
+
+		m := Parse(line)
+
+		s.mu.Lock()
+		s.next++
+		s.pending++
+		id := s.next
+		s.mu.Unlock()
+
+		msgs = append(msgs, &driver.Message{
+			LoggableID: strconv.Itoa(id),
+			Body:       m.Body,
+			Metadata:   m.Metadata,
+			AckID:      id,
+		})
+	}
+	return msgs, nil
+}
+
+// end waits for the pending messages to be acked and returns why the input ended
+func (s *subscription) end(ctx context.Context) error {
+	for {
+		s.mu.Lock()
+		pending := s.pending
+		s.mu.Unlock()
+		if pending == 0 {
+			break
+		}
+
+		select {
+		case <-s.acked:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if s.readErr != nil {
+		return s.readErr
+	}
+	return io.EOF
+}
+
+// SendAcks implements driver.Subscription.SendAcks
+func (s *subscription) SendAcks(_ context.Context, ackIDs []driver.AckID) error {
+	s.mu.Lock()
+	s.pending -= len(ackIDs)
+	s.mu.Unlock()
+
+	select {
+	case s.acked <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// CanNack implements driver.Subscription.CanNack. Messages can not be read again, so they can not be nacked
+func (*subscription) CanNack() bool { return false }
+
+// SendNacks implements driver.Subscription.SendNacks
+func (*subscription) SendNacks(context.Context, []driver.AckID) error {
+	return errors.New("nacks are not supported")
+}
+
+// IsRetryable implements driver.Subscription.IsRetryable
+func (*subscription) IsRetryable(error) bool { return false }
+
+// As implements driver.Subscription.As
+func (*subscription) As(interface{}) bool { return false }
+
+// ErrorAs implements driver.Subscription.ErrorAs
+func (*subscription) ErrorAs(error, interface{}) bool { return false }
+
+// ErrorCode implements driver.Subscription.ErrorCode
+func (*subscription) ErrorCode(err error) gcerrors.ErrorCode {
+	if errors.Is(err, io.EOF) {
+		return gcerrors.NotFound
+	}
+	return gcerrors.Unknown
+}
+
+// Close implements driver.Subscription.Close
+func (s *subscription) Close() error {
+	close(s.done)
+	if s.closer != nil {
+		return s.closer.Close()
+	}
+	return nil
+}
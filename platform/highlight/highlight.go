@@ -0,0 +1,87 @@
+package highlight
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+const (
+	Pre      = "<mark>"
+	Post     = "</mark>"
+	Ellipsis = "…"
+)
+
+// span is the position of a word in a text
+type span struct {
+	start, end int
+}
+
+// Snippet returns a window of up to size words of the text, starting a few words before the first word matched.
+// Matched words are wrapped in Pre and Post, and the rest of the text is HTML escaped. Words are passed lower cased to match
+func Snippet(text string, match func(word string) bool, size int) string {
+	words := split(text)
+	if len(words) == 0 {
+		return ""
+	}
+
+	first := 0
+	for i, w := range words {
+		if match(strings.ToLower(text[w.start:w.end])) {
+			first = i
+			break
+		}
+	}
+	// start a few words before the first match, but fill the window when the text ends before it
+	from := first - size/4
+	if last := len(words) - size; from > last {
+		from = last
+	}
+	if from < 0 || size <= 0 {
+		from = 0
+	}
+	to := len(words)
+	if size > 0 && from+size < to {
+		to = from + size
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString(Ellipsis)
+	}
+	pos := words[from].start
+	for _, w := range words[from:to] {
+		b.WriteString(html.EscapeString(text[pos:w.start]))
+		word := html.EscapeString(text[w.start:w.end])
+		if match(strings.ToLower(text[w.start:w.end])) {
+			b.WriteString(Pre + word + Post)
+		} else {
+			b.WriteString(word)
+		}
+		pos = w.end
+	}
+	if to < len(words) {
+		b.WriteString(Ellipsis)
+	}
+	return b.String()
+}
+
+// split finds the words of a text, as runs of letters and digits
+func split(text string) []span {
+	var words []span
+	start := -1
+	for i, r := range text {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case isWord && start < 0:
+			start = i
+		case !isWord && start >= 0:
+			words = append(words, span{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		words = append(words, span{start, len(text)})
+	}
+	return words
+}
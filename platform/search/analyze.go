@@ -0,0 +1,62 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+type Language string
+
+const (
+	English    Language = "en"
+	Portuguese Language = "pt"
+)
+
+// ParseLanguage returns the language of a code, as en or pt
+func ParseLanguage(code string) (Language, bool) {
+	switch l := Language(strings.ToLower(code)); l {
+	case English, Portuguese:
+		return l, true
+	}
+	return "", false
+}
+
+// Tokenize splits a text in lower cased words, as runs of letters and digits
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Fold removes the accents of a word, so "ação" and "acao" are the same term
+func Fold(word string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(t, word)
+	if err != nil {
+		return word
+	}
+	return folded
+}
+
+// Stem reduces a lower cased word to its stem in the language
+func Stem(lang Language, word string) string {
+	switch lang {
+	case Portuguese:
+		return Fold(stemPortuguese(word))
+	default:
+		return stemEnglish(Fold(word))
+	}
+}
+
+// Analyze turns a text in the terms of the index, tokenizing and stemming its words
+func Analyze(lang Language, text string) []string {
+	words := Tokenize(text)
+	for i, w := range words {
+		words[i] = Stem(lang, w)
+	}
+	return words
+}
@@ -0,0 +1,196 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// BM25 parameters: k1 saturates the term frequency and b normalizes by the document length
+const (
+	k1 = 1.2
+	b  = 0.75
+)
+
+// Field is a text of a document. Its terms count Weight times, so titles can weigh more than bodies
+type Field struct {
+	Text   string
+	Weight int
+}
+
+// Hit is a document found by a search. The higher the score, the more relevant the document
+type Hit struct {
+	Id    uint64
+	Score float64
+}
+
+type document struct {
+	Terms  map[string]int
+	Length int
+}
+
+// Index is an in-memory inverted index, safe for concurrent use, ranking documents with BM25
+type Index struct {
+	mu       sync.RWMutex
+	lang     Language
+	docs     map[uint64]document
+	postings map[string]map[uint64]int
+	length   int
+}
+
+func New(lang Language) *Index {
+	return &Index{
+		lang:     lang,
+		docs:     make(map[uint64]document),
+		postings: make(map[string]map[uint64]int),
+	}
+}
+
+func (ix *Index) Language() Language {
+	return ix.lang
+}
+
+// Len returns how many documents are indexed
+func (ix *Index) Len() int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return len(ix.docs)
+}
+
+// Add indexes a document, replacing it if it was already indexed
+func (ix *Index) Add(id uint64, fields ...Field) {
+	doc := document{Terms: make(map[string]int)}
+	for _, f := range fields {
+		for _, t := range Analyze(ix.lang, f.Text) {
+			doc.Terms[t] += f.Weight
+			doc.Length += f.Weight
+		}
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.remove(id)
+	ix.add(id, doc)
+}
+
+// Remove takes a document out of the index
+func (ix *Index) Remove(id uint64) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.remove(id)
+}
+
+// Replace swaps the content of the index by the content of another index, as after rebuilding it
+func (ix *Index) Replace(other *Index) {
+	other.mu.RLock()
+	defer other.mu.RUnlock()
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.lang = other.lang
+	ix.docs = other.docs
+	ix.postings = other.postings
+	ix.length = other.length
+}
+
+func (ix *Index) add(id uint64, doc document) {
+	ix.docs[id] = doc
+	ix.length += doc.Length
+	for t, freq := range doc.Terms {
+		p, ok := ix.postings[t]
+		if !ok {
+			p = make(map[uint64]int)
+			ix.postings[t] = p
+		}
+		p[id] = freq
+	}
+}
+
+func (ix *Index) remove(id uint64) {
+	doc, ok := ix.docs[id]
+	if !ok {
+		return
+	}
+	delete(ix.docs, id)
+	ix.length -= doc.Length
+	for t := range doc.Terms {
+		delete(ix.postings[t], id)
+		if len(ix.postings[t]) == 0 {
+			delete(ix.postings, t)
+		}
+	}
+}
+
+// Search returns the documents with all the words, and at least a term starting with each of the prefixes,
+// the most relevant first
+func (ix *Index) Search(words, prefixes []string) []Hit {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	// each group holds the terms that satisfy a word or a prefix
+	var groups [][]string
+	for _, w := range words {
+		for _, t := range Analyze(ix.lang, w) {
+			groups = append(groups, []string{t})
+		}
+	}
+	for _, p := range prefixes {
+		p = Fold(strings.ToLower(p))
+		stem := Stem(ix.lang, p)
+		var terms []string
+		for t := range ix.postings {
+			if strings.HasPrefix(t, p) || t == stem {
+				terms = append(terms, t)
+			}
+		}
+		groups = append(groups, terms)
+	}
+	if len(groups) == 0 || len(ix.docs) == 0 {
+		return nil
+	}
+
+	n := float64(len(ix.docs))
+	avg := float64(ix.length) / n
+	var scores map[uint64]float64
+	for _, g := range groups {
+		found := make(map[uint64]float64)
+		for _, t := range g {
+			p := ix.postings[t]
+			idf := math.Log(1 + (n-float64(len(p))+0.5)/(float64(len(p))+0.5))
+			for id, freq := range p {
+				if scores != nil {
+					if _, ok := scores[id]; !ok {
+						continue
+					}
+				}
+				tf := float64(freq)
+				norm := 1 - b + b*float64(ix.docs[id].Length)/avg
+				found[id] += idf * tf * (k1 + 1) / (tf + k1*norm)
+			}
+		}
+		// documents must match every group
+		if scores != nil {
+			for id, s := range scores {
+				if _, ok := found[id]; ok {
+					found[id] += s
+				}
+			}
+		}
+		scores = found
+		if len(scores) == 0 {
+			return nil
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for id, s := range scores {
+		hits = append(hits, Hit{Id: id, Score: s})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Id > hits[j].Id
+	})
+	return hits
+}
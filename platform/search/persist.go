@@ -0,0 +1,73 @@
+package search
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// formatVersion changes whenever the analysis changes, so old indexes are rebuilt instead of read
+const formatVersion = 1
+
+// ErrIncompatible is returned when loading an index saved by another version or for another language
+var ErrIncompatible = errors.New("incompatible search index")
+
+type snapshot struct {
+	Version  int
+	Language Language
+	Docs     map[uint64]document
+}
+
+// Save writes the index to a file, replacing it only once it is completely written
+func (ix *Index) Save(path string) error {
+	ix.mu.RLock()
+	s := snapshot{Version: formatVersion, Language: ix.lang, Docs: ix.docs}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		ix.mu.RUnlock()
+		return fmt.Errorf("failed to create index file: %w", err)
+	}
+	err = gob.NewEncoder(tmp).Encode(s)
+	ix.mu.RUnlock()
+
+	if cErr := tmp.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write index file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to replace index file: %w", err)
+	}
+	return nil
+}
+
+// Load reads an index saved to a file in the language. It returns ErrIncompatible if the file is of another
+// version or language, and an error wrapping os.ErrNotExist if there is no file
+func Load(path string, lang Language) (*Index, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open index file: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	var s snapshot
+	if err := gob.NewDecoder(f).Decode(&s); err != nil {
+		return nil, fmt.Errorf("failed to read index file: %w", err)
+	}
+	if s.Version != formatVersion || s.Language != lang {
+		return nil, ErrIncompatible
+	}
+
+	ix := New(lang)
+	for id, doc := range s.Docs {
+		ix.add(id, doc)
+	}
+	return ix, nil
+}
@@ -0,0 +1,97 @@
+package search
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// rule replaces a suffix when the remaining stem keeps at least min letters
+type rule struct {
+	suffix, with string
+	min          int
+}
+
+// stemPortuguese is a light version of the RSLP stemmer: it reduces plurals, feminine forms, adverbs,
+// diminutives, noun and verb suffixes, and then the final vowel
+func stemPortuguese(word string) string {
+	if utf8.RuneCountInString(word) <= 3 {
+		return word
+	}
+
+	// verbs in the first person plural are not plurals
+	if strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "mos") && !pluralExceptions[word] {
+		word = apply(word, pluralRules)
+	}
+	if strings.HasSuffix(word, "a") || strings.HasSuffix(word, "ã") {
+		word = apply(word, feminineRules)
+	}
+	word = apply(word, adverbRules)
+	word = apply(word, diminutiveRules)
+	if stem := apply(word, nounRules); stem != word {
+		word = stem
+	} else {
+		word = apply(word, verbRules)
+	}
+	return apply(word, vowelRules)
+}
+
+// apply replaces the first suffix of the rules found in the word, which is why longer suffixes come first
+func apply(word string, rules []rule) string {
+	for _, r := range rules {
+		if !strings.HasSuffix(word, r.suffix) {
+			continue
+		}
+		stem := strings.TrimSuffix(word, r.suffix)
+		if utf8.RuneCountInString(stem) < r.min {
+			return word
+		}
+		return stem + r.with
+	}
+	return word
+}
+
+var pluralExceptions = map[string]bool{
+	"lápis": true, "cais": true, "mais": true, "pois": true, "depois": true, "dois": true, "três": true,
+	"ônibus": true, "vírus": true, "atlas": true, "país": true, "simples": true, "tênis": true,
+}
+
+var pluralRules = []rule{
+	{"ões", "ão", 1}, {"ães", "ão", 1}, {"ais", "al", 1}, {"éis", "el", 2}, {"eis", "el", 2},
+	{"óis", "ol", 2}, {"ns", "m", 1}, {"les", "l", 3}, {"res", "r", 3}, {"is", "il", 2}, {"s", "", 2},
+}
+
+var feminineRules = []rule{
+	{"ona", "ão", 3}, {"ã", "ão", 2}, {"ora", "or", 3}, {"inha", "inho", 3}, {"esa", "ês", 3},
+	{"osa", "oso", 3}, {"íaca", "íaco", 3}, {"ica", "ico", 3}, {"ada", "ado", 2}, {"ida", "ido", 3},
+	{"ída", "ido", 3}, {"ima", "imo", 3}, {"iva", "ivo", 3}, {"eira", "eiro", 3}, {"na", "no", 4},
+}
+
+var adverbRules = []rule{
+	{"mente", "", 4},
+}
+
+var diminutiveRules = []rule{
+	{"íssimo", "", 3}, {"érrimo", "", 4}, {"zinho", "", 2}, {"inho", "", 3}, {"alhão", "", 4},
+	{"zão", "", 2}, {"ão", "", 3},
+}
+
+var nounRules = []rule{
+	{"amentos", "", 3}, {"imentos", "", 3}, {"amento", "", 3}, {"imento", "", 3}, {"mento", "", 6},
+	{"ações", "", 3}, {"ação", "", 3}, {"ções", "", 3}, {"ção", "", 3}, {"idade", "", 4}, {"dade", "", 3},
+	{"ismo", "", 3}, {"ista", "", 4}, {"ável", "", 2}, {"ível", "", 4}, {"ância", "", 4}, {"ência", "", 3},
+	{"eza", "", 3}, {"ante", "", 2}, {"ente", "", 4}, {"ico", "", 4}, {"ivo", "", 4}, {"oso", "", 3},
+	{"ador", "", 3}, {"edor", "", 3}, {"idor", "", 4}, {"eiro", "", 3}, {"ário", "", 3},
+}
+
+var verbRules = []rule{
+	{"aríamos", "", 2}, {"eríamos", "", 3}, {"iríamos", "", 3}, {"ássemos", "", 2}, {"êssemos", "", 2},
+	{"íssemos", "", 3}, {"aremos", "", 2}, {"eremos", "", 2}, {"iremos", "", 3}, {"ávamos", "", 2},
+	{"íamos", "", 3}, {"ando", "", 2}, {"endo", "", 3}, {"indo", "", 3}, {"ondo", "", 3}, {"aram", "", 2},
+	{"eram", "", 3}, {"iram", "", 3}, {"avam", "", 2}, {"aria", "", 3}, {"eria", "", 3}, {"iria", "", 3},
+	{"amos", "", 2}, {"emos", "", 2}, {"imos", "", 3}, {"ado", "", 2}, {"ido", "", 3}, {"ava", "", 2}, {"ar", "", 2}, {"er", "", 2}, {"ir", "", 3},
+	{"ou", "", 3}, {"am", "", 2}, {"em", "", 2}, {"ei", "", 3},
+}
+
+var vowelRules = []rule{
+	{"a", "", 3}, {"e", "", 3}, {"o", "", 3},
+}
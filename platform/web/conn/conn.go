@@ -0,0 +1,57 @@
+package conn
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+type writerKey struct{}
+
+// Handler keeps the response writer of the server in the request context, as the gin one does not unwrap to it
+func Handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), writerKey{}, w)))
+	})
+}
+
+// FullDuplex lets the request body be read after the response started to be written, which HTTP/1 servers
+// otherwise close on the first flush. Requests not served through Handler are left as they are
+func FullDuplex(ctx *gin.Context) error {
+	rc, ok := controller(ctx)
+	if !ok {
+		return nil
+	}
+	return rc.EnableFullDuplex()
+}
+
+// Extend moves the read and write deadlines of the request to read and write from now, for requests taking longer
+// than the server timeouts. Requests not served through Handler keep their deadlines
+func Extend(ctx *gin.Context, read, write time.Duration) error {
+	rc, ok := controller(ctx)
+	if !ok {
+		return nil
+	}
+	now := time.Now()
+	if read > 0 {
+		if err := rc.SetReadDeadline(now.Add(read)); err != nil {
+			return err
+		}
+	}
+	if write > 0 {
+		if err := rc.SetWriteDeadline(now.Add(write)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// controller is the response controller of the server writer of the request
+func controller(ctx *gin.Context) (*http.ResponseController, bool) {
+	w, ok := ctx.Request.Context().Value(writerKey{}).(http.ResponseWriter)
+	if !ok {
+		return nil, false
+	}
+	return http.NewResponseController(w), true
+}
@@ -0,0 +1,42 @@
+package etag
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalid = errors.New("invalid etag")
+
+// Format returns the entity tag of a resource version
+func Format(version uint64) string {
+	return strconv.Quote(strconv.FormatUint(version, 10))
+}
+
+// Match tells if an If-None-Match header matches the version, using the weak comparison
+func Match(header string, version uint64) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == Format(version) {
+			return true
+		}
+	}
+	return false
+}
+
+// Version parses an If-Match header into the version it expects. A wildcard expects any version, returning 0
+func Version(header string) (uint64, error) {
+	header = strings.TrimSpace(header)
+	if header == "*" {
+		return 0, nil
+	}
+	unquoted, err := strconv.Unquote(header)
+	if err != nil {
+		return 0, ErrInvalid
+	}
+	version, err := strconv.ParseUint(unquoted, 10, 64)
+	if err != nil || version == 0 {
+		return 0, ErrInvalid
+	}
+	return version, nil
+}
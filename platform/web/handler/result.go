@@ -1,7 +1,12 @@
 package handler
 
-// Result holds the status of the processing of a handler
+import "io"
+
+// Result holds the status of the processing of a handler. Stream writes the body instead of Body,
+// for bodies written as they are produced, with their content type set in Headers
 type Result struct {
-	Status int
-	Body   any
+	Status  int
+	Body    any
+	Headers map[string]string
+	Stream  func(w io.Writer)
 }
@@ -6,7 +6,13 @@ import "github.com/gin-gonic/gin"
 func Wrapper(h HandleFunc) func(*gin.Context) {
 	return func(c *gin.Context) {
 		result := h(c)
+		for k, v := range result.Headers {
+			c.Header(k, v)
+		}
 		switch {
+		case result.Stream != nil:
+			c.Status(result.Status)
+			result.Stream(c.Writer)
 		case result.Body != nil:
 			c.JSON(result.Status, result.Body)
 		default:
@@ -0,0 +1,18 @@
+package identity
+
+import "github.com/gin-gonic/gin"
+
+const (
+	APIKeyHeader = "X-API-Key"
+	UserHeader   = "X-User-Id"
+)
+
+// APIKey returns the api key the caller sent, or empty if there is none
+func APIKey(ctx *gin.Context) string {
+	return ctx.GetHeader(APIKeyHeader)
+}
+
+// User returns the id of the user calling the api, or empty if it is anonymous
+func User(ctx *gin.Context) string {
+	return ctx.GetHeader(UserHeader)
+}
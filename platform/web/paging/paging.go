@@ -0,0 +1,40 @@
+package paging
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ribgsilva/note-api/platform/web/handler"
+	"strconv"
+)
+
+// Page is the slice of a listing the caller asked for
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+// Parse reads the limit and offset query params, using def as limit when it is not set, and capping it at max
+func Parse(ctx *gin.Context, def, max int) (Page, []handler.Error) {
+	var errs []handler.Error
+	p := Page{Limit: def}
+
+	if l := ctx.Query("limit"); l != "" {
+		limit, err := strconv.Atoi(l)
+		if err != nil || limit <= 0 {
+			errs = append(errs, handler.Error{Field: "limit", Message: "must be a positive number"})
+		}
+		p.Limit = limit
+	}
+	if p.Limit > max {
+		p.Limit = max
+	}
+
+	if o := ctx.Query("offset"); o != "" {
+		offset, err := strconv.Atoi(o)
+		if err != nil || offset < 0 {
+			errs = append(errs, handler.Error{Field: "offset", Message: "must not be negative"})
+		}
+		p.Offset = offset
+	}
+
+	return p, errs
+}
@@ -0,0 +1,32 @@
+package ratelimit
+
+import (
+	"math"
+	"time"
+)
+
+// bucket is the state of a token bucket, with the tokens available at a given moment
+type bucket struct {
+	tokens float64
+	at     time.Time
+}
+
+// decision is the outcome of trying to take a token from a bucket
+type decision struct {
+	allowed bool
+	tokens  float64
+}
+
+// take refills the bucket since its last use and tries to take a token from it
+func (b *bucket) take(now time.Time, capacity int, rate float64) decision {
+	elapsed := now.Sub(b.at).Seconds()
+	if elapsed > 0 {
+		b.tokens = math.Min(float64(capacity), b.tokens+elapsed*rate)
+		b.at = now
+	}
+	if b.tokens < 1 {
+		return decision{tokens: b.tokens}
+	}
+	b.tokens--
+	return decision{allowed: true, tokens: b.tokens}
+}
@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/ribgsilva/note-api/platform/web/identity"
+	"strings"
+)
+
+// KeyFunc identifies who is being limited, returning empty when it can not identify the caller
+type KeyFunc func(ctx *gin.Context) string
+
+// ByAPIKey limits by the api key sent in the request. The key is not authenticated by the api, so callers can
+// escape the limit sending a new key on every request; only use it behind a gateway that authenticates the key
+func ByAPIKey(ctx *gin.Context) string {
+	if k := identity.APIKey(ctx); k != "" {
+		return "apikey:" + k
+	}
+	return ""
+}
+
+// ByUser limits by the user calling the api. As with ByAPIKey, the user header is not authenticated by the api,
+// so only use it behind a gateway that authenticates the user
+func ByUser(ctx *gin.Context) string {
+	if u := identity.User(ctx); u != "" {
+		return "user:" + u
+	}
+	return ""
+}
+
+// ByIP limits by the client ip
+func ByIP(ctx *gin.Context) string {
+	return "ip:" + ctx.ClientIP()
+}
+
+// First uses the first key func able to identify the caller
+func First(keys ...KeyFunc) KeyFunc {
+	return func(ctx *gin.Context) string {
+		for _, k := range keys {
+			if key := k(ctx); key != "" {
+				return key
+			}
+		}
+		return ""
+	}
+}
+
+// ParseKeys builds a KeyFunc from a comma separated list of apikey, user and ip
+func ParseKeys(keys string) (KeyFunc, error) {
+	var funcs []KeyFunc
+	for _, k := range strings.Split(keys, ",") {
+		switch strings.TrimSpace(k) {
+		case "apikey":
+			funcs = append(funcs, ByAPIKey)
+		case "user":
+			funcs = append(funcs, ByUser)
+		case "ip":
+			funcs = append(funcs, ByIP)
+		case "":
+		default:
+			return nil, fmt.Errorf("unknown rate limit key %q", k)
+		}
+	}
+	if len(funcs) == 0 {
+		return ByIP, nil
+	}
+	return First(funcs...), nil
+}
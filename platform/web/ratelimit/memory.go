@@ -0,0 +1,45 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// memoryStore keeps the buckets of a single replica, used while redis is unavailable
+type memoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	swept   time.Time
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{buckets: make(map[string]*bucket)}
+}
+
+func (s *memoryStore) take(key string, now time.Time, capacity int, rate float64) decision {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now, capacity, rate)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(capacity), at: now}
+		s.buckets[key] = b
+	}
+	return b.take(now, capacity, rate)
+}
+
+// sweep drops the buckets that are already full again, so idle clients do not pile up in memory
+func (s *memoryStore) sweep(now time.Time, capacity int, rate float64) {
+	refill := time.Duration(float64(capacity) / rate * float64(time.Second))
+	if now.Sub(s.swept) < refill {
+		return
+	}
+	s.swept = now
+	for k, b := range s.buckets {
+		if now.Sub(b.at) >= refill {
+			delete(s.buckets, k)
+		}
+	}
+}
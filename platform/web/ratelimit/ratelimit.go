@@ -0,0 +1,93 @@
+package ratelimit
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"github.com/ribgsilva/note-api/platform/web/handler"
+	"go.uber.org/zap"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// redisRetry is how long the limiter keeps using the in memory buckets after redis fails
+const redisRetry = 5 * time.Second
+
+// Config holds the rate limit settings.
+// Limit requests are allowed every Period, with bursts of up to Burst requests
+type Config struct {
+	Log     *zap.SugaredLogger
+	Cache   *redis.Client
+	Timeout time.Duration
+	Prefix  string
+	Limit   int
+	Period  time.Duration
+	Burst   int
+	Key     KeyFunc
+	Now     func() time.Time
+}
+
+// New creates a token bucket rate limit middleware. Buckets are stored in redis, so the limits hold across replicas,
+// falling back to in memory buckets when redis can not be reached. Limit and Period must be positive
+func New(cfg Config) (gin.HandlerFunc, error) {
+	if cfg.Limit <= 0 {
+		return nil, fmt.Errorf("rate limit must be positive: %d", cfg.Limit)
+	}
+	if cfg.Period <= 0 {
+		return nil, fmt.Errorf("rate limit period must be positive: %s", cfg.Period)
+	}
+	if cfg.Burst <= 0 {
+		cfg.Burst = cfg.Limit
+	}
+	if cfg.Key == nil {
+		cfg.Key = ByIP
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	if cfg.Prefix == "" {
+		cfg.Prefix = "ratelimit"
+	}
+	rate := float64(cfg.Limit) / cfg.Period.Seconds()
+
+	remote := &redisStore{cache: cfg.Cache, timeout: cfg.Timeout}
+	local := newMemoryStore()
+
+	return func(ctx *gin.Context) {
+		key := cfg.Key(ctx)
+		if key == "" {
+			ctx.Next()
+			return
+		}
+
+		now := cfg.Now()
+		var d decision
+		var err error
+		if remote.available(now) {
+			d, err = remote.take(ctx, fmt.Sprintf("%s.%s", cfg.Prefix, key), now, cfg.Burst, rate)
+			if err != nil {
+				cfg.Log.Warnf("rate limit falling back to memory for %s: %s", redisRetry, err)
+				remote.failed(now)
+			}
+		}
+		if !remote.available(now) {
+			d = local.take(key, now, cfg.Burst, rate)
+		}
+
+		reset := math.Ceil((float64(cfg.Burst) - d.tokens) / rate)
+		ctx.Header("RateLimit-Limit", strconv.Itoa(cfg.Burst))
+		ctx.Header("RateLimit-Remaining", strconv.Itoa(int(d.tokens)))
+		ctx.Header("RateLimit-Reset", strconv.Itoa(int(reset)))
+
+		if !d.allowed {
+			retry := math.Ceil((1 - d.tokens) / rate)
+			ctx.Header("Retry-After", strconv.Itoa(int(retry)))
+			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, handler.Error{Message: "rate limit exceeded"})
+			return
+		}
+
+		ctx.Next()
+	}, nil
+}
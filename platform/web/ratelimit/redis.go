@@ -0,0 +1,69 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// takeScript is the redis side of bucket.take, so replicas share the same buckets
+var takeScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'at')
+local tokens = tonumber(state[1]) or capacity
+local at = tonumber(state[2]) or now
+if now > at then
+	tokens = math.min(capacity, tokens + (now - at) / 1000 * rate)
+	at = now
+end
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'at', at)
+redis.call('PEXPIRE', KEYS[1], ARGV[4])
+return {allowed, tostring(tokens)}
+`)
+
+type redisStore struct {
+	cache     *redis.Client
+	timeout   time.Duration
+	downUntil int64
+}
+
+// available tells if redis should be used, or if it failed too recently
+func (s *redisStore) available(now time.Time) bool {
+	return now.UnixNano() >= atomic.LoadInt64(&s.downUntil)
+}
+
+func (s *redisStore) failed(now time.Time) {
+	atomic.StoreInt64(&s.downUntil, now.Add(redisRetry).UnixNano())
+}
+
+func (s *redisStore) take(ctx context.Context, key string, now time.Time, capacity int, rate float64) (decision, error) {
+	tcCtx, tcCancel := context.WithTimeout(ctx, s.timeout)
+	defer tcCancel()
+
+	ttl := time.Duration(float64(capacity)/rate*float64(time.Second)) + time.Second
+	res, err := takeScript.Run(tcCtx, s.cache, []string{key}, capacity, rate, now.UnixMilli(), ttl.Milliseconds()).Slice()
+	if err != nil {
+		return decision{}, fmt.Errorf("failed to take rate limit token: %w", err)
+	}
+	if len(res) != 2 {
+		return decision{}, fmt.Errorf("unexpected rate limit response: %v", res)
+	}
+
+	allowed, _ := res[0].(int64)
+	raw, _ := res[1].(string)
+	tokens, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return decision{}, fmt.Errorf("failed to parse rate limit tokens: %w", err)
+	}
+	return decision{allowed: allowed == 1, tokens: tokens}, nil
+}
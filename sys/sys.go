@@ -3,7 +3,9 @@ package sys
 import (
 	"database/sql"
 	"github.com/go-redis/redis/v8"
+	"github.com/ribgsilva/note-api/platform/search"
 	"go.uber.org/zap"
+	"gocloud.dev/pubsub"
 	"time"
 )
 
@@ -15,6 +17,7 @@ var Configs struct {
 		ReadTimeout     time.Duration
 		WriteTimeout    time.Duration
 		IdleTimeout     time.Duration
+		RequireIfMatch  bool
 	}
 	Swagger struct {
 		Protocol string
@@ -22,8 +25,10 @@ var Configs struct {
 	}
 	Database struct {
 		ConnectionURL    string
+		Dialect          string
 		PingTimeout      time.Duration
 		OperationTimeout time.Duration
+		Migrate          bool
 	}
 	Cache struct {
 		ConnectionURL    string
@@ -33,12 +38,67 @@ var Configs struct {
 		OperationTimeout time.Duration
 		CacheTTL         time.Duration
 	}
+	RateLimit struct {
+		Enabled bool
+		Limit   int
+		Period  time.Duration
+		Burst   int
+		Keys    string
+	}
+	Trash struct {
+		Retention     time.Duration
+		PurgeInterval time.Duration
+	}
+	Links struct {
+		Secret     string
+		DefaultTTL time.Duration
+		MaxTTL     time.Duration
+	}
+	Batch struct {
+		MaxOperations       int
+		MaxBytes            int
+		MaxStreamOperations int
+	}
+	Import struct {
+		MaxBytes       int
+		QueueThreshold int
+		TopicURL       string
+	}
 	Messaging struct {
 		TopicName       string
+		SubscriptionURL string
+		DeadLetterURL   string
+		ReplyURLs       []string
+		StrictDecoding  bool
 		MaxWorkers      int
+		MinWorkers      int
+		Adaptive        bool
+		TargetLatency   time.Duration
+		MaxErrorRate    float64
+		AdaptInterval   time.Duration
+		HealthInterval  time.Duration
+		BatchSize       int
+		BatchWait       time.Duration
 		WaitTime        time.Duration
 		ShutdownTimeout time.Duration
 	}
+	Events struct {
+		TopicURL        string
+		Format          string
+		Source          string
+		ShutdownTimeout time.Duration
+		Outbox          bool
+		RelayInterval   time.Duration
+		RelayBatch      int
+		RelayMaxBackoff time.Duration
+		OutboxRetention time.Duration
+	}
+	Search struct {
+		Engine          string
+		IndexPath       string
+		Language        string
+		ReindexInterval time.Duration
+	}
 	NewRelic struct {
 		AppName           string
 		Licence           string
@@ -53,4 +113,13 @@ var R struct {
 	Log      *zap.SugaredLogger
 	Cache    *redis.Client
 	Database *sql.DB
+	// Events is the topic note changes are published to, nil when publishing is disabled
+	Events *pubsub.Topic
+	// Imports is the topic large imports are queued to, the one the messaging app subscribes to. Nil when every
+	// import is done on the request
+	Imports *pubsub.Topic
+	// DeadLetter is the topic of the messages the messaging app could not handle, nil when they are only logged
+	DeadLetter *pubsub.Topic
+	// Search is the embedded search index, nil when searching with the database full-text index
+	Search *search.Index
 }